@@ -4,6 +4,7 @@ go 1.24.0
 
 require (
 	github.com/emersion/go-imap/v2 v2.0.0-beta.4
+	github.com/mozillazg/go-pinyin v0.21.0
 	github.com/spf13/cobra v1.10.2
 	github.com/spf13/viper v1.21.0
 	modernc.org/sqlite v1.34.5
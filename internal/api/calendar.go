@@ -2,6 +2,7 @@ package api
 
 import (
 	"fmt"
+	"net/url"
 )
 
 // GetPrimaryCalendar retrieves the user's primary calendar
@@ -75,3 +76,161 @@ func (c *Client) ListCalendars() ([]Calendar, error) {
 
 	return allCalendars, nil
 }
+
+// SubscribeCalendar subscribes the user to a shared or public calendar
+func (c *Client) SubscribeCalendar(calendarID string) (*Calendar, error) {
+	var resp CalendarResponse
+
+	path := fmt.Sprintf("/calendar/v4/calendars/%s/subscribe", calendarID)
+	if err := c.Post(path, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("API error (code %d): %s", resp.Code, resp.Msg)
+	}
+
+	return resp.Data.Calendar, nil
+}
+
+// UnsubscribeCalendar removes a subscribed calendar from the user's list
+func (c *Client) UnsubscribeCalendar(calendarID string) error {
+	var resp BaseResponse
+
+	path := fmt.Sprintf("/calendar/v4/calendars/%s/unsubscribe", calendarID)
+	if err := c.Post(path, nil, &resp); err != nil {
+		return err
+	}
+
+	if resp.Code != 0 {
+		return fmt.Errorf("API error (code %d): %s", resp.Code, resp.Msg)
+	}
+
+	return nil
+}
+
+// CalendarRequest is the body for creating or updating a calendar
+type CalendarRequest struct {
+	Summary     string `json:"summary,omitempty"`
+	Description string `json:"description,omitempty"`
+	Permissions string `json:"permissions,omitempty"` // private, show_only_free_busy, public
+	Color       *int   `json:"color,omitempty"`
+}
+
+// CreateCalendar creates a shared calendar owned by the user
+func (c *Client) CreateCalendar(req *CalendarRequest) (*Calendar, error) {
+	var resp CalendarResponse
+
+	if err := c.Post("/calendar/v4/calendars", req, &resp); err != nil {
+		return nil, err
+	}
+
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("API error (code %d): %s", resp.Code, resp.Msg)
+	}
+
+	return resp.Data.Calendar, nil
+}
+
+// UpdateCalendar updates a calendar's summary, description, color or permissions
+func (c *Client) UpdateCalendar(calendarID string, req *CalendarRequest) (*Calendar, error) {
+	var resp CalendarResponse
+
+	path := fmt.Sprintf("/calendar/v4/calendars/%s", calendarID)
+	if err := c.Patch(path, req, &resp); err != nil {
+		return nil, err
+	}
+
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("API error (code %d): %s", resp.Code, resp.Msg)
+	}
+
+	return resp.Data.Calendar, nil
+}
+
+// ListCalendarACLs retrieves the access control list of a calendar
+func (c *Client) ListCalendarACLs(calendarID string) ([]CalendarACL, error) {
+	var acls []CalendarACL
+	var pageToken string
+
+	for {
+		params := url.Values{}
+		params.Set("user_id_type", "open_id")
+		params.Set("page_size", "50")
+		if pageToken != "" {
+			params.Set("page_token", pageToken)
+		}
+
+		var resp CalendarACLListResponse
+		path := fmt.Sprintf("/calendar/v4/calendars/%s/acls?%s", calendarID, params.Encode())
+		if err := c.Get(path, &resp); err != nil {
+			return nil, err
+		}
+
+		if resp.Code != 0 {
+			return nil, fmt.Errorf("API error (code %d): %s", resp.Code, resp.Msg)
+		}
+
+		acls = append(acls, resp.Data.ACLs...)
+
+		if !resp.Data.HasMore {
+			break
+		}
+		pageToken = resp.Data.PageToken
+	}
+
+	return acls, nil
+}
+
+// CreateCalendarACL grants a user a role on a calendar
+func (c *Client) CreateCalendarACL(calendarID, userID, role string) (*CalendarACL, error) {
+	var resp CalendarACLResponse
+
+	body := CalendarACL{
+		Role:  role,
+		Scope: ACLScope{Type: "user", UserID: userID},
+	}
+	path := fmt.Sprintf("/calendar/v4/calendars/%s/acls?user_id_type=open_id", calendarID)
+	if err := c.Post(path, body, &resp); err != nil {
+		return nil, err
+	}
+
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("API error (code %d): %s", resp.Code, resp.Msg)
+	}
+
+	return &resp.Data, nil
+}
+
+// DeleteCalendarACL removes an access control entry from a calendar
+func (c *Client) DeleteCalendarACL(calendarID, aclID string) error {
+	var resp BaseResponse
+
+	path := fmt.Sprintf("/calendar/v4/calendars/%s/acls/%s", calendarID, aclID)
+	if err := c.Delete(path, &resp); err != nil {
+		return err
+	}
+
+	if resp.Code != 0 {
+		return fmt.Errorf("API error (code %d): %s", resp.Code, resp.Msg)
+	}
+
+	return nil
+}
+
+// ConvertToOutputCalendar converts a Lark calendar to CLI output format
+func ConvertToOutputCalendar(c Calendar) OutputCalendar {
+	out := OutputCalendar{
+		ID:          c.CalendarID,
+		Name:        c.DisplayName(),
+		Description: c.Description,
+		Type:        c.Type,
+		Role:        c.Role,
+		Permissions: c.Permissions,
+		Primary:     c.Type == "primary",
+	}
+	if c.Color != 0 && c.Color != -1 {
+		out.Color = fmt.Sprintf("#%02X%02X%02X", (c.Color>>16)&0xFF, (c.Color>>8)&0xFF, c.Color&0xFF)
+	}
+	return out
+}
@@ -50,3 +50,37 @@ func (c *Client) SearchChats(opts *SearchChatsOptions) ([]Chat, bool, string, er
 
 	return resp.Data.Items, resp.Data.HasMore, resp.Data.PageToken, nil
 }
+
+// ListChatMembers retrieves all members of a chat, identified by open_id
+func (c *Client) ListChatMembers(chatID string) ([]ChatMember, error) {
+	var members []ChatMember
+	var pageToken string
+
+	for {
+		params := url.Values{}
+		params.Set("member_id_type", "open_id")
+		params.Set("page_size", "100")
+		if pageToken != "" {
+			params.Set("page_token", pageToken)
+		}
+
+		var resp ChatMembersResponse
+		path := fmt.Sprintf("/im/v1/chats/%s/members?%s", url.PathEscape(chatID), params.Encode())
+		if err := c.GetWithTenantToken(path, &resp); err != nil {
+			return nil, err
+		}
+
+		if resp.Code != 0 {
+			return nil, fmt.Errorf("API error %d: %s", resp.Code, resp.Msg)
+		}
+
+		members = append(members, resp.Data.Items...)
+
+		if !resp.Data.HasMore {
+			break
+		}
+		pageToken = resp.Data.PageToken
+	}
+
+	return members, nil
+}
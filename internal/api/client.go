@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"time"
 
@@ -246,3 +247,67 @@ func (c *Client) Download(path string) (io.ReadCloser, string, error) {
 	contentType := resp.Header.Get("Content-Type")
 	return resp.Body, contentType, nil
 }
+
+// formField is a field of a multipart upload, written in order
+type formField struct {
+	Name  string
+	Value string
+}
+
+// upload POSTs fields and a file as a multipart form and parses the JSON
+// response into result. With tenant set the tenant access token is used,
+// otherwise the user access token.
+func (c *Client) upload(path string, tenant bool, fields []formField, fileField, fileName string, file io.Reader, result interface{}) error {
+	var token string
+	if tenant {
+		if err := auth.EnsureValidTenantToken(); err != nil {
+			return err
+		}
+		token = auth.GetTenantTokenStore().GetAccessToken()
+	} else {
+		if err := auth.EnsureValidToken(); err != nil {
+			return err
+		}
+		token = auth.GetTokenStore().GetAccessToken()
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for _, f := range fields {
+		if err := writer.WriteField(f.Name, f.Value); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.Name, err)
+		}
+	}
+	part, err := writer.CreateFormFile(fileField, fileName)
+	if err != nil {
+		return fmt.Errorf("failed to create %s form: %w", fileField, err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return fmt.Errorf("failed to read %s: %w", fileName, err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to finalize upload: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", getBaseURL()+path, &buf)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if err := json.Unmarshal(respBody, result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
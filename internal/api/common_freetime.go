@@ -5,6 +5,19 @@ import (
 	"time"
 )
 
+// apiTimeFormat is the "YYYY-MM-DD HH:MM:SS" layout of common free time
+// requests and slots, and of some freebusy responses
+const apiTimeFormat = "2006-01-02 15:04:05"
+
+// ParseAPITime parses a calendar API timestamp: RFC 3339, or
+// "YYYY-MM-DD HH:MM:SS" in loc (the zone the query was made in)
+func ParseAPITime(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(apiTimeFormat, s, loc)
+}
+
 // CommonFreeTimeOptions configures a common free time query
 type CommonFreeTimeOptions struct {
 	UserIDs                 []string
@@ -21,8 +34,6 @@ type CommonFreeTimeOptions struct {
 // GetCommonFreeTime queries common free time slots for multiple users
 func (c *Client) GetCommonFreeTime(opts CommonFreeTimeOptions) ([]FreeTimeSlot, error) {
 	// Format time as "YYYY-MM-DD HH:MM:SS" (API requirement)
-	const apiTimeFormat = "2006-01-02 15:04:05"
-
 	req := CommonFreeTimeRequest{
 		UserIDs:                 opts.UserIDs,
 		StartTime:               opts.StartTime.Format(apiTimeFormat),
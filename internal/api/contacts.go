@@ -27,6 +27,31 @@ func (c *Client) GetUser(userID string, idType string) (*ContactUser, error) {
 	return resp.Data.User, nil
 }
 
+// BatchGetUsers retrieves up to 50 users by ID in one request
+// idType: "open_id", "union_id", or "user_id" (defaults to "open_id")
+func (c *Client) BatchGetUsers(userIDs []string, idType string) ([]ContactUser, error) {
+	if idType == "" {
+		idType = "open_id"
+	}
+	if len(userIDs) > 50 {
+		return nil, fmt.Errorf("at most 50 users per request, got %d", len(userIDs))
+	}
+
+	query := url.Values{"user_id_type": {idType}, "user_ids": userIDs}
+	path := "/contact/v3/users/batch?" + query.Encode()
+
+	var resp BatchGetUsersResponse
+	if err := c.GetWithTenantToken(path, &resp); err != nil {
+		return nil, err
+	}
+
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("API error %d: %s", resp.Code, resp.Msg)
+	}
+
+	return resp.Data.Items, nil
+}
+
 // ListUsersByDepartment retrieves users directly under a department
 // deptID: the department ID (use "0" for root department)
 // pageSize: number of results per page (max 50)
@@ -75,6 +100,38 @@ func (c *Client) GetDepartment(deptID string) (*Department, error) {
 	return resp.Data.Department, nil
 }
 
+// ListChildDepartments retrieves the sub-departments of a department
+// deptID: the department ID (use "0" for root department)
+// recursive: include all descendants, not only direct children
+// pageSize: number of results per page (max 50)
+// pageToken: pagination token (empty for first page)
+func (c *Client) ListChildDepartments(deptID string, recursive bool, pageSize int, pageToken string) ([]Department, bool, string, error) {
+	if pageSize <= 0 {
+		pageSize = 50
+	}
+	if pageSize > 50 {
+		pageSize = 50
+	}
+
+	path := fmt.Sprintf("/contact/v3/departments/%s/children?department_id_type=open_department_id&user_id_type=open_id&fetch_child=%t&page_size=%d",
+		url.PathEscape(deptID), recursive, pageSize)
+
+	if pageToken != "" {
+		path += "&page_token=" + url.QueryEscape(pageToken)
+	}
+
+	var resp ListChildDepartmentsResponse
+	if err := c.GetWithTenantToken(path, &resp); err != nil {
+		return nil, false, "", err
+	}
+
+	if resp.Code != 0 {
+		return nil, false, "", fmt.Errorf("API error %d: %s", resp.Code, resp.Msg)
+	}
+
+	return resp.Data.Items, resp.Data.HasMore, resp.Data.PageToken, nil
+}
+
 // SearchDepartmentsRequest is the request body for department search
 type SearchDepartmentsRequest struct {
 	Query string `json:"query"`
@@ -122,6 +122,29 @@ func (c *Client) CreateDocumentBlocks(documentID, blockID string, children []Doc
 	return resp.Data.Children, resp.Data.DocumentRevisionID, nil
 }
 
+// GetDocumentURL returns the web link of a docx document
+// documentID: the document ID
+func (c *Client) GetDocumentURL(documentID string) (string, error) {
+	req := DocumentMetasRequest{
+		RequestDocs: []DocumentMetaRef{{DocToken: documentID, DocType: "docx"}},
+		WithURL:     true,
+	}
+
+	var resp DocumentMetasResponse
+	if err := c.Post("/drive/v1/metas/batch_query", req, &resp); err != nil {
+		return "", err
+	}
+
+	if resp.Code != 0 {
+		return "", fmt.Errorf("API error %d: %s", resp.Code, resp.Msg)
+	}
+	if len(resp.Data.Metas) == 0 || resp.Data.Metas[0].URL == "" {
+		return "", fmt.Errorf("no URL returned for document %s", documentID)
+	}
+
+	return resp.Data.Metas[0].URL, nil
+}
+
 // ListFolderItems lists items in a Lark Drive folder
 // folderToken: folder token (empty for root cloud space)
 // pageSize: number of items per page (max 200)
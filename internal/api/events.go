@@ -3,6 +3,8 @@ package api
 import (
 	"fmt"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strconv"
 	"time"
 
@@ -90,6 +92,47 @@ func (c *Client) ListEvents(opts ListEventsOptions) ([]Event, error) {
 	return events, nil
 }
 
+// ListEventChanges lists events changed since syncToken, including cancelled
+// ones, and returns the token for the next call. Recurring events come back as
+// the series plus any exceptions, not as expanded instances. With an empty
+// syncToken, every event from anchor onwards is returned along with a first
+// token.
+func (c *Client) ListEventChanges(calendarID, syncToken string, anchor time.Time) ([]Event, string, error) {
+	var allEvents []Event
+	var pageToken string
+
+	for {
+		params := url.Values{}
+		params.Set("page_size", "500")
+		if syncToken != "" {
+			params.Set("sync_token", syncToken)
+		} else if !anchor.IsZero() {
+			params.Set("anchor_time", strconv.FormatInt(anchor.Unix(), 10))
+		}
+		if pageToken != "" {
+			params.Set("page_token", pageToken)
+		}
+
+		path := fmt.Sprintf("/calendar/v4/calendars/%s/events?%s", calendarID, params.Encode())
+
+		var resp EventListResponse
+		if err := c.Get(path, &resp); err != nil {
+			return nil, "", err
+		}
+
+		if resp.Code != 0 {
+			return nil, "", fmt.Errorf("API error (code %d): %s", resp.Code, resp.Msg)
+		}
+
+		allEvents = append(allEvents, resp.Data.Items...)
+
+		if !resp.Data.HasMore {
+			return allEvents, resp.Data.SyncToken, nil
+		}
+		pageToken = resp.Data.PageToken
+	}
+}
+
 // getInstanceView fetches event instances for a time range (max 40 days)
 func (c *Client) getInstanceView(calendarID string, startTime, endTime time.Time) ([]InstanceViewItem, error) {
 	params := url.Values{}
@@ -185,18 +228,20 @@ func (c *Client) GetEvent(calendarID, eventID string) (*Event, error) {
 
 // CreateEventRequest contains the data for creating an event
 type CreateEventRequest struct {
-	Summary         string     `json:"summary,omitempty"`
-	Description     string     `json:"description,omitempty"`
-	StartTime       *TimeInfo  `json:"start_time"`
-	EndTime         *TimeInfo  `json:"end_time"`
-	Location        *Location  `json:"location,omitempty"`
-	Color           int        `json:"color,omitempty"`
-	Reminders       []Reminder `json:"reminders,omitempty"`
-	Recurrence      string     `json:"recurrence,omitempty"`
-	Vchat           *Vchat     `json:"vchat,omitempty"`
-	Visibility      string     `json:"visibility,omitempty"`
-	AttendeeAbility string     `json:"attendee_ability,omitempty"`
-	NeedNotify      *bool      `json:"need_notification,omitempty"`
+	Summary         string       `json:"summary,omitempty"`
+	Description     string       `json:"description,omitempty"`
+	StartTime       *TimeInfo    `json:"start_time"`
+	EndTime         *TimeInfo    `json:"end_time"`
+	Location        *Location    `json:"location,omitempty"`
+	Color           int          `json:"color,omitempty"`
+	Reminders       []Reminder   `json:"reminders,omitempty"`
+	Recurrence      string       `json:"recurrence,omitempty"`
+	Vchat           *Vchat       `json:"vchat,omitempty"`
+	Visibility      string       `json:"visibility,omitempty"`
+	AttendeeAbility string       `json:"attendee_ability,omitempty"`
+	FreeBusyStatus  string       `json:"free_busy_status,omitempty"`
+	NeedNotify      *bool        `json:"need_notification,omitempty"`
+	Attachments     []Attachment `json:"attachments,omitempty"`
 }
 
 // CreateEvent creates a new event
@@ -217,16 +262,19 @@ func (c *Client) CreateEvent(calendarID string, req *CreateEventRequest) (*Event
 
 // UpdateEventRequest contains the data for updating an event
 type UpdateEventRequest struct {
-	Summary         string     `json:"summary,omitempty"`
-	Description     string     `json:"description,omitempty"`
-	StartTime       *TimeInfo  `json:"start_time,omitempty"`
-	EndTime         *TimeInfo  `json:"end_time,omitempty"`
-	Location        *Location  `json:"location,omitempty"`
-	Color           *int       `json:"color,omitempty"`
-	Reminders       []Reminder `json:"reminders,omitempty"`
-	Visibility      string     `json:"visibility,omitempty"`
-	AttendeeAbility string     `json:"attendee_ability,omitempty"`
-	NeedNotify      *bool      `json:"need_notification,omitempty"`
+	Summary         string       `json:"summary,omitempty"`
+	Description     string       `json:"description,omitempty"`
+	StartTime       *TimeInfo    `json:"start_time,omitempty"`
+	EndTime         *TimeInfo    `json:"end_time,omitempty"`
+	Location        *Location    `json:"location,omitempty"`
+	Color           *int         `json:"color,omitempty"`
+	Reminders       []Reminder   `json:"reminders,omitempty"`
+	Recurrence      string       `json:"recurrence,omitempty"`
+	Vchat           *Vchat       `json:"vchat,omitempty"`
+	Visibility      string       `json:"visibility,omitempty"`
+	AttendeeAbility string       `json:"attendee_ability,omitempty"`
+	NeedNotify      *bool        `json:"need_notification,omitempty"`
+	Attachments     []Attachment `json:"attachments,omitempty"` // Replaces the event's attachments
 }
 
 // UpdateEvent updates an existing event
@@ -291,6 +339,54 @@ func (c *Client) SearchEvents(calendarID, query string, startTime, endTime time.
 	return resp.Data.Items, nil
 }
 
+// maxAttachmentSize is the largest file the drive upload_all API accepts (20 MB)
+const maxAttachmentSize = 20 << 20
+
+// UploadEventAttachment uploads a file to a calendar so it can be attached
+// to events on it
+func (c *Client) UploadEventAttachment(calendarID, filePath string) (*Attachment, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open attachment: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	if info.Size() > maxAttachmentSize {
+		return nil, fmt.Errorf("%s is larger than 20 MB", filepath.Base(filePath))
+	}
+
+	name := filepath.Base(filePath)
+	size := strconv.FormatInt(info.Size(), 10)
+	fields := []formField{
+		{"file_name", name},
+		{"parent_type", "calendar"},
+		{"parent_node", calendarID},
+		{"size", size},
+	}
+	var uploadResp UploadMediaResponse
+	if err := c.upload("/drive/v1/medias/upload_all", false, fields, "file", name, file, &uploadResp); err != nil {
+		return nil, err
+	}
+
+	if uploadResp.Code != 0 {
+		return nil, fmt.Errorf("API error (code %d): %s", uploadResp.Code, uploadResp.Msg)
+	}
+
+	if uploadResp.Data.FileToken == "" {
+		return nil, fmt.Errorf("API error: missing file_token")
+	}
+
+	return &Attachment{
+		FileToken: uploadResp.Data.FileToken,
+		FileSize:  size,
+		Name:      name,
+	}, nil
+}
+
 // ConvertToOutputEvent converts a Lark API event to CLI output format
 func ConvertToOutputEvent(e Event) OutputEvent {
 	tz := config.GetTimezone()
@@ -305,6 +401,9 @@ func ConvertToOutputEvent(e Event) OutputEvent {
 		Description: e.Description,
 		Visibility:  e.Visibility,
 		Recurrence:  e.Recurrence,
+		RecurringID: e.RecurringEventID,
+		IsException: e.IsException,
+		FreeBusy:    e.FreeBusyStatus,
 	}
 
 	// Convert start time
@@ -349,6 +448,13 @@ func ConvertToOutputEvent(e Event) OutputEvent {
 		out.MeetingURL = e.Vchat.MeetingURL
 	}
 
+	for _, a := range e.Attachments {
+		if a.IsDeleted {
+			continue
+		}
+		out.Attachments = append(out.Attachments, OutputAttachment{Name: a.Name, FileToken: a.FileToken, Size: a.FileSize})
+	}
+
 	// Convert attendees
 	for _, att := range e.Attendees {
 		outAtt := OutputAttendee{
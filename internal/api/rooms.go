@@ -0,0 +1,135 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// rootRoomLevelID is the room level ID of the top of the room hierarchy
+const rootRoomLevelID = "0"
+
+// ListRoomLevels returns the direct children of a room level.
+// An empty parentID lists the top level (usually buildings).
+func (c *Client) ListRoomLevels(parentID string) ([]RoomLevel, error) {
+	if parentID == "" {
+		parentID = rootRoomLevelID
+	}
+
+	var levels []RoomLevel
+	pageToken := ""
+	for {
+		params := url.Values{}
+		params.Set("room_level_id", parentID)
+		params.Set("page_size", "100")
+		if pageToken != "" {
+			params.Set("page_token", pageToken)
+		}
+
+		var resp RoomLevelListResponse
+		if err := c.GetWithTenantToken("/vc/v1/room_levels?"+params.Encode(), &resp); err != nil {
+			return nil, err
+		}
+		if resp.Code != 0 {
+			return nil, fmt.Errorf("API error %d: %s", resp.Code, resp.Msg)
+		}
+
+		levels = append(levels, resp.Data.Items...)
+		if !resp.Data.HasMore || resp.Data.PageToken == "" {
+			break
+		}
+		pageToken = resp.Data.PageToken
+	}
+
+	return levels, nil
+}
+
+// GetRoomLevels fetches room levels by ID
+func (c *Client) GetRoomLevels(levelIDs []string) ([]RoomLevel, error) {
+	const batchSize = 50
+
+	var levels []RoomLevel
+	for i := 0; i < len(levelIDs); i += batchSize {
+		end := i + batchSize
+		if end > len(levelIDs) {
+			end = len(levelIDs)
+		}
+
+		body := map[string]interface{}{"level_ids": levelIDs[i:end]}
+		var resp RoomLevelListResponse
+		if err := c.PostWithTenantToken("/vc/v1/room_levels/mget", body, &resp); err != nil {
+			return nil, err
+		}
+		if resp.Code != 0 {
+			return nil, fmt.Errorf("API error %d: %s", resp.Code, resp.Msg)
+		}
+		levels = append(levels, resp.Data.Items...)
+	}
+
+	return levels, nil
+}
+
+// ListRooms returns the rooms directly under a room level.
+// An empty levelID lists rooms at the top level.
+func (c *Client) ListRooms(levelID string) ([]Room, error) {
+	if levelID == "" {
+		levelID = rootRoomLevelID
+	}
+
+	var rooms []Room
+	pageToken := ""
+	for {
+		params := url.Values{}
+		params.Set("room_level_id", levelID)
+		params.Set("page_size", "100")
+		params.Set("user_id_type", "open_id")
+		if pageToken != "" {
+			params.Set("page_token", pageToken)
+		}
+
+		var resp RoomListResponse
+		if err := c.GetWithTenantToken("/vc/v1/rooms?"+params.Encode(), &resp); err != nil {
+			return nil, err
+		}
+		if resp.Code != 0 {
+			return nil, fmt.Errorf("API error %d: %s", resp.Code, resp.Msg)
+		}
+
+		rooms = append(rooms, resp.Data.Rooms...)
+		if !resp.Data.HasMore || resp.Data.PageToken == "" {
+			break
+		}
+		pageToken = resp.Data.PageToken
+	}
+
+	return rooms, nil
+}
+
+// SearchRooms searches meeting rooms by name. When searchLevelName is set,
+// building and floor names are matched as well.
+func (c *Client) SearchRooms(keyword, levelID string, searchLevelName bool) ([]Room, error) {
+	req := RoomSearchRequest{
+		Keyword:         keyword,
+		RoomLevelID:     levelID,
+		SearchLevelName: searchLevelName,
+		PageSize:        100,
+	}
+
+	var rooms []Room
+	for {
+		var resp RoomListResponse
+		if err := c.PostWithTenantToken("/vc/v1/rooms/search?user_id_type=open_id", req, &resp); err != nil {
+			return nil, err
+		}
+		if resp.Code != 0 {
+			return nil, fmt.Errorf("API error %d: %s", resp.Code, resp.Msg)
+		}
+
+		rooms = append(rooms, resp.Data.Rooms...)
+		if !resp.Data.HasMore || resp.Data.PageToken == "" {
+			break
+		}
+		req.PageToken = resp.Data.PageToken
+	}
+
+	return rooms, nil
+}
@@ -25,6 +25,14 @@ type Vchat struct {
 	Description string `json:"description,omitempty"`
 }
 
+// Attachment is a file attached to an event
+type Attachment struct {
+	FileToken string `json:"file_token"`
+	FileSize  string `json:"file_size,omitempty"`
+	IsDeleted bool   `json:"is_deleted,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
 // Reminder represents an event reminder
 type Reminder struct {
 	Minutes int `json:"minutes"`
@@ -35,6 +43,8 @@ type Attendee struct {
 	Type            string `json:"type,omitempty"` // user, chat, resource, third_party
 	AttendeeID      string `json:"attendee_id,omitempty"`
 	UserID          string `json:"user_id,omitempty"`
+	ChatID          string `json:"chat_id,omitempty"`
+	RoomID          string `json:"room_id,omitempty"`
 	DisplayName     string `json:"display_name,omitempty"`
 	RsvpStatus      string `json:"rsvp_status,omitempty"` // needs_action, accept, tentative, decline
 	IsOptional      bool   `json:"is_optional,omitempty"`
@@ -45,36 +55,49 @@ type Attendee struct {
 
 // Event represents a calendar event from Lark API
 type Event struct {
-	EventID             string     `json:"event_id,omitempty"`
-	OrganizerCalendarID string     `json:"organizer_calendar_id,omitempty"`
-	Summary             string     `json:"summary,omitempty"`
-	Description         string     `json:"description,omitempty"`
-	StartTime           *TimeInfo  `json:"start_time,omitempty"`
-	EndTime             *TimeInfo  `json:"end_time,omitempty"`
-	Vchat               *Vchat     `json:"vchat,omitempty"`
-	Visibility          string     `json:"visibility,omitempty"`
-	AttendeeAbility     string     `json:"attendee_ability,omitempty"`
-	FreeBusyStatus      string     `json:"free_busy_status,omitempty"`
-	Location            *Location  `json:"location,omitempty"`
-	Color               int        `json:"color,omitempty"`
-	Reminders           []Reminder `json:"reminders,omitempty"`
-	Recurrence          string     `json:"recurrence,omitempty"`
-	Status              string     `json:"status,omitempty"` // tentative, confirmed, cancelled
-	IsException         bool       `json:"is_exception,omitempty"`
-	RecurringEventID    string     `json:"recurring_event_id,omitempty"`
-	CreateTime          string     `json:"create_time,omitempty"`
-	Attendees           []Attendee `json:"attendees,omitempty"`
-	HasMoreAttendee     bool       `json:"has_more_attendee,omitempty"`
+	EventID             string       `json:"event_id,omitempty"`
+	OrganizerCalendarID string       `json:"organizer_calendar_id,omitempty"`
+	Summary             string       `json:"summary,omitempty"`
+	Description         string       `json:"description,omitempty"`
+	StartTime           *TimeInfo    `json:"start_time,omitempty"`
+	EndTime             *TimeInfo    `json:"end_time,omitempty"`
+	Vchat               *Vchat       `json:"vchat,omitempty"`
+	Visibility          string       `json:"visibility,omitempty"`
+	AttendeeAbility     string       `json:"attendee_ability,omitempty"`
+	FreeBusyStatus      string       `json:"free_busy_status,omitempty"`
+	Location            *Location    `json:"location,omitempty"`
+	Color               int          `json:"color,omitempty"`
+	Reminders           []Reminder   `json:"reminders,omitempty"`
+	Recurrence          string       `json:"recurrence,omitempty"`
+	Status              string       `json:"status,omitempty"` // tentative, confirmed, cancelled
+	IsException         bool         `json:"is_exception,omitempty"`
+	RecurringEventID    string       `json:"recurring_event_id,omitempty"`
+	CreateTime          string       `json:"create_time,omitempty"`
+	Attendees           []Attendee   `json:"attendees,omitempty"`
+	HasMoreAttendee     bool         `json:"has_more_attendee,omitempty"`
+	Attachments         []Attachment `json:"attachments,omitempty"`
 }
 
 // Calendar represents a Lark calendar
 type Calendar struct {
-	CalendarID  string `json:"calendar_id"`
-	Summary     string `json:"summary,omitempty"`
-	Description string `json:"description,omitempty"`
-	Type        string `json:"type,omitempty"` // primary, shared, google, exchange, resource
-	Color       int    `json:"color,omitempty"`
-	Role        string `json:"role,omitempty"` // owner, writer, reader, free_busy_reader
+	CalendarID   string `json:"calendar_id"`
+	Summary      string `json:"summary,omitempty"`
+	SummaryAlias string `json:"summary_alias,omitempty"` // The user's own name for the calendar
+	Description  string `json:"description,omitempty"`
+	Type         string `json:"type,omitempty"` // primary, shared, google, exchange, resource
+	Color        int    `json:"color,omitempty"`
+	Role         string `json:"role,omitempty"`        // owner, writer, reader, free_busy_reader
+	Permissions  string `json:"permissions,omitempty"` // private, show_only_free_busy, public
+	IsDeleted    bool   `json:"is_deleted,omitempty"`
+	IsThirdParty bool   `json:"is_third_party,omitempty"`
+}
+
+// DisplayName returns the name the user sees for the calendar
+func (c Calendar) DisplayName() string {
+	if c.SummaryAlias != "" {
+		return c.SummaryAlias
+	}
+	return c.Summary
 }
 
 // --- API Response Types ---
@@ -106,7 +129,7 @@ type CalendarListResponse struct {
 	Data struct {
 		HasMore   bool       `json:"has_more"`
 		PageToken string     `json:"page_token,omitempty"`
-		Calendars []Calendar `json:"calendars,omitempty"`
+		Calendars []Calendar `json:"calendar_list,omitempty"`
 	} `json:"data,omitempty"`
 }
 
@@ -216,32 +239,69 @@ type OutputAttendee struct {
 	Email       string `json:"email,omitempty"` // for third_party type
 }
 
+// OutputRsvpPerson is one attendee in an RSVP summary, with group chats
+// expanded into their members
+type OutputRsvpPerson struct {
+	UserID      string `json:"user_id,omitempty"`
+	Name        string `json:"name"`
+	Email       string `json:"email,omitempty"`
+	RsvpStatus  string `json:"rsvp_status"`
+	IsOptional  bool   `json:"is_optional,omitempty"`
+	IsOrganizer bool   `json:"is_organizer,omitempty"`
+	IsExternal  bool   `json:"is_external,omitempty"`
+	Via         string `json:"via,omitempty"` // Group chat they were invited through
+}
+
 // OutputEvent is the simplified event format for CLI output
 type OutputEvent struct {
-	ID            string           `json:"id"`
-	Summary       string           `json:"summary"`
-	Description   string           `json:"description,omitempty"`
-	Start         string           `json:"start"` // ISO 8601 with timezone
-	End           string           `json:"end"`   // ISO 8601 with timezone
-	AllDay        bool             `json:"all_day,omitempty"`
-	Location      string           `json:"location,omitempty"`
-	Color         string           `json:"color,omitempty"`      // Hex color like "#579FFF", empty if using calendar color
-	Visibility    string           `json:"visibility,omitempty"` // default, public, private
-	Organizer     string           `json:"organizer,omitempty"`
-	Attendees     []OutputAttendee `json:"attendees,omitempty"`
-	MeetingURL    string           `json:"meeting_url,omitempty"`
-	Recurrence    string           `json:"recurrence,omitempty"`
-	ConflictsWith []string         `json:"conflicts_with,omitempty"`
-	RsvpStatus    string           `json:"rsvp_status,omitempty"` // User's RSVP status: needs_action, accept, tentative, decline
+	ID            string             `json:"id"`
+	Summary       string             `json:"summary"`
+	Description   string             `json:"description,omitempty"`
+	Start         string             `json:"start"` // ISO 8601 with timezone
+	End           string             `json:"end"`   // ISO 8601 with timezone
+	AllDay        bool               `json:"all_day,omitempty"`
+	Location      string             `json:"location,omitempty"`
+	Color         string             `json:"color,omitempty"`      // Hex color like "#579FFF", empty if using calendar color
+	Visibility    string             `json:"visibility,omitempty"` // default, public, private
+	Organizer     string             `json:"organizer,omitempty"`
+	Attendees     []OutputAttendee   `json:"attendees,omitempty"`
+	MeetingURL    string             `json:"meeting_url,omitempty"`
+	Attachments   []OutputAttachment `json:"attachments,omitempty"`
+	Recurrence    string             `json:"recurrence,omitempty"`         // RRULE, e.g. "FREQ=WEEKLY;COUNT=10"
+	RecurringID   string             `json:"recurring_event_id,omitempty"` // Series ID when this is an occurrence
+	IsException   bool               `json:"is_exception,omitempty"`       // Occurrence modified from its series
+	ConflictsWith []string           `json:"conflicts_with,omitempty"`
+	RsvpStatus    string             `json:"rsvp_status,omitempty"`      // User's RSVP status: needs_action, accept, tentative, decline
+	FreeBusy      string             `json:"free_busy_status,omitempty"` // busy or free
+	CalendarID    string             `json:"calendar_id,omitempty"`      // Set when listing across calendars
+	CalendarName  string             `json:"calendar,omitempty"`
+}
+
+// OutputAttachment is an event attachment for CLI output
+type OutputAttachment struct {
+	Name      string `json:"name"`
+	FileToken string `json:"file_token"`
+	Size      string `json:"size,omitempty"` // Bytes
 }
 
 // Conflict represents a detected scheduling conflict
 type Conflict struct {
-	Type                  string   `json:"type"`                              // "overlap" or "insufficient_buffer"
-	EventIDs              []string `json:"events"`                            // IDs of conflicting events
-	OverlapMinutes        int      `json:"overlap_minutes,omitempty"`         // Duration of overlap
-	GapMinutes            int      `json:"gap_minutes,omitempty"`             // Gap between events (for buffer)
-	RequiredBufferMinutes int      `json:"required_buffer_minutes,omitempty"` // Required buffer
+	Type                  string               `json:"type"`                              // "overlap" or "insufficient_buffer"
+	EventIDs              []string             `json:"events"`                            // IDs of conflicting events
+	Severity              string               `json:"severity,omitempty"`                // high, medium, low (from RSVP status)
+	OverlapMinutes        int                  `json:"overlap_minutes,omitempty"`         // Duration of overlap
+	GapMinutes            int                  `json:"gap_minutes,omitempty"`             // Gap between events (for buffer)
+	RequiredBufferMinutes int                  `json:"required_buffer_minutes,omitempty"` // Required buffer
+	Suggestions           []ConflictSuggestion `json:"suggestions,omitempty"`
+}
+
+// ConflictSuggestion is a proposed way to resolve a conflict
+type ConflictSuggestion struct {
+	Action  string `json:"action"`          // move, decline
+	EventID string `json:"event_id"`        // Event the action applies to
+	Start   string `json:"start,omitempty"` // New start for move
+	End     string `json:"end,omitempty"`
+	Reason  string `json:"reason"`
 }
 
 // OutputEventList is the list events response for CLI
@@ -250,6 +310,33 @@ type OutputEventList struct {
 	Count        int           `json:"count"`
 	Conflicts    []Conflict    `json:"conflicts,omitempty"`
 	HasConflicts bool          `json:"has_conflicts,omitempty"`
+	Cache        *OutputCache  `json:"cache,omitempty"` // Set when read with --offline
+}
+
+// OutputCache describes the local calendar cache an offline result came from
+type OutputCache struct {
+	LastSync  string `json:"last_sync"`
+	Freshness string `json:"freshness"`
+}
+
+// OutputConflictReport is the conflict report response for CLI
+type OutputConflictReport struct {
+	From      string        `json:"from"`
+	To        string        `json:"to"`
+	Calendars []string      `json:"calendars"`
+	Events    []OutputEvent `json:"events"` // Only events involved in a conflict
+	Conflicts []Conflict    `json:"conflicts"`
+	Count     int           `json:"count"`
+}
+
+// OutputBlock is a focus or out-of-office block created (or planned) for CLI output
+type OutputBlock struct {
+	EventID string  `json:"event_id,omitempty"` // Empty in dry runs
+	Summary string  `json:"summary"`
+	Start   string  `json:"start"`
+	End     string  `json:"end"`
+	Hours   float64 `json:"hours,omitempty"`
+	AllDay  bool    `json:"all_day,omitempty"`
 }
 
 // OutputError is the error response format for CLI
@@ -269,12 +356,65 @@ type OutputAuthStatus struct {
 	ScopeGroups   map[string]bool `json:"scope_groups,omitempty"`
 }
 
+// OutputCalendar is a calendar for CLI output
+type OutputCalendar struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Role        string `json:"role,omitempty"`
+	Permissions string `json:"permissions,omitempty"`
+	Color       string `json:"color,omitempty"`
+	Primary     bool   `json:"primary,omitempty"`
+}
+
 // OutputSuccess is a generic success response
 type OutputSuccess struct {
 	Success bool   `json:"success"`
 	Message string `json:"message,omitempty"`
 }
 
+// --- Calendar ACL Types ---
+
+// ACLScope identifies who an ACL entry applies to
+type ACLScope struct {
+	Type   string `json:"type"` // user
+	UserID string `json:"user_id,omitempty"`
+}
+
+// CalendarACL is an access control entry on a calendar
+type CalendarACL struct {
+	ACLID string   `json:"acl_id,omitempty"`
+	Role  string   `json:"role"` // free_busy_reader, reader, writer, owner
+	Scope ACLScope `json:"scope"`
+}
+
+// CalendarACLResponse is the response from creating an ACL entry
+type CalendarACLResponse struct {
+	BaseResponse
+	Data CalendarACL `json:"data,omitempty"`
+}
+
+// CalendarACLListResponse is the response from listing a calendar's ACL
+type CalendarACLListResponse struct {
+	BaseResponse
+	Data struct {
+		HasMore   bool          `json:"has_more"`
+		PageToken string        `json:"page_token,omitempty"`
+		ACLs      []CalendarACL `json:"acls,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+// OutputCalendarACL is an ACL entry for CLI output
+type OutputCalendarACL struct {
+	ACLID  string `json:"acl_id"`
+	Role   string `json:"role"`
+	Type   string `json:"type"`
+	UserID string `json:"user_id,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Email  string `json:"email,omitempty"`
+}
+
 // --- Freebusy Types ---
 
 // FreebusyRequest is the request body for freebusy API
@@ -321,6 +461,93 @@ type OutputFreebusyPeriod struct {
 	End   string `json:"end"`
 }
 
+// --- Meeting Room Types ---
+
+// Room represents a meeting room from the VC room API
+type Room struct {
+	RoomID       string       `json:"room_id"`
+	Name         string       `json:"name"`
+	Capacity     int          `json:"capacity"`
+	Description  string       `json:"description,omitempty"`
+	DisplayID    string       `json:"display_id,omitempty"`
+	CustomRoomID string       `json:"custom_room_id,omitempty"`
+	RoomLevelID  string       `json:"room_level_id"`
+	Path         []string     `json:"path,omitempty"` // Room level IDs from the root down
+	RoomStatus   *RoomStatus  `json:"room_status,omitempty"`
+	Device       []RoomDevice `json:"device,omitempty"`
+}
+
+// RoomStatus describes whether a meeting room can be booked
+type RoomStatus struct {
+	Status         bool   `json:"status"` // true if the room is enabled
+	ScheduleStatus bool   `json:"schedule_status"`
+	DisableReason  string `json:"disable_reason,omitempty"`
+}
+
+// RoomDevice is a piece of equipment installed in a meeting room
+type RoomDevice struct {
+	Name string `json:"name"`
+}
+
+// RoomLevel is a node in the meeting room hierarchy (e.g. a building or floor)
+type RoomLevel struct {
+	RoomLevelID   string   `json:"room_level_id"`
+	Name          string   `json:"name"`
+	ParentID      string   `json:"parent_id"`
+	Path          []string `json:"path,omitempty"`
+	HasChild      bool     `json:"has_child"`
+	CustomGroupID string   `json:"custom_group_id,omitempty"`
+}
+
+// RoomListResponse is the response from the list and search room APIs
+type RoomListResponse struct {
+	BaseResponse
+	Data struct {
+		Rooms     []Room `json:"rooms"`
+		PageToken string `json:"page_token"`
+		HasMore   bool   `json:"has_more"`
+	} `json:"data"`
+}
+
+// RoomLevelListResponse is the response from the list room levels API
+type RoomLevelListResponse struct {
+	BaseResponse
+	Data struct {
+		Items     []RoomLevel `json:"items"`
+		PageToken string      `json:"page_token"`
+		HasMore   bool        `json:"has_more"`
+	} `json:"data"`
+}
+
+// RoomSearchRequest is the request body for the room search API
+type RoomSearchRequest struct {
+	Keyword         string `json:"keyword,omitempty"`
+	RoomLevelID     string `json:"room_level_id,omitempty"`
+	SearchLevelName bool   `json:"search_level_name,omitempty"`
+	PageSize        int    `json:"page_size,omitempty"`
+	PageToken       string `json:"page_token,omitempty"`
+}
+
+// OutputRoom is a meeting room for CLI output
+type OutputRoom struct {
+	RoomID      string   `json:"room_id"`
+	Name        string   `json:"name"`
+	Capacity    int      `json:"capacity"`
+	Building    string   `json:"building,omitempty"`
+	Floor       string   `json:"floor,omitempty"`
+	Equipment   []string `json:"equipment,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Disabled    bool     `json:"disabled,omitempty"`
+}
+
+// OutputRoomLevel is a room level for CLI output
+type OutputRoomLevel struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	ParentID string `json:"parent_id,omitempty"`
+	HasChild bool   `json:"has_child,omitempty"`
+}
+
 // --- User Lookup Types ---
 
 // UserLookupRequest is the request body for user lookup API
@@ -440,6 +667,7 @@ type ContactUser struct {
 	EmployeeType    int                `json:"employee_type,omitempty"`
 	JobTitle        string             `json:"job_title,omitempty"`
 	EnterpriseEmail string             `json:"enterprise_email,omitempty"`
+	TimeZone        string             `json:"time_zone,omitempty"`
 }
 
 // DepartmentI18nName represents internationalized department names
@@ -488,6 +716,14 @@ type FindByDepartmentResponse struct {
 	} `json:"data,omitempty"`
 }
 
+// BatchGetUsersResponse is the response from GET /contact/v3/users/batch
+type BatchGetUsersResponse struct {
+	BaseResponse
+	Data struct {
+		Items []ContactUser `json:"items,omitempty"`
+	} `json:"data,omitempty"`
+}
+
 // GetDepartmentResponse is the response from GET /contact/v3/departments/:department_id
 type GetDepartmentResponse struct {
 	BaseResponse
@@ -506,6 +742,16 @@ type SearchDepartmentsResponse struct {
 	} `json:"data,omitempty"`
 }
 
+// ListChildDepartmentsResponse is the response from GET /contact/v3/departments/:department_id/children
+type ListChildDepartmentsResponse struct {
+	BaseResponse
+	Data struct {
+		Items     []Department `json:"items,omitempty"`
+		PageToken string       `json:"page_token,omitempty"`
+		HasMore   bool         `json:"has_more"`
+	} `json:"data,omitempty"`
+}
+
 // SearchUserAvatar represents user avatar information from search
 type SearchUserAvatar struct {
 	Avatar72     string `json:"avatar_72,omitempty"`
@@ -544,6 +790,8 @@ type OutputContact struct {
 	Email      string `json:"email,omitempty"`
 	JobTitle   string `json:"job_title,omitempty"`
 	Department string `json:"department,omitempty"` // Primary department name
+	Score      int    `json:"score,omitempty"`      // Search match quality, 0-100
+	MatchedOn  string `json:"matched_on,omitempty"` // What a search matched, e.g. pinyin
 }
 
 // OutputContactList is the list contacts response for CLI
@@ -695,6 +943,30 @@ type DocumentContentResponse struct {
 	} `json:"data,omitempty"`
 }
 
+// DocumentMetasRequest is the request body for POST /drive/v1/metas/batch_query
+type DocumentMetasRequest struct {
+	RequestDocs []DocumentMetaRef `json:"request_docs"`
+	WithURL     bool              `json:"with_url"`
+}
+
+// DocumentMetaRef identifies a document in a metadata query
+type DocumentMetaRef struct {
+	DocToken string `json:"doc_token"`
+	DocType  string `json:"doc_type"`
+}
+
+// DocumentMetasResponse is the response from POST /drive/v1/metas/batch_query
+type DocumentMetasResponse struct {
+	BaseResponse
+	Data struct {
+		Metas []struct {
+			DocToken string `json:"doc_token"`
+			Title    string `json:"title"`
+			URL      string `json:"url"`
+		} `json:"metas,omitempty"`
+	} `json:"data,omitempty"`
+}
+
 // --- Document Write Types ---
 
 // CreateDocumentRequest is the request body for POST /docx/v1/documents
@@ -1227,6 +1499,14 @@ type UploadImageResponse struct {
 	} `json:"data,omitempty"`
 }
 
+// UploadMediaResponse is the response from POST /drive/v1/medias/upload_all
+type UploadMediaResponse struct {
+	BaseResponse
+	Data struct {
+		FileToken string `json:"file_token"`
+	} `json:"data,omitempty"`
+}
+
 // SendMessageResponse is the response from POST /im/v1/messages
 type SendMessageResponse struct {
 	BaseResponse
@@ -1279,6 +1559,25 @@ type SearchChatsResponse struct {
 	} `json:"data,omitempty"`
 }
 
+// ChatMember is a member of a chat/group
+type ChatMember struct {
+	MemberIDType string `json:"member_id_type,omitempty"`
+	MemberID     string `json:"member_id,omitempty"`
+	Name         string `json:"name,omitempty"`
+	TenantKey    string `json:"tenant_key,omitempty"`
+}
+
+// ChatMembersResponse is the response from GET /im/v1/chats/:chat_id/members
+type ChatMembersResponse struct {
+	BaseResponse
+	Data struct {
+		Items       []ChatMember `json:"items,omitempty"`
+		PageToken   string       `json:"page_token,omitempty"`
+		HasMore     bool         `json:"has_more"`
+		MemberTotal int          `json:"member_total,omitempty"`
+	} `json:"data,omitempty"`
+}
+
 // --- Chat CLI Output Types ---
 
 // OutputChat is the simplified chat format for CLI output
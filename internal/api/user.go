@@ -38,6 +38,7 @@ func (c *Client) GetCurrentUser() (*UserInfo, error) {
 type UserLookupOptions struct {
 	Emails  []string
 	Mobiles []string
+	IDType  string // ID returned: "open_id" (default), "union_id" or "user_id"
 }
 
 // LookupUsers looks up user IDs by email or mobile number
@@ -48,9 +49,14 @@ func (c *Client) LookupUsers(opts UserLookupOptions) ([]UserContactInfo, error)
 		Mobiles: opts.Mobiles,
 	}
 
+	idType := opts.IDType
+	if idType == "" {
+		idType = "open_id"
+	}
+
 	var resp UserLookupResponse
 	// Use tenant token for contacts API
-	if err := c.PostWithTenantToken("/contact/v3/users/batch_get_id?user_id_type=open_id", req, &resp); err != nil {
+	if err := c.PostWithTenantToken("/contact/v3/users/batch_get_id?user_id_type="+idType, req, &resp); err != nil {
 		return nil, err
 	}
 
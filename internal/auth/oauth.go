@@ -9,8 +9,10 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"os/exec"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/yjwong/lark-cli/internal/config"
@@ -24,6 +26,10 @@ const (
 	defaultTimeout    = 5 * time.Minute
 )
 
+// refreshMu serializes token checks, so concurrent API calls wait for one
+// refresh instead of each spending the single-use refresh token
+var refreshMu sync.Mutex
+
 func getAccountsHost() string {
 	if config.GetRegion() == "feishu" {
 		return "accounts.feishu.cn"
@@ -196,6 +202,8 @@ func RefreshAccessToken() error {
 
 // EnsureValidToken checks and refreshes the token if needed
 func EnsureValidToken() error {
+	refreshMu.Lock()
+	defer refreshMu.Unlock()
 	store := GetTokenStore()
 
 	if store.IsValid() {
@@ -204,7 +212,7 @@ func EnsureValidToken() error {
 			// Proactively refresh before expiry
 			if err := RefreshAccessToken(); err != nil {
 				// Log but don't fail - current token is still valid
-				fmt.Printf("Warning: Failed to proactively refresh token: %v\n", err)
+				fmt.Fprintf(os.Stderr, "Warning: Failed to proactively refresh token: %v\n", err)
 			}
 		}
 		return nil
@@ -226,6 +234,8 @@ func Logout() error {
 
 // EnsureValidTenantToken ensures we have a valid tenant access token
 func EnsureValidTenantToken() error {
+	refreshMu.Lock()
+	defer refreshMu.Unlock()
 	store := GetTenantTokenStore()
 
 	if store.IsValid() {
@@ -0,0 +1,603 @@
+// Package caldav serves calendars to desktop clients over a minimal CalDAV
+// (RFC 4791) and WebDAV (RFC 4918) interface.
+//
+// Resources are laid out as:
+//
+//	/principals/me/                    the user
+//	/calendars/                        calendar home
+//	/calendars/<calendar-id>/          a calendar collection
+//	/calendars/<calendar-id>/<name>.ics  an event
+//
+// PROPFIND, REPORT (calendar-query and calendar-multiget), GET, PUT and
+// DELETE are supported. Events are stored by a Backend.
+package caldav
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yjwong/lark-cli/internal/ics"
+	timex "github.com/yjwong/lark-cli/internal/time"
+)
+
+// Calendar is a calendar collection served by the server
+type Calendar struct {
+	ID          string
+	Name        string
+	Description string
+	ReadOnly    bool
+}
+
+// Object is an event resource in a calendar
+type Object struct {
+	Name  string // Resource name, without the .ics extension
+	Event ics.Event
+}
+
+// ErrNotFound is returned by a Backend for events that don't exist
+var ErrNotFound = errors.New("not found")
+
+// Backend stores the calendars and events the server exposes
+type Backend interface {
+	Calendars() ([]Calendar, error)
+	ListEvents(calendarID string, from, to time.Time) ([]Object, error)
+	GetEvent(calendarID, name string) (*Object, error)
+	CreateEvent(calendarID, name string, event ics.Event) error
+	UpdateEvent(calendarID, name string, event ics.Event) error
+	DeleteEvent(calendarID, name string) error
+}
+
+const (
+	principalPath = "/principals/me/"
+	homePath      = "/calendars/"
+)
+
+// Server is an http.Handler that serves a Backend over CalDAV
+type Server struct {
+	Backend    Backend
+	Location   *time.Location // Zone for floating times in uploaded events
+	PastDays   int            // Days of past events listed without a time-range filter
+	FutureDays int            // Days of upcoming events listed without a time-range filter
+
+	// OnRequest, if set, is called after each request with the response status
+	OnRequest func(r *http.Request, status int, err error)
+}
+
+// httpError is an error with the HTTP status it is reported with
+type httpError struct {
+	status int
+	msg    string
+}
+
+func (e *httpError) Error() string {
+	return e.msg
+}
+
+func errorf(status int, format string, args ...interface{}) error {
+	return &httpError{status: status, msg: fmt.Sprintf(format, args...)}
+}
+
+// statusRecorder remembers the status written to a ResponseWriter
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// ServeHTTP implements http.Handler. Backend failures are reported as
+// 502 Bad Gateway.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	err := s.serve(rec, r)
+	if err != nil {
+		status := http.StatusBadGateway
+		var he *httpError
+		if errors.As(err, &he) {
+			status = he.status
+		}
+		http.Error(rec, err.Error(), status)
+	}
+	if s.OnRequest != nil {
+		s.OnRequest(r, rec.status, err)
+	}
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) error {
+	if strings.TrimSuffix(r.URL.Path, "/") == "/.well-known/caldav" {
+		http.Redirect(w, r, principalPath, http.StatusMovedPermanently)
+		return nil
+	}
+
+	res, err := parsePath(r.URL.Path)
+	if err != nil {
+		return err
+	}
+
+	switch r.Method {
+	case http.MethodOptions:
+		w.Header().Set("DAV", "1, 3, calendar-access")
+		w.Header().Set("Allow", allowedMethods)
+		w.WriteHeader(http.StatusOK)
+		return nil
+	case "PROPFIND":
+		return s.propfind(w, r, res)
+	case "REPORT":
+		return s.report(w, r, res)
+	case http.MethodGet, http.MethodHead:
+		return s.get(w, r, res)
+	case http.MethodPut:
+		return s.put(w, r, res)
+	case http.MethodDelete:
+		return s.delete(w, r, res)
+	}
+	w.Header().Set("Allow", allowedMethods)
+	return errorf(http.StatusMethodNotAllowed, "method %s not supported", r.Method)
+}
+
+const allowedMethods = "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT"
+
+// --- Paths ---
+
+type resourceKind int
+
+const (
+	kindRoot resourceKind = iota
+	kindPrincipal
+	kindHome
+	kindCalendar
+	kindEvent
+)
+
+// resource is a parsed request path
+type resource struct {
+	kind       resourceKind
+	calendarID string
+	name       string
+}
+
+func parsePath(p string) (resource, error) {
+	parts := strings.FieldsFunc(p, func(r rune) bool { return r == '/' })
+	switch {
+	case len(parts) == 0:
+		return resource{kind: kindRoot}, nil
+	case len(parts) == 2 && parts[0] == "principals" && parts[1] == "me":
+		return resource{kind: kindPrincipal}, nil
+	case len(parts) == 1 && parts[0] == "calendars":
+		return resource{kind: kindHome}, nil
+	case len(parts) == 2 && parts[0] == "calendars":
+		return resource{kind: kindCalendar, calendarID: parts[1]}, nil
+	case len(parts) == 3 && parts[0] == "calendars" && strings.HasSuffix(parts[2], ".ics"):
+		return resource{kind: kindEvent, calendarID: parts[1], name: strings.TrimSuffix(parts[2], ".ics")}, nil
+	}
+	return resource{}, errorf(http.StatusNotFound, "no resource at %s", p)
+}
+
+func calendarHref(calendarID string) string {
+	return homePath + url.PathEscape(calendarID) + "/"
+}
+
+func eventHref(calendarID, name string) string {
+	return calendarHref(calendarID) + url.PathEscape(name) + ".ics"
+}
+
+// --- Properties ---
+
+func (s *Server) calendar(id string) (*Calendar, error) {
+	calendars, err := s.Backend.Calendars()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range calendars {
+		if c.ID == id {
+			return &c, nil
+		}
+	}
+	return nil, errorf(http.StatusNotFound, "calendar %s not found", id)
+}
+
+func davName(local string) xml.Name {
+	return xml.Name{Space: nsDAV, Local: local}
+}
+
+func calDAVName(local string) xml.Name {
+	return xml.Name{Space: nsCalDAV, Local: local}
+}
+
+var principalProp = davProp{davName("current-user-principal"), hrefElement(principalPath)}
+
+func rootProps() []davProp {
+	return []davProp{
+		{davName("resourcetype"), "<d:collection/>"},
+		{davName("displayname"), "Lark"},
+		principalProp,
+	}
+}
+
+func principalProps() []davProp {
+	return []davProp{
+		{davName("resourcetype"), "<d:collection/><d:principal/>"},
+		{davName("displayname"), "Lark"},
+		principalProp,
+		{davName("principal-URL"), hrefElement(principalPath)},
+		{calDAVName("calendar-home-set"), hrefElement(homePath)},
+	}
+}
+
+func homeProps() []davProp {
+	return []davProp{
+		{davName("resourcetype"), "<d:collection/>"},
+		{davName("displayname"), "Calendars"},
+		principalProp,
+	}
+}
+
+func calendarProps(c Calendar) []davProp {
+	privileges := "<d:privilege><d:read/></d:privilege>"
+	if !c.ReadOnly {
+		privileges += "<d:privilege><d:write/></d:privilege><d:privilege><d:write-content/></d:privilege>" +
+			"<d:privilege><d:bind/></d:privilege><d:privilege><d:unbind/></d:privilege>"
+	}
+	return []davProp{
+		{davName("resourcetype"), "<d:collection/><c:calendar/>"},
+		{davName("displayname"), escape(c.Name)},
+		{calDAVName("calendar-description"), escape(c.Description)},
+		{calDAVName("supported-calendar-component-set"), `<c:comp name="VEVENT"/>`},
+		{davName("supported-report-set"), "<d:supported-report><d:report><c:calendar-query/></d:report></d:supported-report>" +
+			"<d:supported-report><d:report><c:calendar-multiget/></d:report></d:supported-report>"},
+		{davName("current-user-privilege-set"), privileges},
+		principalProp,
+	}
+}
+
+// eventProps lists an event's properties; calendar-data only when requested
+func eventProps(o Object, req *davRequest) ([]davProp, error) {
+	props := []davProp{
+		{davName("resourcetype"), ""},
+		{davName("getetag"), escape(etag(o.Event))},
+		{davName("getcontenttype"), "text/calendar; charset=utf-8; component=vevent"},
+	}
+	if req.wants(calendarDataName) {
+		var buf bytes.Buffer
+		if err := ics.EncodeObject(&buf, o.Event); err != nil {
+			return nil, err
+		}
+		props = append(props, davProp{calendarDataName, escape(buf.String())})
+	}
+	return props, nil
+}
+
+// etag derives an entity tag from an event's content
+func etag(e ics.Event) string {
+	data, _ := json.Marshal(e)
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// matchETag reports whether an If-Match or If-None-Match header matches tag
+func matchETag(header, tag string) bool {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		if v == "*" || v == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// window is the range listed when a client doesn't give a time range
+func (s *Server) window() (time.Time, time.Time) {
+	today := timex.StartOfDay(time.Now().In(s.Location))
+	return today.AddDate(0, 0, -s.PastDays), today.AddDate(0, 0, s.FutureDays+1)
+}
+
+// --- Methods ---
+
+func (s *Server) propfind(w http.ResponseWriter, r *http.Request, res resource) error {
+	req, err := parseRequest(r.Body)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "%v", err)
+	}
+	children := r.Header.Get("Depth") != "0"
+
+	var responses []davResponse
+	switch res.kind {
+	case kindRoot:
+		responses = append(responses, selectProps("/", rootProps(), req))
+		if children {
+			responses = append(responses, selectProps(homePath, homeProps(), req))
+		}
+	case kindPrincipal:
+		responses = append(responses, selectProps(principalPath, principalProps(), req))
+	case kindHome:
+		responses = append(responses, selectProps(homePath, homeProps(), req))
+		if children {
+			calendars, err := s.Backend.Calendars()
+			if err != nil {
+				return err
+			}
+			for _, c := range calendars {
+				responses = append(responses, selectProps(calendarHref(c.ID), calendarProps(c), req))
+			}
+		}
+	case kindCalendar:
+		cal, err := s.calendar(res.calendarID)
+		if err != nil {
+			return err
+		}
+		responses = append(responses, selectProps(calendarHref(cal.ID), calendarProps(*cal), req))
+		if children {
+			from, to := s.window()
+			objects, err := s.Backend.ListEvents(cal.ID, from, to)
+			if err != nil {
+				return err
+			}
+			for _, o := range objects {
+				props, err := eventProps(o, req)
+				if err != nil {
+					return err
+				}
+				responses = append(responses, selectProps(eventHref(cal.ID, o.Name), props, req))
+			}
+		}
+	case kindEvent:
+		o, err := s.object(res)
+		if err != nil {
+			return err
+		}
+		props, err := eventProps(*o, req)
+		if err != nil {
+			return err
+		}
+		responses = append(responses, selectProps(eventHref(res.calendarID, o.Name), props, req))
+	}
+
+	writeMultistatus(w, responses)
+	return nil
+}
+
+func (s *Server) report(w http.ResponseWriter, r *http.Request, res resource) error {
+	req, err := parseRequest(r.Body)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "%v", err)
+	}
+
+	var responses []davResponse
+	switch req.Root {
+	case calDAVName("calendar-query"):
+		if res.kind != kindCalendar {
+			return errorf(http.StatusForbidden, "calendar-query must target a calendar")
+		}
+		if _, err := s.calendar(res.calendarID); err != nil {
+			return err
+		}
+		// Only events are stored; a query for tasks or journals matches nothing
+		for _, comp := range req.Components {
+			if comp != "VCALENDAR" && comp != "VEVENT" {
+				writeMultistatus(w, nil)
+				return nil
+			}
+		}
+
+		from, to := s.window()
+		if !req.Start.IsZero() {
+			from = req.Start
+		}
+		if !req.End.IsZero() {
+			to = req.End
+		}
+		objects, err := s.Backend.ListEvents(res.calendarID, from, to)
+		if err != nil {
+			return err
+		}
+		for _, o := range objects {
+			props, err := eventProps(o, req)
+			if err != nil {
+				return err
+			}
+			responses = append(responses, selectProps(eventHref(res.calendarID, o.Name), props, req))
+		}
+	case calDAVName("calendar-multiget"):
+		for _, href := range req.Hrefs {
+			target := href
+			if u, err := url.Parse(href); err == nil {
+				target = u.Path
+			}
+			hres, err := parsePath(target)
+			if err != nil || hres.kind != kindEvent {
+				responses = append(responses, davResponse{Href: href, Status: http.StatusNotFound})
+				continue
+			}
+			o, err := s.object(hres)
+			if err != nil {
+				var he *httpError
+				if errors.As(err, &he) && he.status == http.StatusNotFound {
+					responses = append(responses, davResponse{Href: href, Status: http.StatusNotFound})
+					continue
+				}
+				return err
+			}
+			props, err := eventProps(*o, req)
+			if err != nil {
+				return err
+			}
+			responses = append(responses, selectProps(href, props, req))
+		}
+	default:
+		return errorf(http.StatusForbidden, "unsupported report %s", req.Root.Local)
+	}
+
+	writeMultistatus(w, responses)
+	return nil
+}
+
+// object fetches an event, mapping ErrNotFound to a 404
+func (s *Server) object(res resource) (*Object, error) {
+	o, err := s.Backend.GetEvent(res.calendarID, res.name)
+	if errors.Is(err, ErrNotFound) {
+		return nil, errorf(http.StatusNotFound, "event %s not found", res.name)
+	}
+	return o, err
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request, res resource) error {
+	var body bytes.Buffer
+	switch res.kind {
+	case kindEvent:
+		o, err := s.object(res)
+		if err != nil {
+			return err
+		}
+		tag := etag(o.Event)
+		w.Header().Set("ETag", tag)
+		if inm := r.Header.Get("If-None-Match"); inm != "" && matchETag(inm, tag) {
+			w.WriteHeader(http.StatusNotModified)
+			return nil
+		}
+		if err := ics.EncodeObject(&body, o.Event); err != nil {
+			return err
+		}
+	case kindCalendar:
+		// The whole window as one file, for clients that only subscribe
+		if _, err := s.calendar(res.calendarID); err != nil {
+			return err
+		}
+		from, to := s.window()
+		objects, err := s.Backend.ListEvents(res.calendarID, from, to)
+		if err != nil {
+			return err
+		}
+		events := make([]ics.Event, len(objects))
+		for i, o := range objects {
+			events[i] = o.Event
+		}
+		if err := ics.Encode(&body, events); err != nil {
+			return err
+		}
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintf(w, "Lark CalDAV server. Add http://%s/ to your calendar client.\n", r.Host)
+		return nil
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(body.Bytes())
+	}
+	return nil
+}
+
+func (s *Server) put(w http.ResponseWriter, r *http.Request, res resource) error {
+	if res.kind != kindEvent {
+		return errorf(http.StatusMethodNotAllowed, "only events can be written")
+	}
+	cal, err := s.calendar(res.calendarID)
+	if err != nil {
+		return err
+	}
+	if cal.ReadOnly {
+		return errorf(http.StatusForbidden, "calendar %s is read-only", cal.Name)
+	}
+
+	event, err := decodeObject(r.Body, s.Location)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "%v", err)
+	}
+
+	existing, err := s.Backend.GetEvent(res.calendarID, res.name)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	if existing != nil {
+		if inm := r.Header.Get("If-None-Match"); inm != "" && matchETag(inm, etag(existing.Event)) {
+			return errorf(http.StatusPreconditionFailed, "event %s already exists", res.name)
+		}
+		if im := r.Header.Get("If-Match"); im != "" && !matchETag(im, etag(existing.Event)) {
+			return errorf(http.StatusPreconditionFailed, "event %s has changed", res.name)
+		}
+		if err := s.Backend.UpdateEvent(res.calendarID, res.name, event); err != nil {
+			return err
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+
+	if r.Header.Get("If-Match") != "" {
+		return errorf(http.StatusPreconditionFailed, "event %s not found", res.name)
+	}
+	if err := s.Backend.CreateEvent(res.calendarID, res.name, event); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusCreated)
+	return nil
+}
+
+// decodeObject parses an uploaded calendar object resource. Overridden
+// occurrences (RECURRENCE-ID) are ignored; every component must share a UID.
+func decodeObject(r io.Reader, loc *time.Location) (ics.Event, error) {
+	events, err := ics.Decode(r, loc)
+	if err != nil {
+		return ics.Event{}, err
+	}
+	var master *ics.Event
+	for i, e := range events {
+		if e.UID != events[0].UID {
+			return ics.Event{}, fmt.Errorf("calendar object has more than one UID")
+		}
+		if e.RecurrenceID == "" && master == nil {
+			master = &events[i]
+		}
+	}
+	if master == nil {
+		return ics.Event{}, fmt.Errorf("calendar object has no VEVENT")
+	}
+	return *master, nil
+}
+
+func (s *Server) delete(w http.ResponseWriter, r *http.Request, res resource) error {
+	if res.kind != kindEvent {
+		return errorf(http.StatusForbidden, "only events can be deleted")
+	}
+	cal, err := s.calendar(res.calendarID)
+	if err != nil {
+		return err
+	}
+	if cal.ReadOnly {
+		return errorf(http.StatusForbidden, "calendar %s is read-only", cal.Name)
+	}
+
+	if im := r.Header.Get("If-Match"); im != "" {
+		o, err := s.object(res)
+		if err != nil {
+			return err
+		}
+		if !matchETag(im, etag(o.Event)) {
+			return errorf(http.StatusPreconditionFailed, "event %s has changed", res.name)
+		}
+	}
+
+	err = s.Backend.DeleteEvent(res.calendarID, res.name)
+	if errors.Is(err, ErrNotFound) {
+		return errorf(http.StatusNotFound, "event %s not found", res.name)
+	}
+	if err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
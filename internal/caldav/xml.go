@@ -0,0 +1,232 @@
+package caldav
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// XML namespaces used by WebDAV and CalDAV
+const (
+	nsDAV    = "DAV:"
+	nsCalDAV = "urn:ietf:params:xml:ns:caldav"
+	nsCS     = "http://calendarserver.org/ns/"
+)
+
+// prefixes are the namespace prefixes used in responses
+var prefixes = map[string]string{nsDAV: "d", nsCalDAV: "c", nsCS: "cs"}
+
+var (
+	propName         = xml.Name{Space: nsDAV, Local: "prop"}
+	calendarDataName = xml.Name{Space: nsCalDAV, Local: "calendar-data"}
+)
+
+// utcFormat is the iCalendar UTC format used by time-range filters
+const utcFormat = "20060102T150405Z"
+
+// davRequest is the parsed body of a PROPFIND or REPORT request
+type davRequest struct {
+	Root       xml.Name   // Root element, e.g. propfind or calendar-query
+	Props      []xml.Name // Requested properties
+	AllProp    bool       // allprop, propname or an empty body
+	Hrefs      []string   // calendar-multiget targets
+	Start, End time.Time  // time-range filter; zero when open
+	Components []string   // comp-filter names, outermost first
+}
+
+// wants reports whether a property should be included in the response.
+// calendar-data is only returned when asked for by name.
+func (d *davRequest) wants(name xml.Name) bool {
+	if d.AllProp {
+		return name != calendarDataName
+	}
+	for _, p := range d.Props {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRequest reads the parts of a request body the server acts on
+func parseRequest(r io.Reader) (*davRequest, error) {
+	req := &davRequest{}
+	dec := xml.NewDecoder(r)
+	var stack []xml.Name
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid XML body: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if len(stack) == 0 {
+				req.Root = t.Name
+			} else if stack[len(stack)-1] == propName {
+				req.Props = append(req.Props, t.Name)
+			}
+
+			switch t.Name {
+			case xml.Name{Space: nsDAV, Local: "allprop"}, xml.Name{Space: nsDAV, Local: "propname"}:
+				req.AllProp = true
+			case xml.Name{Space: nsDAV, Local: "href"}:
+				var href string
+				if err := dec.DecodeElement(&href, &t); err != nil {
+					return nil, fmt.Errorf("invalid href: %w", err)
+				}
+				req.Hrefs = append(req.Hrefs, strings.TrimSpace(href))
+				continue // DecodeElement consumed the end tag
+			case xml.Name{Space: nsCalDAV, Local: "comp-filter"}:
+				req.Components = append(req.Components, attr(t, "name"))
+			case xml.Name{Space: nsCalDAV, Local: "time-range"}:
+				if req.Start, err = parseUTC(attr(t, "start")); err != nil {
+					return nil, err
+				}
+				if req.End, err = parseUTC(attr(t, "end")); err != nil {
+					return nil, err
+				}
+			}
+			stack = append(stack, t.Name)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	if req.Root.Local == "" {
+		req.AllProp = true
+	}
+	return req, nil
+}
+
+func attr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// parseUTC parses a time-range bound; an empty value is an open bound
+func parseUTC(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(utcFormat, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time-range value %q (want UTC, e.g. 20260105T000000Z)", s)
+	}
+	return t, nil
+}
+
+// davProp is a property and its value as inner XML
+type davProp struct {
+	Name  xml.Name
+	Value string
+}
+
+// davResponse is one resource in a multistatus response
+type davResponse struct {
+	Href    string
+	Props   []davProp
+	Missing []xml.Name
+	Status  int // Set instead of properties, e.g. 404 for a missing multiget href
+}
+
+// selectProps builds the response for a resource from its available properties
+func selectProps(href string, available []davProp, req *davRequest) davResponse {
+	resp := davResponse{Href: href}
+	if req.AllProp {
+		for _, p := range available {
+			if req.wants(p.Name) {
+				resp.Props = append(resp.Props, p)
+			}
+		}
+		return resp
+	}
+	for _, name := range req.Props {
+		found := false
+		for _, p := range available {
+			if p.Name == name {
+				resp.Props = append(resp.Props, p)
+				found = true
+				break
+			}
+		}
+		if !found {
+			resp.Missing = append(resp.Missing, name)
+		}
+	}
+	return resp
+}
+
+// writeMultistatus writes a 207 Multi-Status response
+func writeMultistatus(w http.ResponseWriter, responses []davResponse) {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	b.WriteString(`<d:multistatus xmlns:d="DAV:" xmlns:c="` + nsCalDAV + `" xmlns:cs="` + nsCS + `">`)
+	for _, r := range responses {
+		b.WriteString("<d:response><d:href>" + escape(r.Href) + "</d:href>")
+		if r.Status != 0 {
+			b.WriteString("<d:status>" + statusLine(r.Status) + "</d:status>")
+		}
+		if len(r.Props) > 0 {
+			b.WriteString("<d:propstat><d:prop>")
+			for _, p := range r.Props {
+				b.WriteString(element(p.Name, p.Value))
+			}
+			b.WriteString("</d:prop><d:status>" + statusLine(http.StatusOK) + "</d:status></d:propstat>")
+		}
+		if len(r.Missing) > 0 {
+			b.WriteString("<d:propstat><d:prop>")
+			for _, name := range r.Missing {
+				b.WriteString(element(name, ""))
+			}
+			b.WriteString("</d:prop><d:status>" + statusLine(http.StatusNotFound) + "</d:status></d:propstat>")
+		}
+		b.WriteString("</d:response>")
+	}
+	b.WriteString("</d:multistatus>\n")
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	io.WriteString(w, b.String())
+}
+
+// element renders an element with inner XML, declaring unknown namespaces inline
+func element(name xml.Name, inner string) string {
+	tag, decl := name.Local, ""
+	if p, ok := prefixes[name.Space]; ok {
+		tag = p + ":" + name.Local
+	} else if name.Space != "" {
+		tag = "x:" + name.Local
+		decl = ` xmlns:x="` + escape(name.Space) + `"`
+	}
+	if inner == "" {
+		return "<" + tag + decl + "/>"
+	}
+	return "<" + tag + decl + ">" + inner + "</" + tag + ">"
+}
+
+func hrefElement(href string) string {
+	return "<d:href>" + escape(href) + "</d:href>"
+}
+
+func statusLine(code int) string {
+	return fmt.Sprintf("HTTP/1.1 %d %s", code, http.StatusText(code))
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,444 @@
+package calendar
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/config"
+	_ "modernc.org/sqlite"
+)
+
+// CacheFilePath returns the path to the calendar cache database
+func CacheFilePath() string {
+	return filepath.Join(config.GetConfigDir(), "calendar_cache.db")
+}
+
+// Cache provides SQLite-based calendar event caching
+type Cache struct {
+	db *sql.DB
+}
+
+// OpenCache opens or creates the cache database
+func OpenCache() (*Cache, error) {
+	path := CacheFilePath()
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening cache database: %w", err)
+	}
+
+	cache := &Cache{db: db}
+	if err := cache.init(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return cache, nil
+}
+
+// Close closes the cache database
+func (c *Cache) Close() error {
+	if c.db != nil {
+		return c.db.Close()
+	}
+	return nil
+}
+
+func (c *Cache) init() error {
+	schema := `
+		CREATE TABLE IF NOT EXISTS calendars (
+			calendar_id TEXT PRIMARY KEY,
+			summary TEXT,
+			type TEXT,
+			sync_token TEXT NOT NULL DEFAULT '',
+			window_start INTEGER NOT NULL DEFAULT 0,
+			window_end INTEGER NOT NULL DEFAULT 0,
+			last_sync INTEGER NOT NULL DEFAULT 0
+		);
+
+		CREATE TABLE IF NOT EXISTS instances (
+			calendar_id TEXT NOT NULL,
+			event_id TEXT NOT NULL,
+			start_ts INTEGER NOT NULL,
+			end_ts INTEGER NOT NULL,
+			summary TEXT,
+			description TEXT,
+			location TEXT,
+			data TEXT NOT NULL,
+			PRIMARY KEY (calendar_id, event_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS changes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			calendar_id TEXT NOT NULL,
+			event_id TEXT NOT NULL,
+			change TEXT NOT NULL,
+			summary TEXT,
+			changed_at INTEGER NOT NULL,
+			data TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS meta (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_instances_start ON instances(calendar_id, start_ts);
+		CREATE INDEX IF NOT EXISTS idx_changes_changed_at ON changes(calendar_id, changed_at);
+	`
+
+	_, err := c.db.Exec(schema)
+	if err != nil {
+		return fmt.Errorf("initializing cache schema: %w", err)
+	}
+
+	return nil
+}
+
+// CalendarState holds sync state for a calendar
+type CalendarState struct {
+	CalendarID  string
+	Summary     string
+	Type        string
+	SyncToken   string
+	WindowStart time.Time // Instances are cached for [WindowStart, WindowEnd)
+	WindowEnd   time.Time
+	LastSync    time.Time
+}
+
+// GetCalendarState returns the cached state for a calendar
+func (c *Cache) GetCalendarState(calendarID string) (*CalendarState, error) {
+	states, err := c.queryCalendarStates(`WHERE calendar_id = ?`, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	if len(states) == 0 {
+		return nil, nil
+	}
+	return &states[0], nil
+}
+
+// ListCalendarStates returns the state of every synced calendar, primary first
+func (c *Cache) ListCalendarStates() ([]CalendarState, error) {
+	return c.queryCalendarStates(`ORDER BY type = 'primary' DESC, summary`)
+}
+
+func (c *Cache) queryCalendarStates(clause string, args ...any) ([]CalendarState, error) {
+	rows, err := c.db.Query(
+		`SELECT calendar_id, summary, type, sync_token, window_start, window_end, last_sync
+		 FROM calendars `+clause,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying calendar state: %w", err)
+	}
+	defer rows.Close()
+
+	var states []CalendarState
+	for rows.Next() {
+		var s CalendarState
+		var summary, calType sql.NullString
+		var windowStart, windowEnd, lastSync int64
+		if err := rows.Scan(&s.CalendarID, &summary, &calType, &s.SyncToken, &windowStart, &windowEnd, &lastSync); err != nil {
+			return nil, fmt.Errorf("scanning calendar state: %w", err)
+		}
+		s.Summary = summary.String
+		s.Type = calType.String
+		s.WindowStart = time.Unix(windowStart, 0)
+		s.WindowEnd = time.Unix(windowEnd, 0)
+		s.LastSync = time.Unix(lastSync, 0)
+		states = append(states, s)
+	}
+	return states, rows.Err()
+}
+
+// Change is an event created, updated or cancelled between two syncs
+type Change struct {
+	CalendarID string
+	EventID    string
+	Type       string // created, updated, cancelled
+	ChangedAt  time.Time
+	Event      api.Event
+}
+
+// SyncUpdate is everything one sync writes for a calendar. It is applied in
+// a single transaction, so an interrupted sync leaves the cache unchanged.
+type SyncUpdate struct {
+	State   CalendarState
+	Replace bool        // Drop every cached instance before applying Upserts
+	Removed []string    // Event IDs to drop, along with their recurring instances
+	Upserts []api.Event // Instances to add or refresh
+	Changes []Change
+}
+
+// ApplySync writes a sync's results. Instances outside the state's window
+// are dropped.
+func (c *Cache) ApplySync(u *SyncUpdate, loc *time.Location) error {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	calendarID := u.State.CalendarID
+
+	if u.Replace {
+		if _, err := tx.Exec(`DELETE FROM instances WHERE calendar_id = ?`, calendarID); err != nil {
+			return fmt.Errorf("clearing instances: %w", err)
+		}
+	}
+
+	for _, id := range u.Removed {
+		_, err := tx.Exec(
+			`DELETE FROM instances WHERE calendar_id = ? AND (event_id = ? OR event_id LIKE ? ESCAPE '\')`,
+			calendarID, id, instancePattern(id),
+		)
+		if err != nil {
+			return fmt.Errorf("removing event: %w", err)
+		}
+	}
+
+	if len(u.Upserts) > 0 {
+		stmt, err := tx.Prepare(
+			`INSERT INTO instances (calendar_id, event_id, start_ts, end_ts, summary, description, location, data)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			 ON CONFLICT(calendar_id, event_id) DO UPDATE SET
+				start_ts = excluded.start_ts,
+				end_ts = excluded.end_ts,
+				summary = excluded.summary,
+				description = excluded.description,
+				location = excluded.location,
+				data = excluded.data`,
+		)
+		if err != nil {
+			return fmt.Errorf("preparing insert: %w", err)
+		}
+		defer stmt.Close()
+
+		for _, e := range u.Upserts {
+			data, err := json.Marshal(e)
+			if err != nil {
+				return fmt.Errorf("encoding event: %w", err)
+			}
+			start, end := EventBounds(e, loc)
+			var location string
+			if e.Location != nil {
+				location = e.Location.Name
+			}
+			_, err = stmt.Exec(calendarID, e.EventID, start.Unix(), end.Unix(), e.Summary, e.Description, location, string(data))
+			if err != nil {
+				return fmt.Errorf("inserting event: %w", err)
+			}
+		}
+	}
+
+	_, err = tx.Exec(
+		`DELETE FROM instances WHERE calendar_id = ? AND (end_ts <= ? OR start_ts >= ?)`,
+		calendarID, u.State.WindowStart.Unix(), u.State.WindowEnd.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("pruning instances: %w", err)
+	}
+
+	for _, ch := range u.Changes {
+		data, err := json.Marshal(ch.Event)
+		if err != nil {
+			return fmt.Errorf("encoding event: %w", err)
+		}
+		_, err = tx.Exec(
+			`INSERT INTO changes (calendar_id, event_id, change, summary, changed_at, data) VALUES (?, ?, ?, ?, ?, ?)`,
+			calendarID, ch.EventID, ch.Type, ch.Event.Summary, ch.ChangedAt.Unix(), string(data),
+		)
+		if err != nil {
+			return fmt.Errorf("recording change: %w", err)
+		}
+	}
+
+	s := u.State
+	_, err = tx.Exec(
+		`INSERT INTO calendars (calendar_id, summary, type, sync_token, window_start, window_end, last_sync)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(calendar_id) DO UPDATE SET
+			summary = excluded.summary,
+			type = excluded.type,
+			sync_token = excluded.sync_token,
+			window_start = excluded.window_start,
+			window_end = excluded.window_end,
+			last_sync = excluded.last_sync`,
+		calendarID, s.Summary, s.Type, s.SyncToken, s.WindowStart.Unix(), s.WindowEnd.Unix(), s.LastSync.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("updating calendar state: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// CountInstances returns the number of cached instances for a calendar
+func (c *Cache) CountInstances(calendarID string) (int, error) {
+	var count int
+	err := c.db.QueryRow(`SELECT COUNT(*) FROM instances WHERE calendar_id = ?`, calendarID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("counting instances: %w", err)
+	}
+	return count, nil
+}
+
+// Events returns cached instances overlapping [from, to), in start order
+func (c *Cache) Events(calendarID string, from, to time.Time) ([]api.Event, error) {
+	return c.queryEvents(
+		`WHERE calendar_id = ? AND start_ts < ? AND end_ts > ?`,
+		calendarID, to.Unix(), from.Unix(),
+	)
+}
+
+// Search returns cached instances overlapping [from, to) whose summary,
+// description or location contains query, in start order
+func (c *Cache) Search(calendarID, query string, from, to time.Time) ([]api.Event, error) {
+	like := "%" + query + "%"
+	return c.queryEvents(
+		`WHERE calendar_id = ? AND start_ts < ? AND end_ts > ?
+		 AND (summary LIKE ? OR description LIKE ? OR location LIKE ?)`,
+		calendarID, to.Unix(), from.Unix(), like, like, like,
+	)
+}
+
+func (c *Cache) queryEvents(clause string, args ...any) ([]api.Event, error) {
+	rows, err := c.db.Query(`SELECT data FROM instances `+clause+` ORDER BY start_ts, event_id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying cache: %w", err)
+	}
+	defer rows.Close()
+
+	var events []api.Event
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("scanning row: %w", err)
+		}
+		var e api.Event
+		if err := json.Unmarshal([]byte(data), &e); err != nil {
+			return nil, fmt.Errorf("decoding cached event: %w", err)
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// Changes returns changes recorded for a calendar at or after since, oldest first
+func (c *Cache) Changes(calendarID string, since time.Time) ([]Change, error) {
+	rows, err := c.db.Query(
+		`SELECT event_id, change, changed_at, data FROM changes
+		 WHERE calendar_id = ? AND changed_at >= ? ORDER BY changed_at, id`,
+		calendarID, since.Unix(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []Change
+	for rows.Next() {
+		ch := Change{CalendarID: calendarID}
+		var changedAt int64
+		var data string
+		if err := rows.Scan(&ch.EventID, &ch.Type, &changedAt, &data); err != nil {
+			return nil, fmt.Errorf("scanning change: %w", err)
+		}
+		ch.ChangedAt = time.Unix(changedAt, 0)
+		if err := json.Unmarshal([]byte(data), &ch.Event); err != nil {
+			return nil, fmt.Errorf("decoding change: %w", err)
+		}
+		changes = append(changes, ch)
+	}
+	return changes, rows.Err()
+}
+
+// GetMeta returns a stored value, or "" if unset
+func (c *Cache) GetMeta(key string) (string, error) {
+	var value string
+	err := c.db.QueryRow(`SELECT value FROM meta WHERE key = ?`, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("querying %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// SetMeta stores a value
+func (c *Cache) SetMeta(key, value string) error {
+	_, err := c.db.Exec(
+		`INSERT INTO meta (key, value) VALUES (?, ?)
+		 ON CONFLICT(key) DO UPDATE SET value = excluded.value`,
+		key, value,
+	)
+	if err != nil {
+		return fmt.Errorf("storing %s: %w", key, err)
+	}
+	return nil
+}
+
+// instancePattern matches the IDs of a recurring event's instances, which
+// are the series ID followed by an underscore and the start time
+func instancePattern(id string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return r.Replace(id) + `\_%`
+}
+
+// EventBounds returns an event's start and end. All-day dates are taken as
+// midnight in loc.
+func EventBounds(e api.Event, loc *time.Location) (time.Time, time.Time) {
+	return parseTimeInfo(e.StartTime, loc), parseTimeInfo(e.EndTime, loc)
+}
+
+func parseTimeInfo(ti *api.TimeInfo, loc *time.Location) time.Time {
+	if ti == nil {
+		return time.Time{}
+	}
+	if ti.Date != "" {
+		t, _ := time.ParseInLocation("2006-01-02", strings.TrimSpace(ti.Date), loc)
+		return t
+	}
+	ts, _ := strconv.ParseInt(ti.Timestamp, 10, 64)
+	return time.Unix(ts, 0).In(loc)
+}
+
+// Freshness describes how long ago t was
+func Freshness(t time.Time) string {
+	if t.IsZero() || t.Unix() == 0 {
+		return "never synced"
+	}
+
+	d := time.Since(t)
+
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		mins := int(d.Minutes())
+		if mins == 1 {
+			return "1 minute ago"
+		}
+		return fmt.Sprintf("%d minutes ago", mins)
+	case d < 24*time.Hour:
+		hours := int(d.Hours())
+		if hours == 1 {
+			return "1 hour ago"
+		}
+		return fmt.Sprintf("%d hours ago", hours)
+	default:
+		days := int(d.Hours() / 24)
+		if days == 1 {
+			return "1 day ago"
+		}
+		return fmt.Sprintf("%d days ago", days)
+	}
+}
@@ -0,0 +1,206 @@
+package calendar
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/yjwong/lark-cli/internal/api"
+	timex "github.com/yjwong/lark-cli/internal/time"
+)
+
+// Default cache window, relative to today
+const (
+	DefaultPastDays   = 30
+	DefaultFutureDays = 180
+)
+
+// SyncOptions configures the sync operation
+type SyncOptions struct {
+	PastDays   int
+	FutureDays int
+	Full       bool // Ignore the sync token and refetch everything
+	Location   *time.Location
+	Progress   io.Writer // If set, progress is written here
+}
+
+// SyncResult contains the result of a sync operation
+type SyncResult struct {
+	CalendarID  string `json:"calendar_id"`
+	Calendar    string `json:"calendar"`
+	FullSync    bool   `json:"full_sync"`
+	Created     int    `json:"created"`
+	Updated     int    `json:"updated"`
+	Cancelled   int    `json:"cancelled"`
+	TotalCached int    `json:"total_cached"`
+	WindowStart string `json:"window_start"`
+	WindowEnd   string `json:"window_end"`
+	Message     string `json:"message"`
+}
+
+// Sync brings a calendar's cached instances up to date.
+//
+// The first sync fetches every instance in the window and a sync token.
+// Later syncs ask only for events changed since that token and record them
+// as changes. Changes to one-off events are patched into the cache directly;
+// a change to any recurring series refetches the window, since the change
+// list doesn't expand series into instances.
+func Sync(client *api.Client, cache *Cache, cal api.Calendar, opts *SyncOptions) (*SyncResult, error) {
+	if opts == nil {
+		opts = &SyncOptions{}
+	}
+	if opts.PastDays <= 0 {
+		opts.PastDays = DefaultPastDays
+	}
+	if opts.FutureDays <= 0 {
+		opts.FutureDays = DefaultFutureDays
+	}
+	loc := opts.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	now := time.Now().In(loc)
+	today := timex.StartOfDay(now)
+	u := &SyncUpdate{
+		State: CalendarState{
+			CalendarID:  cal.CalendarID,
+			Summary:     cal.DisplayName(),
+			Type:        cal.Type,
+			WindowStart: today.AddDate(0, 0, -opts.PastDays),
+			WindowEnd:   today.AddDate(0, 0, opts.FutureDays),
+			LastSync:    now,
+		},
+	}
+	result := &SyncResult{
+		CalendarID:  cal.CalendarID,
+		Calendar:    cal.DisplayName(),
+		WindowStart: u.State.WindowStart.Format("2006-01-02"),
+		WindowEnd:   u.State.WindowEnd.Format("2006-01-02"),
+	}
+
+	state, err := cache.GetCalendarState(cal.CalendarID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A window reaching further back than what's cached needs a full fetch too
+	full := opts.Full || state == nil || state.SyncToken == "" || state.WindowStart.After(u.State.WindowStart)
+
+	var changed []api.Event
+	if !full {
+		progress(opts, "Fetching changes for %s...\n", result.Calendar)
+		changed, u.State.SyncToken, err = client.ListEventChanges(cal.CalendarID, state.SyncToken, time.Time{})
+		if err != nil {
+			// Most likely an expired token; start over
+			progress(opts, "Incremental sync failed (%v); resyncing\n", err)
+			full = true
+		}
+	}
+
+	if full {
+		result.FullSync = true
+		progress(opts, "Fetching events for %s...\n", result.Calendar)
+
+		// Take the token before listing, so nothing changed in between is missed
+		if _, u.State.SyncToken, err = client.ListEventChanges(cal.CalendarID, "", u.State.WindowStart); err != nil {
+			return nil, fmt.Errorf("getting sync token: %w", err)
+		}
+		if u.Upserts, err = listWindow(client, cal.CalendarID, u.State.WindowStart, u.State.WindowEnd); err != nil {
+			return nil, err
+		}
+		u.Replace = true
+	} else {
+		refetch := false
+		for _, e := range changed {
+			ch := Change{CalendarID: cal.CalendarID, EventID: e.EventID, Type: changeType(e, state.LastSync), ChangedAt: now, Event: e}
+			u.Changes = append(u.Changes, ch)
+			switch ch.Type {
+			case "created":
+				result.Created++
+			case "updated":
+				result.Updated++
+			case "cancelled":
+				result.Cancelled++
+			}
+
+			if e.Recurrence != "" || e.RecurringEventID != "" || e.IsException {
+				refetch = true
+				continue
+			}
+			u.Removed = append(u.Removed, e.EventID)
+			start, end := EventBounds(e, loc)
+			if e.Status != "cancelled" && start.Before(u.State.WindowEnd) && end.After(u.State.WindowStart) {
+				u.Upserts = append(u.Upserts, e)
+			}
+		}
+
+		switch {
+		case refetch:
+			progress(opts, "Recurring events changed; refetching %s...\n", result.Calendar)
+			if u.Upserts, err = listWindow(client, cal.CalendarID, u.State.WindowStart, u.State.WindowEnd); err != nil {
+				return nil, err
+			}
+			u.Replace = true
+			u.Removed = nil
+		case u.State.WindowEnd.After(state.WindowEnd):
+			// The window has moved on since the last sync; fill in its new tail
+			tail, err := listWindow(client, cal.CalendarID, state.WindowEnd, u.State.WindowEnd)
+			if err != nil {
+				return nil, err
+			}
+			u.Upserts = append(u.Upserts, tail...)
+		}
+	}
+
+	if err := cache.ApplySync(u, loc); err != nil {
+		return nil, err
+	}
+
+	result.TotalCached, err = cache.CountInstances(cal.CalendarID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case result.FullSync:
+		result.Message = fmt.Sprintf("cached %d events", result.TotalCached)
+	case len(u.Changes) == 0:
+		result.Message = "already up to date"
+	default:
+		result.Message = fmt.Sprintf("%d created, %d updated, %d cancelled", result.Created, result.Updated, result.Cancelled)
+	}
+	return result, nil
+}
+
+// listWindow lists the instances between from and to
+func listWindow(client *api.Client, calendarID string, from, to time.Time) ([]api.Event, error) {
+	events, err := client.ListEvents(api.ListEventsOptions{
+		CalendarID: calendarID,
+		StartTime:  from,
+		EndTime:    to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing events: %w", err)
+	}
+	return events, nil
+}
+
+// changeType classifies a changed event. Events created after the last sync
+// count as created, even if they were edited again since.
+func changeType(e api.Event, lastSync time.Time) string {
+	if e.Status == "cancelled" {
+		return "cancelled"
+	}
+	if created, err := strconv.ParseInt(e.CreateTime, 10, 64); err == nil && created >= lastSync.Unix() {
+		return "created"
+	}
+	return "updated"
+}
+
+func progress(opts *SyncOptions, format string, args ...any) {
+	if opts.Progress != nil {
+		fmt.Fprintf(opts.Progress, format, args...)
+	}
+}
@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/directory"
+	"github.com/yjwong/lark-cli/internal/output"
+	"github.com/yjwong/lark-cli/internal/parallel"
+)
+
+// aclLookupWorkers bounds concurrent user lookups in acl list
+const aclLookupWorkers = 5
+
+var aclCmd = &cobra.Command{
+	Use:   "acl",
+	Short: "Manage who can access a calendar",
+	Long: `List, grant and revoke access to a calendar.
+
+Roles, from least to most access:
+  free_busy_reader   sees only when the calendar is busy
+  reader             sees event details
+  writer             creates and edits events
+  owner              also manages sharing and settings
+
+Members are given by email, name (@alice), open_id (ou_...), alias from
+config, group chat (oc_..., chat:<name>) or department (dept:<name>). A group
+is expanded to its current members; people who join later are not added. Listing group members requires the bot to be in the chat.`,
+}
+
+// --- List ---
+
+var aclListCmd = &cobra.Command{
+	Use:   "list <calendar>",
+	Short: "List a calendar's access control entries",
+	Long: `List who has access to a calendar and with which role.
+
+Examples:
+  lark cal acl list "Team Calendar"
+  lark cal acl list feishu.cn_xxxxxxxx@group.calendar.feishu.cn`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := api.NewClient()
+
+		cal, err := findCalendar(client, args[0])
+		if err != nil {
+			output.Fatal("CALENDAR_ERROR", err)
+		}
+
+		acls, err := client.ListCalendarACLs(cal.CalendarID)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+
+		outACLs := make([]api.OutputCalendarACL, len(acls))
+		for i, a := range acls {
+			outACLs[i] = api.OutputCalendarACL{
+				ACLID:  a.ACLID,
+				Role:   a.Role,
+				Type:   a.Scope.Type,
+				UserID: a.Scope.UserID,
+			}
+		}
+
+		// Names are a convenience; entries stay listed if a lookup fails
+		parallel.Run(len(outACLs), aclLookupWorkers, func(i int) {
+			if outACLs[i].UserID == "" {
+				return
+			}
+			if user, err := client.GetUser(outACLs[i].UserID, "open_id"); err == nil && user != nil {
+				outACLs[i].Name = user.Name
+				outACLs[i].Email = user.Email
+			}
+		})
+
+		output.JSON(map[string]interface{}{
+			"calendar_id": cal.CalendarID,
+			"calendar":    cal.DisplayName(),
+			"acls":        outACLs,
+			"count":       len(outACLs),
+		})
+	},
+}
+
+// --- Add ---
+
+var aclRole string
+
+var aclAddCmd = &cobra.Command{
+	Use:   "add <calendar> <member>...",
+	Short: "Grant access to a calendar",
+	Long: `Grant users or group members a role on a calendar.
+
+Examples:
+  lark cal acl add "Team Calendar" alice@example.com bob@example.com
+  lark cal acl add "Team Calendar" oc_xxxxxxxx --role writer
+  lark cal acl add "Project X" ou_xxxxxxxx --role free_busy_reader`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := validateACLRole(aclRole); err != nil {
+			output.Fatal("VALIDATION_ERROR", err)
+		}
+
+		client := api.NewClient()
+
+		cal, err := findCalendar(client, args[0])
+		if err != nil {
+			output.Fatal("CALENDAR_ERROR", err)
+		}
+
+		members, err := resolveACLMembers(client, args[1:])
+		if err != nil {
+			output.Fatal("USER_ERROR", err)
+		}
+
+		added := []api.OutputCalendarACL{}
+		for _, m := range members {
+			acl, err := client.CreateCalendarACL(cal.CalendarID, m.UserID, aclRole)
+			if err != nil {
+				output.Fatalf("API_ERROR", "Failed to add %s: %v", m.Ref, err)
+			}
+			added = append(added, api.OutputCalendarACL{
+				ACLID:  acl.ACLID,
+				Role:   acl.Role,
+				Type:   acl.Scope.Type,
+				UserID: acl.Scope.UserID,
+				Name:   m.Name,
+				Email:  m.Email,
+			})
+		}
+
+		output.JSON(map[string]interface{}{
+			"success": true,
+			"message": fmt.Sprintf("Granted %s on %s to %d member(s)", aclRole, cal.DisplayName(), len(added)),
+			"added":   added,
+		})
+	},
+}
+
+// --- Remove ---
+
+var aclRemoveCmd = &cobra.Command{
+	Use:   "remove <calendar> <member|acl-id>...",
+	Short: "Revoke access to a calendar",
+	Long: `Remove access control entries from a calendar, by ACL ID (see 'cal acl
+list') or by member. A group removes the entries of its current members.
+
+Examples:
+  lark cal acl remove "Team Calendar" alice@example.com
+  lark cal acl remove "Team Calendar" user_xxxxxxxx
+  lark cal acl remove "Team Calendar" chat:oc_xxxxxxxx`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := api.NewClient()
+
+		cal, err := findCalendar(client, args[0])
+		if err != nil {
+			output.Fatal("CALENDAR_ERROR", err)
+		}
+
+		acls, err := client.ListCalendarACLs(cal.CalendarID)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+		byID := make(map[string]api.CalendarACL, len(acls))
+		byUser := make(map[string]api.CalendarACL, len(acls))
+		for _, a := range acls {
+			byID[a.ACLID] = a
+			if a.Scope.UserID != "" {
+				byUser[a.Scope.UserID] = a
+			}
+		}
+
+		// Work out every entry to remove before removing any
+		var toRemove []api.CalendarACL
+		seen := make(map[string]bool)
+		var memberRefs []string
+		for _, ref := range args[1:] {
+			if a, ok := byID[ref]; ok {
+				if !seen[a.ACLID] {
+					seen[a.ACLID] = true
+					toRemove = append(toRemove, a)
+				}
+				continue
+			}
+			memberRefs = append(memberRefs, ref)
+		}
+		if len(memberRefs) > 0 {
+			members, err := resolveACLMembers(client, memberRefs)
+			if err != nil {
+				output.Fatal("USER_ERROR", err)
+			}
+			var missing []string
+			for _, m := range members {
+				a, ok := byUser[m.UserID]
+				if !ok {
+					if !m.FromGroup {
+						missing = append(missing, m.Ref)
+					}
+					continue
+				}
+				if !seen[a.ACLID] {
+					seen[a.ACLID] = true
+					toRemove = append(toRemove, a)
+				}
+			}
+			if len(missing) > 0 {
+				output.Fatalf("NOT_FOUND", "No access entry on %s for: %s", cal.DisplayName(), strings.Join(missing, ", "))
+			}
+		}
+
+		removed := []api.OutputCalendarACL{}
+		for _, a := range toRemove {
+			if err := client.DeleteCalendarACL(cal.CalendarID, a.ACLID); err != nil {
+				output.Fatalf("API_ERROR", "Failed to remove %s: %v", a.ACLID, err)
+			}
+			removed = append(removed, api.OutputCalendarACL{
+				ACLID:  a.ACLID,
+				Role:   a.Role,
+				Type:   a.Scope.Type,
+				UserID: a.Scope.UserID,
+			})
+		}
+
+		output.JSON(map[string]interface{}{
+			"success": true,
+			"message": fmt.Sprintf("Removed %d member(s) from %s", len(removed), cal.DisplayName()),
+			"removed": removed,
+		})
+	},
+}
+
+func init() {
+	aclAddCmd.Flags().StringVar(&aclRole, "role", "reader", "Role to grant: free_busy_reader, reader, writer, or owner")
+
+	aclCmd.AddCommand(aclListCmd)
+	aclCmd.AddCommand(aclAddCmd)
+	aclCmd.AddCommand(aclRemoveCmd)
+}
+
+// validateACLRole checks the value passed to --role
+func validateACLRole(role string) error {
+	switch role {
+	case "free_busy_reader", "reader", "writer", "owner":
+		return nil
+	default:
+		return fmt.Errorf("invalid role: %s (must be free_busy_reader, reader, writer, or owner)", role)
+	}
+}
+
+// aclMember is a user resolved from a member reference
+type aclMember struct {
+	Ref       string // What the user typed
+	UserID    string // open_id
+	Name      string
+	Email     string
+	FromGroup bool // Expanded from a group chat or department
+}
+
+// resolveACLMembers turns emails, names, open_ids, aliases, group chats and
+// departments into users. Groups expand to their members; duplicates are
+// dropped.
+func resolveACLMembers(client *api.Client, refs []string) ([]aclMember, error) {
+	ids, err := resolveRefs(client, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []aclMember
+	seen := make(map[string]bool)
+	add := func(m aclMember) {
+		if !seen[m.UserID] {
+			seen[m.UserID] = true
+			members = append(members, m)
+		}
+	}
+
+	resolver := &directory.Resolver{Client: client}
+	for _, id := range ids {
+		switch id.Kind {
+		case directory.KindUser:
+			add(aclMember{Ref: id.Ref, UserID: id.ID, Name: id.Name, Email: id.Email})
+		case directory.KindChat, directory.KindDepartment:
+			group, err := resolver.Members(id)
+			if err != nil {
+				return nil, err
+			}
+			for _, m := range group {
+				add(aclMember{Ref: id.Ref, UserID: m.ID, Name: m.Name, Email: m.Email, FromGroup: true})
+			}
+		default:
+			return nil, fmt.Errorf("no Lark user found for %s", id.Ref)
+		}
+	}
+	return members, nil
+}
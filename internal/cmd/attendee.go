@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/yjwong/lark-cli/internal/api"
@@ -20,6 +19,7 @@ var attendeeCmd = &cobra.Command{
 var (
 	addAttendeeEmails   []string
 	addAttendeeUsers    []string
+	addAttendeeRooms    []string
 	addAttendeeSelf     bool
 	addAttendeeOptional bool
 	addAttendeeNoNotify bool
@@ -34,19 +34,21 @@ Examples:
   lark cal attendee add <event-id> --self
   lark cal attendee add <event-id> --email user@example.com
   lark cal attendee add <event-id> --user ou_xxxxxxxx
+  lark cal attendee add <event-id> --user @alice --user "chat:Team Standup" --user dept:Design
+  lark cal attendee add <event-id> --room omm_xxxxxxxx
   lark cal attendee add <event-id> --self --email user@example.com --optional`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		eventID := args[0]
 
-		if !addAttendeeSelf && len(addAttendeeEmails) == 0 && len(addAttendeeUsers) == 0 {
-			output.Fatalf("VALIDATION_ERROR", "at least one of --self, --email, or --user is required")
+		if !addAttendeeSelf && len(addAttendeeEmails) == 0 && len(addAttendeeUsers) == 0 && len(addAttendeeRooms) == 0 {
+			output.Fatalf("VALIDATION_ERROR", "at least one of --self, --email, --user, or --room is required")
 		}
 
 		client := api.NewClient()
 
-		// Get primary calendar
-		cal, err := client.GetPrimaryCalendar()
+		// Get the selected calendar (primary unless --calendar is set)
+		cal, err := resolveCalendar(client)
 		if err != nil {
 			output.Fatal("CALENDAR_ERROR", err)
 		}
@@ -66,45 +68,28 @@ Examples:
 			})
 		}
 
-		// Add email attendees (auto-resolve to internal Lark users)
-		var trimmedEmails []string
-		for _, email := range addAttendeeEmails {
-			email = strings.TrimSpace(email)
-			if email != "" {
-				trimmedEmails = append(trimmedEmails, email)
-			}
+		// Add people and chats; emails of Lark users become users, other
+		// emails external attendees
+		parsed, err := parseAttendees(client, addAttendeeEmails)
+		if err != nil {
+			output.Fatal("VALIDATION_ERROR", err)
 		}
-		if len(trimmedEmails) > 0 {
-			resolved := resolveEmails(client, trimmedEmails)
-			for _, email := range trimmedEmails {
-				if userID, ok := resolved[email]; ok {
-					attendees = append(attendees, api.Attendee{
-						Type:       "user",
-						UserID:     userID,
-						IsOptional: addAttendeeOptional,
-					})
-				} else {
-					attendees = append(attendees, api.Attendee{
-						Type:            "third_party",
-						ThirdPartyEmail: email,
-						IsOptional:      addAttendeeOptional,
-					})
-				}
-			}
+		users, err := resolveAttendeeRefs(client, addAttendeeUsers)
+		if err != nil {
+			output.Fatal("VALIDATION_ERROR", err)
+		}
+		parsed = append(parsed, peopleAttendees(users)...)
+		for _, att := range parsed {
+			att.IsOptional = addAttendeeOptional
+			attendees = append(attendees, att)
 		}
 
-		// Add user attendees by open_id
-		for _, userID := range addAttendeeUsers {
-			userID = strings.TrimSpace(userID)
-			if userID == "" {
-				continue
-			}
-			attendees = append(attendees, api.Attendee{
-				Type:       "user",
-				UserID:     userID,
-				IsOptional: addAttendeeOptional,
-			})
+		// Add meeting rooms as resource attendees
+		rooms, err := resolveRooms(client, addAttendeeRooms)
+		if err != nil {
+			output.Fatal("VALIDATION_ERROR", err)
 		}
+		attendees = append(attendees, rooms...)
 
 		if len(attendees) == 0 {
 			output.Fatalf("VALIDATION_ERROR", "no valid attendees to add")
@@ -139,6 +124,8 @@ Examples:
 
 var (
 	removeAttendeeIDs     []string
+	removeAttendeeEmails  []string
+	removeAttendeeUsers   []string
 	removeAttendeeSelf    bool
 	removeAttendeeNoNotify bool
 )
@@ -148,18 +135,22 @@ var attendeeRemoveCmd = &cobra.Command{
 	Short: "Remove attendees from an event",
 	Long: `Remove one or more attendees from an existing calendar event.
 
+Attendees can be given by attendee ID (see 'lark cal attendee list') or by
+email, name, open_id, chat:<name>, dept:<name> or alias.
+
 Examples:
   lark cal attendee remove <event-id> --self
   lark cal attendee remove <event-id> --id user_xxxxx
-  lark cal attendee remove <event-id> --id user_xxxxx --id third_party_xxxxx`,
+  lark cal attendee remove <event-id> --id user_xxxxx --id third_party_xxxxx
+  lark cal attendee remove <event-id> --email bob@example.com --user @alice`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		eventID := args[0]
 
 		client := api.NewClient()
 
-		// Get primary calendar
-		cal, err := client.GetPrimaryCalendar()
+		// Get the selected calendar (primary unless --calendar is set)
+		cal, err := resolveCalendar(client)
 		if err != nil {
 			output.Fatal("CALENDAR_ERROR", err)
 		}
@@ -196,8 +187,22 @@ Examples:
 		// Add explicit IDs
 		attendeeIDs = append(attendeeIDs, removeAttendeeIDs...)
 
+		// Match people and chats against the current attendees
+		refs := append(append([]string{}, removeAttendeeEmails...), removeAttendeeUsers...)
+		if len(refs) > 0 {
+			attendees, err := client.ListEventAttendees(cal.CalendarID, eventID)
+			if err != nil {
+				output.Fatal("API_ERROR", err)
+			}
+			matched, err := matchAttendees(client, attendees, refs)
+			if err != nil {
+				output.Fatal("RESOLVE_ERROR", err)
+			}
+			attendeeIDs = append(attendeeIDs, matched...)
+		}
+
 		if len(attendeeIDs) == 0 {
-			output.Fatalf("VALIDATION_ERROR", "at least one of --self or --id is required")
+			output.Fatalf("VALIDATION_ERROR", "at least one of --self, --id, --email or --user is required")
 		}
 
 		// Remove attendees
@@ -229,8 +234,8 @@ Examples:
 
 		client := api.NewClient()
 
-		// Get primary calendar
-		cal, err := client.GetPrimaryCalendar()
+		// Get the selected calendar (primary unless --calendar is set)
+		cal, err := resolveCalendar(client)
 		if err != nil {
 			output.Fatal("CALENDAR_ERROR", err)
 		}
@@ -275,13 +280,16 @@ Examples:
 func init() {
 	// Add command flags
 	attendeeAddCmd.Flags().StringSliceVar(&addAttendeeEmails, "email", []string{}, "Add attendee by email (repeatable)")
-	attendeeAddCmd.Flags().StringSliceVar(&addAttendeeUsers, "user", []string{}, "Add attendee by Lark user ID/open_id (repeatable)")
+	attendeeAddCmd.Flags().StringSliceVar(&addAttendeeUsers, "user", []string{}, "Add attendee: open_id, user_id, name, @alias, chat:<name> or dept:<name> (repeatable)")
+	attendeeAddCmd.Flags().StringSliceVar(&addAttendeeRooms, "room", []string{}, "Add meeting room by room ID or name (repeatable)")
 	attendeeAddCmd.Flags().BoolVar(&addAttendeeSelf, "self", false, "Add yourself as an attendee")
 	attendeeAddCmd.Flags().BoolVar(&addAttendeeOptional, "optional", false, "Mark attendee(s) as optional")
 	attendeeAddCmd.Flags().BoolVar(&addAttendeeNoNotify, "no-notify", false, "Don't send notifications")
 
 	// Remove command flags
 	attendeeRemoveCmd.Flags().StringSliceVar(&removeAttendeeIDs, "id", []string{}, "Attendee ID to remove (repeatable)")
+	attendeeRemoveCmd.Flags().StringSliceVar(&removeAttendeeEmails, "email", []string{}, "Remove attendee by email (repeatable)")
+	attendeeRemoveCmd.Flags().StringSliceVar(&removeAttendeeUsers, "user", []string{}, "Remove attendee: open_id, user_id, name, @alias, chat:<name> or dept:<name> (repeatable)")
 	attendeeRemoveCmd.Flags().BoolVar(&removeAttendeeSelf, "self", false, "Remove yourself from the event")
 	attendeeRemoveCmd.Flags().BoolVar(&removeAttendeeNoNotify, "no-notify", false, "Don't send notifications")
 
@@ -289,4 +297,5 @@ func init() {
 	attendeeCmd.AddCommand(attendeeAddCmd)
 	attendeeCmd.AddCommand(attendeeRemoveCmd)
 	attendeeCmd.AddCommand(attendeeListCmd)
+	attendeeCmd.AddCommand(attendeeSummaryCmd)
 }
@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/config"
+	"github.com/yjwong/lark-cli/internal/output"
+	"github.com/yjwong/lark-cli/internal/parallel"
+)
+
+// attendeeLookupWorkers bounds concurrent contact lookups and nudges
+const attendeeLookupWorkers = 5
+
+// rsvpStatuses are the RSVP groups, in output order
+var rsvpStatuses = []string{"accept", "tentative", "decline", "needs_action"}
+
+var (
+	attendeeSummaryNudge   bool
+	attendeeSummaryMessage string
+	attendeeSummaryDryRun  bool
+)
+
+var attendeeSummaryCmd = &cobra.Command{
+	Use:   "summary <event-id>",
+	Short: "Summarize who has responded to an event",
+	Long: `Group an event's attendees by RSVP status (accept, tentative, decline,
+needs_action), separating required from optional attendees.
+
+Group chat attendees are expanded into their members, each with their own
+RSVP, and names and emails are looked up in contacts. Meeting rooms are
+listed separately.
+
+With --nudge, everyone who hasn't responded is sent a reminder by the bot
+(the app needs permission to send messages). The organizer and external
+attendees are skipped. Use --dry-run to see who would be nudged.
+
+Examples:
+  lark cal attendee summary <event-id>
+  lark cal attendee summary <event-id> --nudge --dry-run
+  lark cal attendee summary <event-id> --nudge --message "Please RSVP by Friday"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		eventID := args[0]
+		if (attendeeSummaryMessage != "" || attendeeSummaryDryRun) && !attendeeSummaryNudge {
+			output.Fatalf("VALIDATION_ERROR", "--message and --dry-run require --nudge")
+		}
+
+		tz := config.GetTimezone()
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			loc = time.Local
+		}
+
+		client := api.NewClient()
+
+		// Get the selected calendar (primary unless --calendar is set)
+		cal, err := resolveCalendar(client)
+		if err != nil {
+			output.Fatal("CALENDAR_ERROR", err)
+		}
+
+		event, err := client.GetEvent(cal.CalendarID, eventID)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+		attendees, err := client.ListEventAttendees(cal.CalendarID, eventID)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+
+		people, rooms, err := expandAttendees(client, cal.CalendarID, eventID, attendees)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+
+		// Names are a convenience; people stay listed if a lookup fails
+		parallel.Run(len(people), attendeeLookupWorkers, func(i int) {
+			if people[i].UserID == "" || people[i].IsExternal {
+				return
+			}
+			if user, err := client.GetUser(people[i].UserID, "open_id"); err == nil && user != nil {
+				if user.Name != "" {
+					people[i].Name = user.Name
+				}
+				people[i].Email = user.Email
+			}
+		})
+
+		required := make(map[string][]api.OutputRsvpPerson, len(rsvpStatuses))
+		optional := make(map[string][]api.OutputRsvpPerson, len(rsvpStatuses))
+		counts := make(map[string]int, len(rsvpStatuses)+1)
+		for _, status := range rsvpStatuses {
+			required[status] = []api.OutputRsvpPerson{}
+			optional[status] = []api.OutputRsvpPerson{}
+			counts[status] = 0
+		}
+		for _, p := range people {
+			status := p.RsvpStatus
+			if _, ok := required[status]; !ok {
+				status = "needs_action"
+			}
+			if p.IsOptional {
+				optional[status] = append(optional[status], p)
+			} else {
+				required[status] = append(required[status], p)
+			}
+			counts[status]++
+		}
+		counts["total"] = len(people)
+
+		result := map[string]interface{}{
+			"event_id": eventID,
+			"summary":  event.Summary,
+			"start":    api.ConvertToOutputEvent(*event).Start,
+			"counts":   counts,
+			"required": required,
+			"optional": optional,
+			"rooms":    rooms,
+		}
+
+		if attendeeSummaryNudge {
+			var pending []api.OutputRsvpPerson
+			for _, p := range append(required["needs_action"], optional["needs_action"]...) {
+				if p.UserID != "" && !p.IsExternal && !p.IsOrganizer {
+					pending = append(pending, p)
+				}
+			}
+
+			text := attendeeSummaryMessage
+			if text == "" {
+				text = fmt.Sprintf("Reminder: please RSVP to \"%s\" (%s).", event.Summary, formatNudgeTime(event.StartTime, loc))
+			}
+
+			nudged := make([]map[string]interface{}, len(pending))
+			if !attendeeSummaryDryRun {
+				content, err := buildTextContent(text)
+				if err != nil {
+					output.Fatal("VALIDATION_ERROR", err)
+				}
+				parallel.Run(len(pending), attendeeLookupWorkers, func(i int) {
+					nudged[i] = map[string]interface{}{"user_id": pending[i].UserID, "name": pending[i].Name}
+					if _, err := client.SendMessage("open_id", pending[i].UserID, "text", content); err != nil {
+						nudged[i]["error"] = err.Error()
+					} else {
+						nudged[i]["sent"] = true
+					}
+				})
+			} else {
+				for i, p := range pending {
+					nudged[i] = map[string]interface{}{"user_id": p.UserID, "name": p.Name}
+				}
+			}
+
+			result["dry_run"] = attendeeSummaryDryRun
+			result["message"] = text
+			result["nudged"] = nudged
+		}
+
+		output.JSON(result)
+	},
+}
+
+func init() {
+	attendeeSummaryCmd.Flags().BoolVar(&attendeeSummaryNudge, "nudge", false, "Message everyone who hasn't responded")
+	attendeeSummaryCmd.Flags().StringVar(&attendeeSummaryMessage, "message", "", "Reminder text (default names the event and its start)")
+	attendeeSummaryCmd.Flags().BoolVar(&attendeeSummaryDryRun, "dry-run", false, "List who would be nudged without sending")
+}
+
+// expandAttendees flattens an event's attendees into people, expanding group
+// chats into their members. Someone invited both directly and through a chat
+// is listed once, as their direct invitation. Rooms are returned by name.
+func expandAttendees(client *api.Client, calendarID, eventID string, attendees []api.Attendee) ([]api.OutputRsvpPerson, []string, error) {
+	var people []api.OutputRsvpPerson
+	rooms := []string{}
+	index := make(map[string]int) // open_id to position in people
+
+	add := func(p api.OutputRsvpPerson, direct bool) {
+		if p.UserID == "" {
+			people = append(people, p)
+			return
+		}
+		if i, ok := index[p.UserID]; ok {
+			if direct {
+				people[i] = p
+			}
+			return
+		}
+		index[p.UserID] = len(people)
+		people = append(people, p)
+	}
+
+	for _, att := range attendees {
+		switch att.Type {
+		case "resource":
+			rooms = append(rooms, att.DisplayName)
+		case "chat":
+			members, err := client.ListChatMemberAttendees(calendarID, eventID, att.AttendeeID)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to expand group %s: %w", att.DisplayName, err)
+			}
+			for _, m := range members {
+				add(api.OutputRsvpPerson{
+					UserID:      m.OpenID,
+					Name:        m.DisplayName,
+					RsvpStatus:  m.RsvpStatus,
+					IsOptional:  m.IsOptional,
+					IsOrganizer: m.IsOrganizer,
+					IsExternal:  m.IsExternal,
+					Via:         att.DisplayName,
+				}, false)
+			}
+		case "third_party":
+			name := att.DisplayName
+			if name == "" {
+				name = att.ThirdPartyEmail
+			}
+			add(api.OutputRsvpPerson{
+				Name:       name,
+				Email:      att.ThirdPartyEmail,
+				RsvpStatus: att.RsvpStatus,
+				IsOptional: att.IsOptional,
+				IsExternal: true,
+			}, true)
+		default:
+			add(api.OutputRsvpPerson{
+				UserID:      att.UserID,
+				Name:        att.DisplayName,
+				RsvpStatus:  att.RsvpStatus,
+				IsOptional:  att.IsOptional,
+				IsOrganizer: att.IsOrganizer,
+				IsExternal:  att.IsExternal,
+			}, true)
+		}
+	}
+	return people, rooms, nil
+}
+
+// formatNudgeTime formats an event start for a reminder message
+func formatNudgeTime(ti *api.TimeInfo, loc *time.Location) string {
+	if ti == nil {
+		return "no start time"
+	}
+	if ti.Date != "" {
+		return eventTime(ti, loc).Format("Mon 2 Jan")
+	}
+	return eventTime(ti, loc).Format("Mon 2 Jan 15:04 MST")
+}
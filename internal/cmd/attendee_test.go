@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/config"
+)
+
+// --user keeps taking raw user_ids alongside names and open_ids
+func TestResolveAttendeeRefs(t *testing.T) {
+	t.Setenv("LARK_CONFIG_DIR", t.TempDir())
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		ref  string
+		want []api.Attendee
+	}{
+		{"user_id with a digit", "1a2b3c4d", []api.Attendee{{Type: "user", UserID: "1a2b3c4d"}}},
+		{"user_id that names nobody", "abcdefg", []api.Attendee{{Type: "user", UserID: "abcdefg"}}},
+		{"open_id", "ou_abc123", []api.Attendee{{Type: "user", UserID: "ou_abc123"}}},
+		{"chat", "oc_abc123", []api.Attendee{{Type: "chat", ChatID: "oc_abc123"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			people, err := resolveAttendeeRefs(nil, []string{tt.ref})
+			if err != nil {
+				t.Fatalf("resolveAttendeeRefs(%q): %v", tt.ref, err)
+			}
+			if got := peopleAttendees(people); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	// A name that isn't a bare token is never taken as an ID
+	if _, err := resolveAttendeeRefs(nil, []string{"Nobody Here"}); err == nil {
+		t.Error("unknown full name: want an error")
+	}
+}
@@ -0,0 +1,420 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/config"
+	"github.com/yjwong/lark-cli/internal/directory"
+	"github.com/yjwong/lark-cli/internal/output"
+	"github.com/yjwong/lark-cli/internal/parallel"
+	"github.com/yjwong/lark-cli/internal/scheduler"
+	timex "github.com/yjwong/lark-cli/internal/time"
+)
+
+// availabilityWorkers bounds concurrent lookups in cal availability
+const availabilityWorkers = 8
+
+// heatmapShades are the heatmap cells, from nobody to everybody unavailable
+var heatmapShades = []string{"·", "░", "▒", "▓", "█"}
+
+var (
+	availabilityUsers       []string
+	availabilityFrom        string
+	availabilityTo          string
+	availabilityGranularity string
+	availabilityHours       string
+	availabilityWorkHours   string
+	availabilityFormat      string
+	availabilityBest        int
+)
+
+var availabilityCmd = &cobra.Command{
+	Use:   "availability",
+	Short: "Show how many people are busy in each slot",
+	Long: `Build a heatmap of busy counts for a team, per time slot.
+
+--users takes emails, names (@alice), open_ids, aliases from config,
+dept:<name or id> (users directly in the department) and chat:<name or id>
+(members of a group chat), comma-separated or repeated. Free/busy is fetched for everyone concurrently.
+
+--from and --to select days (default: the next 7 days). Each day is split into
+--granularity slots within --hours (default: the whole day) in your time zone.
+With --work-hours, people who are free but outside working hours in their own
+time zone are counted as off_hours.
+
+The JSON output has one row per day with busy and off_hours counts per slot,
+plus the best slots. --format heatmap draws the same matrix in the terminal.
+
+Examples:
+  lark cal availability --users dept:od_xxxxxxxx --from "next monday" --to "next friday"
+  lark cal availability --users chat:oc_xxxxxxxx,alice@example.com --granularity 1h --work-hours 09:00-18:00
+  lark cal availability --users dept:od_xxxxxxxx --hours 07:00-22:00 --format heatmap
+  lark cal availability --users "dept:Platform Engineering,@alice"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(availabilityUsers) == 0 {
+			output.Fatalf("VALIDATION_ERROR", "--users is required")
+		}
+		step, err := timex.ParseDuration(availabilityGranularity)
+		if err != nil {
+			output.Fatalf("PARSE_ERROR", "Failed to parse --granularity: %v", err)
+		}
+		if step < 5*time.Minute || (24*time.Hour)%step != 0 {
+			output.Fatalf("VALIDATION_ERROR", "--granularity must be at least 5m and divide a day evenly (e.g. 15m, 30m, 1h)")
+		}
+		dayStart, dayEnd := time.Duration(0), 24*time.Hour
+		if availabilityHours != "" {
+			dayStart, dayEnd, err = timex.ParseClockRange(availabilityHours)
+			if err != nil {
+				output.Fatal("VALIDATION_ERROR", err)
+			}
+		}
+		var opts scheduler.Options
+		if availabilityWorkHours != "" {
+			if _, _, err := timex.ParseClockRange(availabilityWorkHours); err != nil {
+				output.Fatal("VALIDATION_ERROR", err)
+			}
+			// Everyone's own holidays aren't known, so the calendar isn't read
+			opts.WorkingHours, err = loadWorkingHours(nil, availabilityWorkHours, time.Time{}, time.Time{})
+			if err != nil {
+				output.Fatal("CONFIG_ERROR", err)
+			}
+		}
+		switch availabilityFormat {
+		case "json", "heatmap":
+		default:
+			output.Fatalf("VALIDATION_ERROR", "Invalid --format: %s (use json or heatmap)", availabilityFormat)
+		}
+
+		// Parse timezone
+		tz := config.GetTimezone()
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			loc = time.Local
+			tz = loc.String()
+		}
+		now := time.Now().In(loc)
+
+		// Whole days from --from to --to
+		startTime := timex.StartOfDay(now)
+		if availabilityFrom != "" {
+			t, err := timex.Parse(availabilityFrom, loc)
+			if err != nil {
+				output.Fatalf("PARSE_ERROR", "Failed to parse --from: %v", err)
+			}
+			startTime = timex.StartOfDay(t)
+		}
+		endTime := timex.EndOfDay(startTime.AddDate(0, 0, 6))
+		if availabilityTo != "" {
+			t, err := timex.Parse(availabilityTo, loc)
+			if err != nil {
+				output.Fatalf("PARSE_ERROR", "Failed to parse --to: %v", err)
+			}
+			endTime = timex.EndOfDay(t)
+		}
+		if !endTime.After(startTime) {
+			output.Fatalf("VALIDATION_ERROR", "--to must be after --from")
+		}
+		if endTime.Sub(startTime) > 90*24*time.Hour {
+			output.Fatalf("VALIDATION_ERROR", "Time range cannot exceed 90 days")
+		}
+
+		client := api.NewClient()
+
+		members, unresolved, err := resolveAvailabilityMembers(client, availabilityUsers)
+		if err != nil {
+			output.Fatal("USER_ERROR", err)
+		}
+		if len(members) == 0 {
+			output.Fatalf("USER_ERROR", "No Lark users found in --users")
+		}
+
+		participants, failed := loadAvailability(client, members, loc, startTime, endTime, opts.WorkingHours.End > opts.WorkingHours.Start)
+		if len(participants) == 0 {
+			output.Fatalf("API_ERROR", "Could not get free/busy for any user: %s", failed[0]["error"])
+		}
+
+		// One row per day, one column per slot within --hours
+		var times []string
+		for off := dayStart; off+step <= dayEnd; off += step {
+			times = append(times, fmt.Sprintf("%02d:%02d", int(off.Hours()), int(off.Minutes())%60))
+		}
+		if len(times) == 0 {
+			output.Fatalf("VALIDATION_ERROR", "--hours is shorter than --granularity")
+		}
+
+		var days []availabilityDay
+		var loads []scheduler.Load
+		for day := startTime; day.Before(endTime); day = day.AddDate(0, 0, 1) {
+			row := availabilityDay{
+				Date:     day.Format("2006-01-02"),
+				Weekday:  day.Format("Mon"),
+				Busy:     make([]int, len(times)),
+				OffHours: make([]int, len(times)),
+			}
+			for i := range times {
+				offset := dayStart + time.Duration(i)*step
+				slotStart := time.Date(day.Year(), day.Month(), day.Day(), int(offset.Hours()), int(offset.Minutes())%60, 0, 0, loc)
+				load := scheduler.SlotLoad(participants, slotStart, slotStart.Add(step), opts)
+				row.Busy[i] = load.Busy
+				row.OffHours[i] = load.OffHours
+				loads = append(loads, load)
+			}
+			days = append(days, row)
+		}
+
+		if availabilityFormat == "heatmap" {
+			printHeatmap(os.Stdout, days, times, len(participants), step, tz)
+			return
+		}
+
+		outMembers := make([]map[string]interface{}, len(participants))
+		for i, p := range participants {
+			outMembers[i] = map[string]interface{}{
+				"user_id":  p.ID,
+				"name":     p.Name,
+				"timezone": p.Location.String(),
+			}
+		}
+
+		query := map[string]interface{}{
+			"from":        startTime.Format(time.RFC3339),
+			"to":          endTime.Format(time.RFC3339),
+			"granularity": int(step.Minutes()),
+			"timezone":    tz,
+		}
+		if availabilityWorkHours != "" {
+			query["work_hours"] = availabilityWorkHours
+		}
+
+		result := map[string]interface{}{
+			"query":        query,
+			"members":      outMembers,
+			"member_count": len(participants),
+			"times":        times,
+			"days":         days,
+			"best":         bestAvailability(loads, len(participants), availabilityBest, loc),
+		}
+		if len(unresolved) > 0 {
+			result["unresolved"] = unresolved
+		}
+		if len(failed) > 0 {
+			result["failed"] = failed
+		}
+		output.JSON(result)
+	},
+}
+
+func init() {
+	availabilityCmd.Flags().StringSliceVar(&availabilityUsers, "users", []string{}, "Emails, names, open_ids, aliases, dept:<name|id> or chat:<name|id> (comma-separated or repeatable)")
+	availabilityCmd.Flags().StringVar(&availabilityFrom, "from", "", "First day (default: today)")
+	availabilityCmd.Flags().StringVar(&availabilityTo, "to", "", "Last day (default: 6 days after --from)")
+	availabilityCmd.Flags().StringVar(&availabilityGranularity, "granularity", "30m", "Slot length (e.g. 15m, 30m, 1h)")
+	availabilityCmd.Flags().StringVar(&availabilityHours, "hours", "", "Time of day to cover in your time zone, e.g. 07:00-22:00 (default: whole day)")
+	availabilityCmd.Flags().StringVar(&availabilityWorkHours, "work-hours", "", "Count people outside these working hours in their own time zone, e.g. 09:00-18:00")
+	availabilityCmd.Flags().StringVar(&availabilityFormat, "format", "json", "Output format: json or heatmap")
+	availabilityCmd.Flags().IntVar(&availabilityBest, "best", 5, "Number of best slots to list")
+}
+
+// availabilityDay is one row of the matrix: counts per slot of a day
+type availabilityDay struct {
+	Date     string `json:"date"`
+	Weekday  string `json:"weekday"`
+	Busy     []int  `json:"busy"`
+	OffHours []int  `json:"off_hours"`
+}
+
+// availabilityMember is a user to check, with whatever the listing told us
+type availabilityMember struct {
+	ID       string
+	Name     string
+	TimeZone string
+}
+
+// resolveAvailabilityMembers expands --users into unique Lark users. Emails
+// that aren't Lark users are returned as unresolved.
+func resolveAvailabilityMembers(client *api.Client, values []string) ([]availabilityMember, []string, error) {
+	var members []availabilityMember
+	seen := make(map[string]bool)
+	add := func(m availabilityMember) {
+		if m.ID != "" && !seen[m.ID] {
+			seen[m.ID] = true
+			members = append(members, m)
+		}
+	}
+
+	var refs []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			refs = append(refs, v)
+		}
+	}
+	ids, err := resolveRefs(client, refs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var unresolved []string
+	for _, id := range ids {
+		switch id.Kind {
+		case directory.KindDepartment:
+			var pageToken string
+			for {
+				users, more, next, err := client.ListUsersByDepartment(id.ID, 50, pageToken)
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to list department %s: %w", id.ID, err)
+				}
+				for _, u := range users {
+					add(availabilityMember{ID: u.OpenID, Name: u.Name, TimeZone: u.TimeZone})
+				}
+				if !more {
+					break
+				}
+				pageToken = next
+			}
+		case directory.KindChat:
+			chatMembers, err := client.ListChatMembers(id.ID)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to list members of %s: %w", id.ID, err)
+			}
+			for _, cm := range chatMembers {
+				add(availabilityMember{ID: cm.MemberID, Name: cm.Name})
+			}
+		case directory.KindUser:
+			name := id.Name
+			if name == "" {
+				name = id.Email
+			}
+			add(availabilityMember{ID: id.ID, Name: name})
+		case directory.KindEmail:
+			unresolved = append(unresolved, id.Email)
+		}
+	}
+	return members, unresolved, nil
+}
+
+// loadAvailability fetches busy periods for every member concurrently. Names
+// and time zones missing from the listing are looked up when needed. Members
+// whose free/busy can't be read are reported rather than failing the run.
+func loadAvailability(client *api.Client, members []availabilityMember, loc *time.Location, from, to time.Time, needZones bool) ([]scheduler.Participant, []map[string]string) {
+	participants := make([]scheduler.Participant, len(members))
+	errs := make([]error, len(members))
+
+	parallel.Run(len(members), availabilityWorkers, func(i int) {
+		m := members[i]
+		if m.TimeZone == "" && (needZones || m.Name == "" || strings.Contains(m.Name, "@")) {
+			// scheduleParticipant looks up the name and time zone itself
+			participants[i], errs[i] = scheduleParticipant(client, m.ID, false, loc, from, to)
+			return
+		}
+
+		p := scheduler.Participant{ID: m.ID, Name: m.Name, Location: loc}
+		if m.TimeZone != "" {
+			if zone, err := time.LoadLocation(m.TimeZone); err == nil {
+				p.Location = zone
+			}
+		}
+		periods, err := client.GetFreebusy(api.FreebusyOptions{StartTime: from, EndTime: to, UserID: m.ID})
+		if err != nil {
+			errs[i] = fmt.Errorf("failed to get free/busy for %s: %w", m.Name, err)
+			return
+		}
+		for _, period := range periods {
+			start, err1 := time.Parse(time.RFC3339, period.StartTime)
+			end, err2 := time.Parse(time.RFC3339, period.EndTime)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			p.Busy = append(p.Busy, scheduler.Interval{Start: start, End: end})
+		}
+		participants[i] = p
+	})
+
+	var loaded []scheduler.Participant
+	var failed []map[string]string
+	for i, p := range participants {
+		if errs[i] != nil {
+			failed = append(failed, map[string]string{"user_id": members[i].ID, "error": errs[i].Error()})
+			continue
+		}
+		loaded = append(loaded, p)
+	}
+	return loaded, failed
+}
+
+// bestAvailability returns the slots where the most people can attend,
+// earliest first among equals
+func bestAvailability(loads []scheduler.Load, total, limit int, loc *time.Location) []map[string]interface{} {
+	sorted := append([]scheduler.Load(nil), loads...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Busy+sorted[i].OffHours < sorted[j].Busy+sorted[j].OffHours
+	})
+	if limit > len(sorted) {
+		limit = len(sorted)
+	}
+
+	best := []map[string]interface{}{}
+	for _, l := range sorted[:limit] {
+		best = append(best, map[string]interface{}{
+			"start":     l.Start.In(loc).Format(time.RFC3339),
+			"end":       l.End.In(loc).Format(time.RFC3339),
+			"busy":      l.Busy,
+			"off_hours": l.OffHours,
+			"available": total - l.Busy - l.OffHours,
+		})
+	}
+	return best
+}
+
+// printHeatmap draws the matrix with one character per slot, shaded by the
+// share of members who are busy or outside working hours
+func printHeatmap(w io.Writer, days []availabilityDay, times []string, total int, step time.Duration, tz string) {
+	fmt.Fprintf(w, "Unavailable members per %s slot (%d members, %s)\n\n", formatStep(step), total, tz)
+
+	// Hour labels above the first slot of each hour, where they fit
+	const labelIndent = 11
+	header := []rune(strings.Repeat(" ", labelIndent+len(times)+2))
+	next := 0
+	for i, t := range times {
+		if !strings.HasSuffix(t, ":00") || i < next {
+			continue
+		}
+		copy(header[labelIndent+i:], []rune(t[:2]))
+		next = i + 3
+	}
+	fmt.Fprintln(w, strings.TrimRight(string(header), " "))
+
+	for _, d := range days {
+		var row strings.Builder
+		for i := range times {
+			row.WriteString(heatmapShade(d.Busy[i]+d.OffHours[i], total))
+		}
+		fmt.Fprintf(w, "%-3s %s  %s\n", d.Weekday, d.Date[5:], row.String())
+	}
+
+	fmt.Fprintf(w, "\n%s none  %s ≤25%%  %s ≤50%%  %s ≤75%%  %s >75%%\n",
+		heatmapShades[0], heatmapShades[1], heatmapShades[2], heatmapShades[3], heatmapShades[4])
+}
+
+// heatmapShade picks the cell for n of total members unavailable
+func heatmapShade(n, total int) string {
+	if n == 0 || total == 0 {
+		return heatmapShades[0]
+	}
+	level := (n*4 + total - 1) / total // 1..4
+	return heatmapShades[level]
+}
+
+// formatStep renders a slot length like 30m or 1h
+func formatStep(d time.Duration) string {
+	if d%time.Hour == 0 {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dm", int(d.Minutes()))
+}
@@ -0,0 +1,754 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/config"
+	"github.com/yjwong/lark-cli/internal/output"
+	"github.com/yjwong/lark-cli/internal/parallel"
+	timex "github.com/yjwong/lark-cli/internal/time"
+)
+
+// defaultBulkWorkers bounds concurrent API calls in cal bulk
+const defaultBulkWorkers = 5
+
+var bulkCmd = &cobra.Command{
+	Use:   "bulk",
+	Short: "Decline, delete, shift or update many events at once",
+	Long: `Apply one change to every event matching a filter.
+
+Events are selected like 'cal list': --from/--to, --week or --today (the
+default), optionally across --all-calendars or only --pending invitations.
+Narrow the selection with:
+  --query           text in the summary, description or location (as 'cal search')
+  --match           summary text, case-insensitive; * matches anything
+  --organizer       organizer email, name, open_id, dept:<name>, alias or "me"
+  --organizer-name  text in the organizer's display name, case-insensitive
+
+Nothing changes without --apply. By default the planned changes are printed
+as a diff table on stderr and as JSON on stdout. With --apply they run
+concurrently (--workers at a time) and each event is reported as ok, failed
+or skipped.
+
+Occurrences of recurring events are changed one by one; the rest of the
+series is left alone.`,
+}
+
+// --- Event selection shared by all bulk commands ---
+
+var (
+	bulkFrom          string
+	bulkTo            string
+	bulkToday         bool
+	bulkWeek          bool
+	bulkAllCalendars  bool
+	bulkPending       bool
+	bulkQuery         string
+	bulkMatch         string
+	bulkOrganizer     string
+	bulkOrganizerName string
+	bulkApply         bool
+	bulkWorkers       int
+)
+
+func addBulkFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&bulkFrom, "from", "", "Start date (ISO 8601 or natural language)")
+	cmd.Flags().StringVar(&bulkTo, "to", "", "End date (ISO 8601 or natural language)")
+	cmd.Flags().BoolVar(&bulkToday, "today", false, "Select today's events (default)")
+	cmd.Flags().BoolVar(&bulkWeek, "week", false, "Select this week's events")
+	cmd.Flags().BoolVar(&bulkAllCalendars, "all-calendars", false, "Select events from all your calendars")
+	cmd.Flags().BoolVar(&bulkPending, "pending", false, "Only events awaiting your RSVP")
+	cmd.Flags().StringVar(&bulkQuery, "query", "", "Only events whose summary, description or location contains this text")
+	cmd.Flags().StringVar(&bulkMatch, "match", "", "Only events whose summary matches (case-insensitive, * wildcards)")
+	cmd.Flags().StringVar(&bulkOrganizer, "organizer", "", "Only events organized by this email, name, open_id, dept:<name>, alias or \"me\"")
+	cmd.Flags().StringVar(&bulkOrganizerName, "organizer-name", "", "Only events whose organizer's display name contains this text")
+	cmd.Flags().BoolVar(&bulkApply, "apply", false, "Make the changes (default is a dry run)")
+	cmd.Flags().IntVar(&bulkWorkers, "workers", defaultBulkWorkers, "Number of events changed concurrently")
+}
+
+// bulkTarget is a selected event with the calendar it came from
+type bulkTarget struct {
+	Event     api.Event
+	Calendar  api.Calendar
+	Rsvp      string // The current user's RSVP status
+	Organizer bool   // The current user organizes the event
+}
+
+// selectBulkEvents lists the events in the selected range and applies the
+// bulk filters. Attendees are fetched so organizer and RSVP are known.
+func selectBulkEvents(client *api.Client, startTime, endTime time.Time, loc *time.Location) ([]bulkTarget, error) {
+	var calendars []api.Calendar
+	if bulkAllCalendars {
+		cals, err := listReadableCalendars(client)
+		if err != nil {
+			return nil, err
+		}
+		calendars = cals
+	} else {
+		cal, err := resolveCalendar(client)
+		if err != nil {
+			return nil, err
+		}
+		calendars = []api.Calendar{*cal}
+	}
+
+	events, eventCalendars, err := fetchCalendarEvents(client, calendars, startTime, endTime, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cheap text filters first, so attendees are only fetched for candidates
+	var summaryRe *regexp.Regexp
+	if bulkMatch != "" {
+		summaryRe = summaryPattern(bulkMatch)
+	}
+	query := strings.ToLower(bulkQuery)
+
+	var targets []bulkTarget
+	for i, e := range events {
+		if e.Status == "cancelled" {
+			continue
+		}
+		if summaryRe != nil && !summaryRe.MatchString(e.Summary) {
+			continue
+		}
+		if query != "" && !eventContains(e, query) {
+			continue
+		}
+		targets = append(targets, bulkTarget{Event: e, Calendar: eventCalendars[i]})
+	}
+	if len(targets) == 0 {
+		return targets, nil
+	}
+
+	user, err := client.GetCurrentUser()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	parallel.Run(len(targets), bulkWorkers, func(i int) {
+		t := &targets[i]
+		attendees, err := client.ListEventAttendees(t.Calendar.CalendarID, t.Event.EventID)
+		if err == nil {
+			t.Event.Attendees = attendees
+		}
+		// Events on the organizer's own calendar carry its calendar ID
+		t.Organizer = eventOrganizerID(t.Event) == user.OpenID || t.Event.OrganizerCalendarID == t.Calendar.CalendarID
+		t.Rsvp = api.ExtractUserRsvpStatus(t.Event, user.OpenID, t.Calendar.CalendarID, client)
+	})
+
+	var organizerMatch func(bulkTarget) bool
+	if bulkOrganizer != "" {
+		organizerMatch = organizerMatcher(client, bulkOrganizer)
+	}
+	organizerName := strings.ToLower(bulkOrganizerName)
+
+	selected := targets[:0]
+	for _, t := range targets {
+		if bulkPending && t.Rsvp != "needs_action" {
+			continue
+		}
+		if organizerMatch != nil && !organizerMatch(t) {
+			continue
+		}
+		if organizerName != "" && !organizerNameMatches(t, organizerName) {
+			continue
+		}
+		selected = append(selected, t)
+	}
+	return selected, nil
+}
+
+// summaryPattern compiles a --match value: a case-insensitive substring, or a
+// whole-summary pattern when it contains * wildcards
+func summaryPattern(match string) *regexp.Regexp {
+	expr := strings.ReplaceAll(regexp.QuoteMeta(match), `\*`, ".*")
+	if strings.Contains(match, "*") {
+		expr = "^" + expr + "$"
+	}
+	return regexp.MustCompile("(?is)" + expr)
+}
+
+// eventContains reports whether the summary, description or location contains
+// the lower-cased query
+func eventContains(e api.Event, query string) bool {
+	text := e.Summary + "\n" + e.Description
+	if e.Location != nil {
+		text += "\n" + e.Location.Name
+	}
+	return strings.Contains(strings.ToLower(text), query)
+}
+
+// organizerMatcher matches the organizer against a person reference (email,
+// name, open_id, dept:<name> or alias), or "me". Since bulk commands change
+// what they select, a reference that doesn't resolve is an error rather than
+// a looser match.
+func organizerMatcher(client *api.Client, spec string) func(bulkTarget) bool {
+	if strings.EqualFold(spec, "me") {
+		return func(t bulkTarget) bool { return t.Organizer }
+	}
+
+	people, err := resolvePeople(client, []string{spec}, true)
+	if err != nil {
+		output.Fatalf("RESOLVE_ERROR", "%v (use --organizer-name to match display names)", err)
+	}
+
+	return func(t bulkTarget) bool {
+		for _, att := range t.Event.Attendees {
+			if !att.IsOrganizer {
+				continue
+			}
+			for _, p := range people {
+				if attendeeIs(att, p) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// organizerNameMatches reports whether the organizer's display name contains
+// the lower-cased name
+func organizerNameMatches(t bulkTarget, name string) bool {
+	for _, att := range t.Event.Attendees {
+		if att.IsOrganizer && strings.Contains(strings.ToLower(att.DisplayName), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// --- Plan, diff table and execution ---
+
+// bulkChange is one field changed on an event
+type bulkChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// bulkEntry is the per-event line of a bulk plan or report
+type bulkEntry struct {
+	EventID    string       `json:"event_id"`
+	Summary    string       `json:"summary"`
+	Start      string       `json:"start"`
+	CalendarID string       `json:"calendar_id,omitempty"`
+	Action     string       `json:"action"`           // decline, delete, shift, update or skip
+	Status     string       `json:"status"`           // planned, skipped, ok or failed
+	Reason     string       `json:"reason,omitempty"` // Why the event is skipped
+	Changes    []bulkChange `json:"changes,omitempty"`
+	Error      string       `json:"error,omitempty"`
+}
+
+// bulkOp pairs a plan entry with the call that applies it
+type bulkOp struct {
+	entry bulkEntry
+	apply func() error // nil when the event is skipped
+}
+
+// newBulkOp starts a plan entry for a target
+func newBulkOp(t bulkTarget, action string, loc *time.Location) bulkOp {
+	entry := bulkEntry{
+		EventID: t.Event.EventID,
+		Summary: t.Event.Summary,
+		Start:   formatBulkTime(t.Event.StartTime, loc),
+		Action:  action,
+		Status:  "planned",
+	}
+	if bulkAllCalendars {
+		entry.CalendarID = t.Calendar.CalendarID
+	}
+	return bulkOp{entry: entry}
+}
+
+// skip marks the op as left alone
+func (op *bulkOp) skip(reason string) {
+	op.entry.Action = "skip"
+	op.entry.Status = "skipped"
+	op.entry.Reason = reason
+	op.apply = nil
+}
+
+// runBulk prints the diff table, then either reports the plan (dry run) or
+// applies it with a bounded worker pool and reports each event's outcome
+func runBulk(action string, ops []bulkOp) {
+	printBulkTable(os.Stderr, ops)
+
+	if bulkApply {
+		parallel.Run(len(ops), bulkWorkers, func(i int) {
+			op := &ops[i]
+			if op.apply == nil {
+				return
+			}
+			if err := op.apply(); err != nil {
+				op.entry.Status = "failed"
+				op.entry.Error = err.Error()
+				return
+			}
+			op.entry.Status = "ok"
+		})
+	}
+
+	entries := make([]bulkEntry, len(ops))
+	counts := make(map[string]int)
+	for i, op := range ops {
+		entries[i] = op.entry
+		counts[op.entry.Status]++
+	}
+
+	result := map[string]interface{}{
+		"action":  action,
+		"dry_run": !bulkApply,
+		"matched": len(ops),
+		"skipped": counts["skipped"],
+		"events":  entries,
+	}
+	if bulkApply {
+		result["succeeded"] = counts["ok"]
+		result["failed"] = counts["failed"]
+	} else {
+		result["planned"] = counts["planned"]
+		fmt.Fprintf(os.Stderr, "\nDry run: %d of %d events would change. Re-run with --apply to make the changes.\n", counts["planned"], len(ops))
+	}
+	output.JSON(result)
+}
+
+// printBulkTable writes a human-readable diff of the plan
+func printBulkTable(w io.Writer, ops []bulkOp) {
+	if len(ops) == 0 {
+		fmt.Fprintln(w, "No events match.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "START\tSUMMARY\tCHANGE")
+	for _, op := range ops {
+		e := op.entry
+		var change string
+		switch {
+		case e.Status == "skipped":
+			change = "skip: " + e.Reason
+		case len(e.Changes) == 0:
+			change = e.Action
+		default:
+			parts := make([]string, len(e.Changes))
+			for i, c := range e.Changes {
+				parts[i] = fmt.Sprintf("%s: %s -> %s", c.Field, orNone(c.From), orNone(c.To))
+			}
+			change = strings.Join(parts, "; ")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", e.Start, truncateRunes(e.Summary, 40), change)
+	}
+	tw.Flush()
+}
+
+// orNone shows empty values in the diff table
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+// formatBulkTime formats an event time for the diff table
+func formatBulkTime(ti *api.TimeInfo, loc *time.Location) string {
+	if ti == nil {
+		return ""
+	}
+	if ti.Date != "" {
+		return ti.Date
+	}
+	return eventTime(ti, loc).Format("2006-01-02 15:04")
+}
+
+// truncateRunes shortens s to at most n runes, marking the cut with "..."
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-3]) + "..."
+}
+
+// bulkLocation returns the configured timezone
+func bulkLocation() *time.Location {
+	loc, err := time.LoadLocation(config.GetTimezone())
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// selectBulkTargets validates the selection flags and runs the selection for
+// a bulk command, exiting on error
+func selectBulkTargets(client *api.Client, loc *time.Location) []bulkTarget {
+	if bulkWorkers < 1 {
+		output.Fatalf("VALIDATION_ERROR", "--workers must be at least 1")
+	}
+	if bulkAllCalendars && calCalendar != "" {
+		output.Fatalf("VALIDATION_ERROR", "--calendar and --all-calendars cannot be used together")
+	}
+
+	startTime, endTime, err := eventRange(bulkFrom, bulkTo, bulkWeek, time.Now().In(loc), loc)
+	if err != nil {
+		output.Fatal("PARSE_ERROR", err)
+	}
+
+	targets, err := selectBulkEvents(client, startTime, endTime, loc)
+	if err != nil {
+		output.Fatal("API_ERROR", err)
+	}
+	return targets
+}
+
+// --- Decline ---
+
+var bulkDeclineCmd = &cobra.Command{
+	Use:   "decline",
+	Short: "Decline every matching invitation",
+	Long: `Decline every matching invitation.
+
+Events you organize, events you are not invited to and events you have
+already declined are skipped.
+
+Examples:
+  lark cal bulk decline --from "next friday" --to "next friday"
+  lark cal bulk decline --week --pending --match "*sync*" --apply`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := api.NewClient()
+		loc := bulkLocation()
+		targets := selectBulkTargets(client, loc)
+
+		ops := make([]bulkOp, len(targets))
+		for i, t := range targets {
+			op := newBulkOp(t, "decline", loc)
+			switch {
+			case t.Organizer:
+				op.skip("you organize this event")
+			case t.Rsvp == "":
+				op.skip("you are not an attendee")
+			case t.Rsvp == "decline":
+				op.skip("already declined")
+			default:
+				calID, eventID := t.Calendar.CalendarID, t.Event.EventID
+				op.entry.Changes = []bulkChange{{Field: "rsvp", From: t.Rsvp, To: "decline"}}
+				op.apply = func() error {
+					return client.ReplyToEvent(calID, eventID, "decline")
+				}
+			}
+			ops[i] = op
+		}
+		runBulk("decline", ops)
+	},
+}
+
+// --- Delete ---
+
+var bulkDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete every matching event",
+	Long: `Delete every matching event.
+
+Only the selected occurrences of recurring events are deleted. Deleting an
+event you don't organize removes it from your calendar only.
+
+Examples:
+  lark cal bulk delete --from 2026-03-02 --to 2026-03-06 --match "Focus time"
+  lark cal bulk delete --week --organizer me --query "offsite" --apply`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := api.NewClient()
+		loc := bulkLocation()
+		targets := selectBulkTargets(client, loc)
+
+		ops := make([]bulkOp, len(targets))
+		for i, t := range targets {
+			op := newBulkOp(t, "delete", loc)
+			calID, eventID := t.Calendar.CalendarID, t.Event.EventID
+			op.apply = func() error {
+				return client.DeleteEvent(calID, eventID)
+			}
+			ops[i] = op
+		}
+		runBulk("delete", ops)
+	},
+}
+
+// --- Shift ---
+
+var (
+	bulkShiftBy       string
+	bulkShiftNoNotify bool
+)
+
+var bulkShiftCmd = &cobra.Command{
+	Use:   "shift",
+	Short: "Move every matching event by a fixed amount",
+	Long: `Move every matching event earlier or later, keeping its duration.
+
+--by takes a duration such as 30m, 1h30m or "-15m" (earlier). All-day events
+are only moved by whole days. Events you can't edit are skipped.
+
+Examples:
+  lark cal bulk shift --week --match "1:1*" --organizer bob@example.com --by 30m
+  lark cal bulk shift --from tomorrow --to tomorrow --by -1h --apply`,
+	Run: func(cmd *cobra.Command, args []string) {
+		by, err := parseShift(bulkShiftBy)
+		if err != nil {
+			output.Fatal("VALIDATION_ERROR", err)
+		}
+
+		client := api.NewClient()
+		loc := bulkLocation()
+		targets := selectBulkTargets(client, loc)
+
+		ops := make([]bulkOp, len(targets))
+		for i, t := range targets {
+			op := newBulkOp(t, "shift", loc)
+			e := t.Event
+
+			req := &api.UpdateEventRequest{}
+			switch {
+			case !canEditEvent(t):
+				op.skip("only the organizer can move this event")
+			case e.StartTime == nil || e.EndTime == nil:
+				op.skip("event has no start or end time")
+			case e.StartTime.Date != "":
+				if by%(24*time.Hour) != 0 {
+					op.skip("all-day event; shift by whole days")
+					break
+				}
+				days := int(by / (24 * time.Hour))
+				req.StartTime = &api.TimeInfo{Date: eventTime(e.StartTime, loc).AddDate(0, 0, days).Format("2006-01-02")}
+				req.EndTime = &api.TimeInfo{Date: eventTime(e.EndTime, loc).AddDate(0, 0, days).Format("2006-01-02")}
+			default:
+				tz := e.StartTime.Timezone
+				if tz == "" {
+					tz = loc.String()
+				}
+				req.StartTime = &api.TimeInfo{Timestamp: strconv.FormatInt(eventTime(e.StartTime, loc).Add(by).Unix(), 10), Timezone: tz}
+				req.EndTime = &api.TimeInfo{Timestamp: strconv.FormatInt(eventTime(e.EndTime, loc).Add(by).Unix(), 10), Timezone: tz}
+			}
+
+			if op.entry.Status != "skipped" {
+				op.entry.Changes = []bulkChange{
+					{Field: "start", From: formatBulkTime(e.StartTime, loc), To: formatBulkTime(req.StartTime, loc)},
+					{Field: "end", From: formatBulkTime(e.EndTime, loc), To: formatBulkTime(req.EndTime, loc)},
+				}
+				if bulkShiftNoNotify {
+					noNotify := false
+					req.NeedNotify = &noNotify
+				}
+				op.apply = bulkUpdateCall(client, t, req)
+			}
+			ops[i] = op
+		}
+		runBulk("shift", ops)
+	},
+}
+
+// parseShift parses --by, allowing a leading sign on any duration form
+func parseShift(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("--by is required")
+	}
+	sign := time.Duration(1)
+	if strings.HasPrefix(s, "-") {
+		sign = -1
+	}
+	d, err := timex.ParseDuration(strings.TrimLeft(s, "+-"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid --by: %v", err)
+	}
+	if d == 0 {
+		return 0, fmt.Errorf("--by must not be zero")
+	}
+	return sign * d, nil
+}
+
+// canEditEvent reports whether the current user may change the event's details
+func canEditEvent(t bulkTarget) bool {
+	return t.Organizer || t.Event.AttendeeAbility == "can_modify_event"
+}
+
+// bulkUpdateCall returns the call that applies req to the target event
+func bulkUpdateCall(client *api.Client, t bulkTarget, req *api.UpdateEventRequest) func() error {
+	calID, eventID := t.Calendar.CalendarID, t.Event.EventID
+	return func() error {
+		_, err := client.UpdateEvent(calID, eventID, req)
+		return err
+	}
+}
+
+// --- Update ---
+
+var (
+	bulkUpdateSummary     string
+	bulkUpdateDescription string
+	bulkUpdateLocation    string
+	bulkUpdateColor       string
+	bulkUpdateVisibility  string
+	bulkUpdateVC          string
+	bulkUpdateNoNotify    bool
+)
+
+var bulkUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Change fields on every matching event",
+	Long: `Set the same fields on every matching event.
+
+Events that already have the new values, and events you can't edit, are
+skipped.
+
+Examples:
+  lark cal bulk update --week --match "Standup" --location "Room 4A"
+  lark cal bulk update --from 2026-04-01 --to 2026-04-30 --organizer me --query "review" --vc lark --apply`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var fields api.UpdateEventRequest
+		if bulkUpdateSummary != "" {
+			fields.Summary = bulkUpdateSummary
+		}
+		if bulkUpdateDescription != "" {
+			fields.Description = bulkUpdateDescription
+		}
+		if bulkUpdateLocation != "" {
+			fields.Location = &api.Location{Name: bulkUpdateLocation}
+		}
+		if bulkUpdateColor != "" {
+			color, err := parseHexColor(bulkUpdateColor)
+			if err != nil {
+				output.Fatalf("VALIDATION_ERROR", "Invalid color format: %v (use hex like #9CA2A9)", err)
+			}
+			fields.Color = &color
+		}
+		if bulkUpdateVisibility != "" {
+			switch bulkUpdateVisibility {
+			case "default", "public", "private":
+				fields.Visibility = bulkUpdateVisibility
+			default:
+				output.Fatalf("VALIDATION_ERROR", "Invalid visibility: %s (use default, public, or private)", bulkUpdateVisibility)
+			}
+		}
+		if bulkUpdateVC != "" {
+			vchat, err := parseVchat(bulkUpdateVC)
+			if err != nil {
+				output.Fatal("VALIDATION_ERROR", err)
+			}
+			fields.Vchat = vchat
+		}
+		if fields.Summary == "" && fields.Description == "" && fields.Location == nil &&
+			fields.Color == nil && fields.Visibility == "" && fields.Vchat == nil {
+			output.Fatalf("VALIDATION_ERROR", "Nothing to update: set at least one of --summary, --description, --location, --color, --visibility or --vc")
+		}
+
+		client := api.NewClient()
+		loc := bulkLocation()
+		targets := selectBulkTargets(client, loc)
+
+		ops := make([]bulkOp, len(targets))
+		for i, t := range targets {
+			op := newBulkOp(t, "update", loc)
+			req, changes := bulkUpdateDiff(t.Event, fields)
+			switch {
+			case !canEditEvent(t):
+				op.skip("only the organizer can change this event")
+			case len(changes) == 0:
+				op.skip("already up to date")
+			default:
+				if bulkUpdateNoNotify {
+					noNotify := false
+					req.NeedNotify = &noNotify
+				}
+				op.entry.Changes = changes
+				op.apply = bulkUpdateCall(client, t, req)
+			}
+			ops[i] = op
+		}
+		runBulk("update", ops)
+	},
+}
+
+// bulkUpdateDiff returns the request holding only the fields that differ from
+// the event, together with the changes for the diff
+func bulkUpdateDiff(e api.Event, fields api.UpdateEventRequest) (*api.UpdateEventRequest, []bulkChange) {
+	req := &api.UpdateEventRequest{}
+	var changes []bulkChange
+
+	if fields.Summary != "" && fields.Summary != e.Summary {
+		req.Summary = fields.Summary
+		changes = append(changes, bulkChange{Field: "summary", From: e.Summary, To: fields.Summary})
+	}
+	if fields.Description != "" && fields.Description != e.Description {
+		req.Description = fields.Description
+		changes = append(changes, bulkChange{Field: "description", From: truncateRunes(e.Description, 40), To: truncateRunes(fields.Description, 40)})
+	}
+	if fields.Location != nil {
+		var current string
+		if e.Location != nil {
+			current = e.Location.Name
+		}
+		if fields.Location.Name != current {
+			req.Location = fields.Location
+			changes = append(changes, bulkChange{Field: "location", From: current, To: fields.Location.Name})
+		}
+	}
+	if fields.Color != nil && *fields.Color != e.Color {
+		req.Color = fields.Color
+		changes = append(changes, bulkChange{Field: "color", From: fmt.Sprintf("#%06X", e.Color&0xFFFFFF), To: fmt.Sprintf("#%06X", *fields.Color&0xFFFFFF)})
+	}
+	if fields.Visibility != "" && fields.Visibility != e.Visibility {
+		req.Visibility = fields.Visibility
+		changes = append(changes, bulkChange{Field: "visibility", From: e.Visibility, To: fields.Visibility})
+	}
+	if fields.Vchat != nil {
+		current := &api.Vchat{}
+		if e.Vchat != nil {
+			current = e.Vchat
+		}
+		if fields.Vchat.VcType != current.VcType || (fields.Vchat.VcType == "third_party" && fields.Vchat.MeetingURL != current.MeetingURL) {
+			req.Vchat = fields.Vchat
+			changes = append(changes, bulkChange{Field: "vc", From: vchatLabel(current), To: vchatLabel(fields.Vchat)})
+		}
+	}
+	return req, changes
+}
+
+// vchatLabel describes a video meeting setting for the diff
+func vchatLabel(v *api.Vchat) string {
+	switch v.VcType {
+	case "third_party":
+		return v.MeetingURL
+	case "":
+		return "none"
+	default:
+		return v.VcType
+	}
+}
+
+func init() {
+	for _, c := range []*cobra.Command{bulkDeclineCmd, bulkDeleteCmd, bulkShiftCmd, bulkUpdateCmd} {
+		addBulkFlags(c)
+		bulkCmd.AddCommand(c)
+	}
+
+	bulkShiftCmd.Flags().StringVar(&bulkShiftBy, "by", "", "How far to move events, e.g. 30m, 1h or -15m (required)")
+	bulkShiftCmd.Flags().BoolVar(&bulkShiftNoNotify, "no-notify", false, "Don't send notifications")
+
+	bulkUpdateCmd.Flags().StringVar(&bulkUpdateSummary, "summary", "", "New event title")
+	bulkUpdateCmd.Flags().StringVar(&bulkUpdateDescription, "description", "", "New event description")
+	bulkUpdateCmd.Flags().StringVar(&bulkUpdateLocation, "location", "", "New event location")
+	bulkUpdateCmd.Flags().StringVar(&bulkUpdateColor, "color", "", "New event color (hex format, e.g., #9CA2A9)")
+	bulkUpdateCmd.Flags().StringVar(&bulkUpdateVisibility, "visibility", "", "New visibility (default, public, or private)")
+	bulkUpdateCmd.Flags().StringVar(&bulkUpdateVC, "vc", "", "Video meeting: lark, none, or url:<link>")
+	bulkUpdateCmd.Flags().BoolVar(&bulkUpdateNoNotify, "no-notify", false, "Don't send notifications")
+}
@@ -41,6 +41,8 @@ func validateScopeGroup(groupName string) {
 }
 
 func init() {
+	calCmd.PersistentFlags().StringVar(&calCalendar, "calendar", "", "Calendar ID or name (default: primary)")
+
 	calCmd.AddCommand(listCmd)
 	calCmd.AddCommand(showCmd)
 	calCmd.AddCommand(createCmd)
@@ -52,4 +54,23 @@ func init() {
 	calCmd.AddCommand(lookupUserCmd)
 	calCmd.AddCommand(commonFreetimeCmd)
 	calCmd.AddCommand(attendeeCmd)
+	calCmd.AddCommand(exportCmd)
+	calCmd.AddCommand(importCmd)
+	calCmd.AddCommand(roomsCmd)
+	calCmd.AddCommand(scheduleCmd)
+	calCmd.AddCommand(calendarsCmd)
+	calCmd.AddCommand(conflictsCmd)
+	calCmd.AddCommand(statsCmd)
+	calCmd.AddCommand(focusCmd)
+	calCmd.AddCommand(oooCmd)
+	calCmd.AddCommand(watchCmd)
+	calCmd.AddCommand(syncCmd)
+	calCmd.AddCommand(changesCmd)
+	calCmd.AddCommand(bulkCmd)
+	calCmd.AddCommand(aclCmd)
+	calCmd.AddCommand(createCalendarCmd)
+	calCmd.AddCommand(updateCalendarCmd)
+	calCmd.AddCommand(availabilityCmd)
+	calCmd.AddCommand(notesCmd)
+	calCmd.AddCommand(expandCmd)
 }
@@ -0,0 +1,470 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/caldav"
+	"github.com/yjwong/lark-cli/internal/calendar"
+	"github.com/yjwong/lark-cli/internal/config"
+	"github.com/yjwong/lark-cli/internal/ics"
+	"github.com/yjwong/lark-cli/internal/output"
+	"github.com/yjwong/lark-cli/internal/parallel"
+)
+
+// caldavWorkers bounds concurrent event fetches when listing a calendar
+const caldavWorkers = 5
+
+// caldavCalendarsTTL is how long the calendar list is reused between requests
+const caldavCalendarsTTL = time.Minute
+
+var caldavCmd = &cobra.Command{
+	Use:   "caldav",
+	Short: "CalDAV bridge commands",
+	Long:  "Serve your Lark calendars to desktop calendar clients over CalDAV",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		validateScopeGroup("calendar")
+	},
+}
+
+var (
+	caldavAddr       string
+	caldavPastDays   int
+	caldavFutureDays int
+)
+
+// caldavNotice is one line of caldav serve output
+type caldavNotice struct {
+	Type      string `json:"type"` // serving, request
+	At        string `json:"at"`
+	URL       string `json:"url,omitempty"`
+	Calendars int    `json:"calendars,omitempty"`
+	Warning   string `json:"warning,omitempty"`
+	Method    string `json:"method,omitempty"`
+	Path      string `json:"path,omitempty"`
+	Status    int    `json:"status,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+var caldavServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve your calendars over CalDAV",
+	Long: `Run a minimal CalDAV server so that desktop calendar clients can read and
+edit your Lark calendars. Requests are made with your logged-in token.
+
+Add http://127.0.0.1:5232/ to your client as a CalDAV account (any user name
+and password). Every calendar you can read is listed; calendars you can only
+read are served read-only. Without a time range, a calendar lists events from
+--past-days ago to --future-days ahead.
+
+Each occurrence of a recurring event is its own resource, as in 'lark cal
+export'. Events created by the client keep the client's resource name and UID.
+Attendees are shown but changes to them are not sent to Lark.
+
+The server has no authentication: keep it on a loopback address. Prints a line
+of JSON (NDJSON) when it starts and for each request. Stop with Ctrl-C.
+
+Examples:
+  lark caldav serve
+  lark caldav serve --addr 127.0.0.1:8008 --future-days 365
+  curl -X PROPFIND -H "Depth: 1" http://127.0.0.1:5232/calendars/`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if caldavPastDays < 0 || caldavFutureDays < 1 {
+			output.Fatalf("VALIDATION_ERROR", "--past-days must be 0 or more and --future-days at least 1")
+		}
+
+		tz := config.GetTimezone()
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			loc = time.Local
+		}
+
+		backend := &larkCalDAVBackend{
+			client: api.NewClient(),
+			loc:    loc,
+			tz:     tz,
+			served: make(map[string]bool),
+			emails: make(map[string]string),
+		}
+		if backend.names, err = loadCalDAVNames(); err != nil {
+			output.Fatal("FILE_ERROR", err)
+		}
+		calendars, err := backend.Calendars()
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+
+		listener, err := net.Listen("tcp", caldavAddr)
+		if err != nil {
+			output.Fatal("SERVER_ERROR", err)
+		}
+
+		server := &http.Server{
+			Handler: &caldav.Server{
+				Backend:    backend,
+				Location:   loc,
+				PastDays:   caldavPastDays,
+				FutureDays: caldavFutureDays,
+				OnRequest: func(r *http.Request, status int, err error) {
+					n := caldavNotice{Type: "request", At: time.Now().In(loc).Format(time.RFC3339), Method: r.Method, Path: r.URL.Path, Status: status}
+					if err != nil {
+						n.Error = err.Error()
+					}
+					output.Line(n)
+				},
+			},
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		notice := caldavNotice{
+			Type:      "serving",
+			At:        time.Now().In(loc).Format(time.RFC3339),
+			URL:       "http://" + listener.Addr().String() + "/",
+			Calendars: len(calendars),
+		}
+		if !isLoopback(listener.Addr()) {
+			notice.Warning = "no authentication: anyone who can reach this address can read and change your calendars"
+		}
+		output.Line(notice)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			server.Shutdown(shutdownCtx)
+		}()
+
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			output.Fatal("SERVER_ERROR", err)
+		}
+	},
+}
+
+func init() {
+	caldavServeCmd.Flags().StringVar(&caldavAddr, "addr", "127.0.0.1:5232", "Address to listen on")
+	caldavServeCmd.Flags().IntVar(&caldavPastDays, "past-days", calendar.DefaultPastDays, "Days of past events to list")
+	caldavServeCmd.Flags().IntVar(&caldavFutureDays, "future-days", calendar.DefaultFutureDays, "Days of upcoming events to list")
+
+	caldavCmd.AddCommand(caldavServeCmd)
+}
+
+// isLoopback reports whether a listener only accepts local connections
+func isLoopback(addr net.Addr) bool {
+	tcp, ok := addr.(*net.TCPAddr)
+	return ok && tcp.IP.IsLoopback()
+}
+
+// caldavName records an event created by a CalDAV client under its own
+// resource name, so it is served back under that name and UID
+type caldavName struct {
+	EventID string `json:"event_id"`
+	UID     string `json:"uid,omitempty"`
+}
+
+// larkCalDAVBackend stores CalDAV resources as Lark events. Resources are
+// named by event ID unless a client created them under another name.
+type larkCalDAVBackend struct {
+	client *api.Client
+	loc    *time.Location
+	tz     string
+
+	mu          sync.Mutex
+	calendars   []caldav.Calendar
+	calendarsAt time.Time
+	served      map[string]bool                  // Event IDs listed or fetched so far
+	emails      map[string]string                // open_id -> email; "" when unresolved
+	names       map[string]map[string]caldavName // calendar_id -> resource name -> event
+}
+
+func (b *larkCalDAVBackend) Calendars() ([]caldav.Calendar, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.calendars != nil && time.Since(b.calendarsAt) < caldavCalendarsTTL {
+		return b.calendars, nil
+	}
+
+	calendars, err := b.client.ListCalendars()
+	if err != nil {
+		return nil, err
+	}
+	out := []caldav.Calendar{}
+	for _, c := range calendars {
+		// Free/busy-only calendars have no readable events
+		if c.IsDeleted || c.Role == "free_busy_reader" {
+			continue
+		}
+		out = append(out, caldav.Calendar{
+			ID:          c.CalendarID,
+			Name:        c.DisplayName(),
+			Description: c.Description,
+			ReadOnly:    c.Role == "reader",
+		})
+	}
+	b.calendars, b.calendarsAt = out, time.Now()
+	return out, nil
+}
+
+func (b *larkCalDAVBackend) ListEvents(calendarID string, from, to time.Time) ([]caldav.Object, error) {
+	events, err := b.client.ListEvents(api.ListEventsOptions{
+		CalendarID: calendarID,
+		StartTime:  from,
+		EndTime:    to,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Serve the same full view as GetEvent, so ETags agree between the two
+	parallel.Run(len(events), caldavWorkers, func(i int) {
+		if full, err := b.fullEvent(calendarID, events[i].EventID); err == nil && full != nil {
+			events[i] = *full
+		}
+	})
+
+	emails := b.resolveEmails(events)
+	objects := make([]caldav.Object, len(events))
+	for i, e := range events {
+		objects[i] = b.object(calendarID, e, emails)
+	}
+	return objects, nil
+}
+
+func (b *larkCalDAVBackend) GetEvent(calendarID, name string) (*caldav.Object, error) {
+	eventID, known := b.eventID(calendarID, name)
+	event, err := b.fullEvent(calendarID, eventID)
+	if err != nil {
+		// A name we haven't served is most likely a new resource
+		if !known {
+			return nil, caldav.ErrNotFound
+		}
+		return nil, err
+	}
+	if event == nil || event.Status == "cancelled" {
+		return nil, caldav.ErrNotFound
+	}
+	o := b.object(calendarID, *event, b.resolveEmails([]api.Event{*event}))
+	return &o, nil
+}
+
+func (b *larkCalDAVBackend) CreateEvent(calendarID, name string, e ics.Event) error {
+	event := icsToEvent(e, b.tz)
+	req := createRequestFromEvent(&event)
+	req.Recurrence = event.Recurrence
+
+	created, err := b.client.CreateEvent(calendarID, req)
+	if err != nil {
+		return err
+	}
+	if name == created.EventID {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.names[calendarID] == nil {
+		b.names[calendarID] = make(map[string]caldavName)
+	}
+	b.names[calendarID][name] = caldavName{EventID: created.EventID, UID: e.UID}
+	return saveCalDAVNames(b.names)
+}
+
+// UpdateEvent sends only the fields that differ from the event as served,
+// so parts the iCalendar form can't express (such as Lark meeting links)
+// survive a round trip through the client
+func (b *larkCalDAVBackend) UpdateEvent(calendarID, name string, e ics.Event) error {
+	eventID, _ := b.eventID(calendarID, name)
+	current, err := b.fullEvent(calendarID, eventID)
+	if err != nil {
+		return err
+	}
+	served := b.object(calendarID, *current, b.resolveEmails([]api.Event{*current})).Event
+	updated := icsToEvent(e, b.tz)
+
+	req := &api.UpdateEventRequest{}
+	changed := false
+	if e.Summary != served.Summary {
+		req.Summary, changed = updated.Summary, true
+	}
+	if e.Description != served.Description {
+		req.Description, changed = e.Description, true
+	}
+	if !sameEventTimes(e, served) {
+		req.StartTime, req.EndTime, changed = updated.StartTime, updated.EndTime, true
+	}
+	if e.Location != served.Location {
+		req.Location, changed = &api.Location{Name: e.Location}, true
+	}
+	if e.URL != served.URL && e.URL != "" {
+		req.Vchat, changed = updated.Vchat, true
+	}
+	if e.Class != served.Class && updated.Visibility != "" {
+		req.Visibility, changed = updated.Visibility, true
+	}
+	if !reflect.DeepEqual(e.Alarms, served.Alarms) && len(updated.Reminders) > 0 {
+		req.Reminders, changed = updated.Reminders, true
+	}
+	if e.RRule != served.RRule && e.RRule != "" {
+		req.Recurrence, changed = e.RRule, true
+	}
+	if !changed {
+		return nil
+	}
+
+	_, err = b.client.UpdateEvent(calendarID, eventID, req)
+	return err
+}
+
+func (b *larkCalDAVBackend) DeleteEvent(calendarID, name string) error {
+	eventID, known := b.eventID(calendarID, name)
+	if err := b.client.DeleteEvent(calendarID, eventID); err != nil {
+		return err
+	}
+	if !known || eventID == name {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.names[calendarID], name)
+	return saveCalDAVNames(b.names)
+}
+
+// sameEventTimes reports whether two events start and end at the same time.
+// All-day events are compared by date, since DATE values carry no zone.
+func sameEventTimes(a, b ics.Event) bool {
+	if a.AllDay != b.AllDay {
+		return false
+	}
+	if a.AllDay {
+		return a.Start.Format("2006-01-02") == b.Start.Format("2006-01-02") &&
+			a.End.Format("2006-01-02") == b.End.Format("2006-01-02")
+	}
+	return a.Start.Equal(b.Start) && a.End.Equal(b.End)
+}
+
+// eventID maps a resource name to its event ID. known is false when the name
+// is neither a client-chosen name nor an event ID served before.
+func (b *larkCalDAVBackend) eventID(calendarID, name string) (eventID string, known bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if n, ok := b.names[calendarID][name]; ok {
+		return n.EventID, true
+	}
+	return name, b.served[name]
+}
+
+// fullEvent fetches an event with its reminders and every attendee
+func (b *larkCalDAVBackend) fullEvent(calendarID, eventID string) (*api.Event, error) {
+	event, err := b.client.GetEvent(calendarID, eventID)
+	if err != nil || event == nil {
+		return event, err
+	}
+	event.EventID = eventID // Keep occurrence IDs as requested
+	if event.HasMoreAttendee {
+		if attendees, err := b.client.ListEventAttendees(calendarID, eventID); err == nil {
+			event.Attendees = attendees
+		}
+	}
+	return event, nil
+}
+
+// object converts an event to its CalDAV form, under the name and UID a
+// client gave it if it created the event
+func (b *larkCalDAVBackend) object(calendarID string, e api.Event, emails map[string]string) caldav.Object {
+	o := caldav.Object{Name: e.EventID, Event: eventToICS(e, b.loc, emails)}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.served[e.EventID] = true
+	for name, n := range b.names[calendarID] {
+		if n.EventID == e.EventID {
+			o.Name = name
+			if n.UID != "" {
+				o.Event.UID = n.UID
+			}
+			break
+		}
+	}
+	return o
+}
+
+// resolveEmails looks up attendee emails, remembering them across requests
+func (b *larkCalDAVBackend) resolveEmails(events []api.Event) map[string]string {
+	var missing []api.Event
+	b.mu.Lock()
+	for _, e := range events {
+		for _, att := range e.Attendees {
+			if _, ok := b.emails[att.UserID]; att.Type == "user" && att.UserID != "" && !ok {
+				missing = append(missing, e)
+				break
+			}
+		}
+	}
+	b.mu.Unlock()
+
+	found := resolveAttendeeEmails(b.client, missing)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, e := range missing {
+		for _, att := range e.Attendees {
+			if att.Type == "user" && att.UserID != "" {
+				b.emails[att.UserID] = found[att.UserID]
+			}
+		}
+	}
+	emails := make(map[string]string, len(b.emails))
+	for id, email := range b.emails {
+		if email != "" {
+			emails[id] = email
+		}
+	}
+	return emails
+}
+
+// caldavNamesFilePath returns the path to the record of client-chosen names
+func caldavNamesFilePath() string {
+	return filepath.Join(config.GetConfigDir(), "caldav_names.json")
+}
+
+// loadCalDAVNames reads the calendar_id -> resource name -> event map
+func loadCalDAVNames() (map[string]map[string]caldavName, error) {
+	names := make(map[string]map[string]caldavName)
+	data, err := os.ReadFile(caldavNamesFilePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return names, nil
+		}
+		return nil, fmt.Errorf("failed to read CalDAV names: %w", err)
+	}
+	if err := json.Unmarshal(data, &names); err != nil {
+		return nil, fmt.Errorf("failed to parse CalDAV names: %w", err)
+	}
+	return names, nil
+}
+
+func saveCalDAVNames(names map[string]map[string]caldavName) error {
+	data, err := json.MarshalIndent(names, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal CalDAV names: %w", err)
+	}
+	if err := os.WriteFile(caldavNamesFilePath(), data, 0600); err != nil {
+		return fmt.Errorf("failed to write CalDAV names: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,349 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/output"
+)
+
+// calCalendar is the --calendar flag shared by all cal subcommands
+var calCalendar string
+
+var calendarsCmd = &cobra.Command{
+	Use:   "calendars",
+	Short: "Manage calendars",
+	Long: `List, inspect, subscribe to and unsubscribe from calendars.
+
+Without a subcommand, lists your calendars. Any calendar can be used with the
+--calendar flag on other cal commands, by ID or by name.
+
+Examples:
+  lark cal calendars
+  lark cal calendars show "Team Calendar"
+  lark cal calendars subscribe feishu.cn_xxxxxxxx@group.calendar.feishu.cn
+  lark cal list --calendar "Team Calendar"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		calendarsListCmd.Run(cmd, args)
+	},
+}
+
+// --- List Calendars ---
+
+var calendarsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List your calendars",
+	Long: `List the calendars you own or subscribe to.
+
+Example:
+  lark cal calendars list`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := api.NewClient()
+
+		calendars, err := client.ListCalendars()
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+
+		outCalendars := []api.OutputCalendar{}
+		for _, c := range calendars {
+			if c.IsDeleted {
+				continue
+			}
+			outCalendars = append(outCalendars, api.ConvertToOutputCalendar(c))
+		}
+
+		output.JSON(map[string]interface{}{
+			"calendars": outCalendars,
+			"count":     len(outCalendars),
+		})
+	},
+}
+
+// --- Show Calendar ---
+
+var calendarsShowCmd = &cobra.Command{
+	Use:   "show <calendar-id|name>",
+	Short: "Show calendar details",
+	Long: `Show details of a calendar by ID or name.
+
+Examples:
+  lark cal calendars show primary
+  lark cal calendars show "Team Calendar"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := api.NewClient()
+
+		cal, err := findCalendar(client, args[0])
+		if err != nil {
+			output.Fatal("CALENDAR_ERROR", err)
+		}
+
+		output.JSON(api.ConvertToOutputCalendar(*cal))
+	},
+}
+
+// --- Subscribe ---
+
+var calendarsSubscribeCmd = &cobra.Command{
+	Use:   "subscribe <calendar-id>",
+	Short: "Subscribe to a calendar",
+	Long: `Subscribe to a shared or public calendar so that it appears in your list.
+
+Example:
+  lark cal calendars subscribe feishu.cn_xxxxxxxx@group.calendar.feishu.cn`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := api.NewClient()
+
+		cal, err := client.SubscribeCalendar(args[0])
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+
+		result := map[string]interface{}{
+			"success": true,
+			"message": fmt.Sprintf("Subscribed to calendar: %s", args[0]),
+		}
+		if cal != nil {
+			result["calendar"] = api.ConvertToOutputCalendar(*cal)
+		}
+		output.JSON(result)
+	},
+}
+
+// --- Unsubscribe ---
+
+var calendarsUnsubscribeCmd = &cobra.Command{
+	Use:   "unsubscribe <calendar-id|name>",
+	Short: "Unsubscribe from a calendar",
+	Long: `Remove a subscribed calendar from your list.
+
+Example:
+  lark cal calendars unsubscribe "Team Calendar"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := api.NewClient()
+
+		cal, err := findCalendar(client, args[0])
+		if err != nil {
+			output.Fatal("CALENDAR_ERROR", err)
+		}
+		if cal.Type == "primary" {
+			output.Fatalf("VALIDATION_ERROR", "Cannot unsubscribe from your primary calendar")
+		}
+
+		if err := client.UnsubscribeCalendar(cal.CalendarID); err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+
+		output.JSON(map[string]interface{}{
+			"success": true,
+			"message": fmt.Sprintf("Unsubscribed from calendar: %s", cal.DisplayName()),
+		})
+	},
+}
+
+// --- Create and Update Calendars ---
+
+var (
+	calendarSummary     string
+	calendarDescription string
+	calendarColor       string
+	calendarPermissions string
+)
+
+var createCalendarCmd = &cobra.Command{
+	Use:   "create-calendar",
+	Short: "Create a shared calendar",
+	Long: `Create a shared calendar owned by you.
+
+--permissions sets what people without an ACL entry can see:
+  private               nothing (default)
+  show_only_free_busy   when the calendar is busy
+  public                event details
+
+Use 'lark cal acl add' to share it with specific people.
+
+Examples:
+  lark cal create-calendar --summary "Team Calendar"
+  lark cal create-calendar --summary "Project X" --description "Milestones and reviews" --color "#4A90E2" --permissions show_only_free_busy`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if calendarSummary == "" {
+			output.Fatalf("VALIDATION_ERROR", "--summary is required")
+		}
+		req, err := calendarRequestFromFlags()
+		if err != nil {
+			output.Fatal("VALIDATION_ERROR", err)
+		}
+
+		client := api.NewClient()
+
+		cal, err := client.CreateCalendar(req)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+		if cal == nil {
+			output.Fatalf("API_ERROR", "No calendar data in response")
+		}
+
+		output.JSON(map[string]interface{}{
+			"success":  true,
+			"message":  fmt.Sprintf("Created calendar: %s", cal.DisplayName()),
+			"calendar": api.ConvertToOutputCalendar(*cal),
+		})
+	},
+}
+
+var updateCalendarCmd = &cobra.Command{
+	Use:   "update-calendar <calendar-id|name>",
+	Short: "Update a calendar's settings",
+	Long: `Change a calendar's title, description, color or permissions.
+Only the flags you pass are changed. You need the owner role.
+
+Examples:
+  lark cal update-calendar "Team Calendar" --description "Team events and OOO"
+  lark cal update-calendar "Project X" --summary "Project X (archived)" --permissions private`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		req, err := calendarRequestFromFlags()
+		if err != nil {
+			output.Fatal("VALIDATION_ERROR", err)
+		}
+		if *req == (api.CalendarRequest{}) {
+			output.Fatalf("VALIDATION_ERROR", "Nothing to update: set at least one of --summary, --description, --color or --permissions")
+		}
+
+		client := api.NewClient()
+
+		cal, err := findCalendar(client, args[0])
+		if err != nil {
+			output.Fatal("CALENDAR_ERROR", err)
+		}
+
+		updated, err := client.UpdateCalendar(cal.CalendarID, req)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+		if updated == nil {
+			updated = cal
+		}
+
+		output.JSON(map[string]interface{}{
+			"success":  true,
+			"message":  fmt.Sprintf("Updated calendar: %s", updated.DisplayName()),
+			"calendar": api.ConvertToOutputCalendar(*updated),
+		})
+	},
+}
+
+// calendarRequestFromFlags builds a create/update request from the calendar flags
+func calendarRequestFromFlags() (*api.CalendarRequest, error) {
+	req := &api.CalendarRequest{
+		Summary:     calendarSummary,
+		Description: calendarDescription,
+	}
+	if calendarColor != "" {
+		color, err := parseHexColor(calendarColor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid color format: %v (use hex like #9CA2A9)", err)
+		}
+		req.Color = &color
+	}
+	switch calendarPermissions {
+	case "", "private", "show_only_free_busy", "public":
+		req.Permissions = calendarPermissions
+	default:
+		return nil, fmt.Errorf("invalid permissions: %s (must be private, show_only_free_busy, or public)", calendarPermissions)
+	}
+	return req, nil
+}
+
+func init() {
+	calendarsCmd.AddCommand(calendarsListCmd)
+	calendarsCmd.AddCommand(calendarsShowCmd)
+	calendarsCmd.AddCommand(calendarsSubscribeCmd)
+	calendarsCmd.AddCommand(calendarsUnsubscribeCmd)
+
+	for _, c := range []*cobra.Command{createCalendarCmd, updateCalendarCmd} {
+		c.Flags().StringVar(&calendarSummary, "summary", "", "Calendar title")
+		c.Flags().StringVar(&calendarDescription, "description", "", "Calendar description")
+		c.Flags().StringVar(&calendarColor, "color", "", "Calendar color (hex format, e.g., #9CA2A9)")
+		c.Flags().StringVar(&calendarPermissions, "permissions", "", "Who else can see it: private, show_only_free_busy, or public")
+	}
+}
+
+// resolveCalendar returns the calendar chosen with --calendar, or the
+// primary calendar when the flag is not set
+func resolveCalendar(client *api.Client) (*api.Calendar, error) {
+	return findCalendar(client, calCalendar)
+}
+
+// findCalendar looks up a calendar by ID or name. An empty reference or
+// "primary" selects the primary calendar. Names must match exactly one of
+// the user's calendars (case-insensitive).
+func findCalendar(client *api.Client, ref string) (*api.Calendar, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.EqualFold(ref, "primary") {
+		return client.GetPrimaryCalendar()
+	}
+
+	calendars, err := client.ListCalendars()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []api.Calendar
+	for _, c := range calendars {
+		if c.IsDeleted {
+			continue
+		}
+		if c.CalendarID == ref {
+			return &c, nil
+		}
+		if strings.EqualFold(c.Summary, ref) || strings.EqualFold(c.SummaryAlias, ref) {
+			matches = append(matches, c)
+		}
+	}
+
+	switch len(matches) {
+	case 1:
+		return &matches[0], nil
+	case 0:
+		// Not in the user's list; it may still be readable by ID
+		if cal, err := client.GetCalendar(ref); err == nil && cal != nil {
+			return cal, nil
+		}
+		return nil, fmt.Errorf("calendar not found: %s (run 'lark cal calendars' to list calendars)", ref)
+	default:
+		var ids []string
+		for _, c := range matches {
+			ids = append(ids, c.CalendarID)
+		}
+		return nil, fmt.Errorf("calendar name %q is ambiguous, use an ID: %s", ref, strings.Join(ids, ", "))
+	}
+}
+
+// listReadableCalendars returns the calendars whose events can be listed,
+// with the primary calendar first
+func listReadableCalendars(client *api.Client) ([]api.Calendar, error) {
+	calendars, err := client.ListCalendars()
+	if err != nil {
+		return nil, err
+	}
+
+	var readable []api.Calendar
+	for _, c := range calendars {
+		if c.IsDeleted || c.Role == "free_busy_reader" {
+			continue
+		}
+		if c.Type == "primary" {
+			readable = append([]api.Calendar{c}, readable...)
+		} else {
+			readable = append(readable, c)
+		}
+	}
+	return readable, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/yjwong/lark-cli/internal/api"
 	"github.com/yjwong/lark-cli/internal/config"
+	"github.com/yjwong/lark-cli/internal/directory"
 	"github.com/yjwong/lark-cli/internal/output"
 	timex "github.com/yjwong/lark-cli/internal/time"
 )
@@ -32,6 +33,7 @@ Returns time slots when all specified users are available.
 Examples:
   lark cal common-freetime --from 2026-01-05 --to 2026-01-09 --users ou_abc123
   lark cal common-freetime --from 2026-01-05 --to 2026-01-09 --users "ou_abc123,ou_def456"
+  lark cal common-freetime --from 2026-01-05 --to 2026-01-09 --users "alice@example.com,@bob"
   lark cal common-freetime --from 2026-01-05T09:00:00+08:00 --to 2026-01-05T18:00:00+08:00 --users ou_abc123 --work-hours --min-length 30`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if commonFreetimeFrom == "" || commonFreetimeTo == "" {
@@ -41,17 +43,6 @@ Examples:
 			output.Fatalf("VALIDATION_ERROR", "--users is required")
 		}
 
-		// Parse user IDs
-		userIDs := strings.Split(commonFreetimeUsers, ",")
-		for i := range userIDs {
-			userIDs[i] = strings.TrimSpace(userIDs[i])
-		}
-
-		// Validate user count
-		if len(userIDs) == 0 || len(userIDs) > 10 {
-			output.Fatalf("VALIDATION_ERROR", "Must specify 1-10 users")
-		}
-
 		// Parse timezone
 		tz := config.GetTimezone()
 		loc, err := time.LoadLocation(tz)
@@ -99,6 +90,24 @@ Examples:
 
 		client := api.NewClient()
 
+		// Resolve users; chats and departments expand to their members
+		people, err := resolvePeople(client, strings.Split(commonFreetimeUsers, ","), false)
+		if err != nil {
+			output.Fatal("USER_ERROR", err)
+		}
+		var userIDs []string
+		for _, p := range people {
+			if p.Kind == directory.KindEmail {
+				output.Fatalf("USER_ERROR", "No Lark user found for %s", p.Email)
+			}
+			userIDs = append(userIDs, p.ID)
+		}
+
+		// Validate user count
+		if len(userIDs) == 0 || len(userIDs) > 10 {
+			output.Fatalf("VALIDATION_ERROR", "Must specify 1-10 users")
+		}
+
 		slots, err := client.GetCommonFreeTime(api.CommonFreeTimeOptions{
 			UserIDs:                 userIDs,
 			StartTime:               startTime,
@@ -114,12 +123,12 @@ Examples:
 			output.Fatal("API_ERROR", err)
 		}
 
-		// Convert to output format
+		// Convert to output format, with the slots' local times as RFC 3339
 		freeSlots := make([]api.OutputFreeTimeSlot, len(slots))
 		for i, s := range slots {
 			freeSlots[i] = api.OutputFreeTimeSlot{
-				Start:         s.StartTime,
-				End:           s.EndTime,
+				Start:         inLocation(s.StartTime, loc),
+				End:           inLocation(s.EndTime, loc),
 				LengthMinutes: s.Length / 60,
 			}
 		}
@@ -141,7 +150,7 @@ Examples:
 func init() {
 	commonFreetimeCmd.Flags().StringVar(&commonFreetimeFrom, "from", "", "Start time (required, ISO 8601 or date)")
 	commonFreetimeCmd.Flags().StringVar(&commonFreetimeTo, "to", "", "End time (required, ISO 8601 or date)")
-	commonFreetimeCmd.Flags().StringVar(&commonFreetimeUsers, "users", "", "Comma-separated user open_ids (required, max 10)")
+	commonFreetimeCmd.Flags().StringVar(&commonFreetimeUsers, "users", "", "Comma-separated users: open_ids, emails, names, aliases, chat:<name> or dept:<name> (required, max 10 people)")
 	commonFreetimeCmd.Flags().BoolVar(&commonFreetimeOnlyBusy, "only-busy", true, "Only consider busy events")
 	commonFreetimeCmd.Flags().BoolVar(&commonFreetimeIncludeExternal, "include-external", false, "Include external calendars")
 	commonFreetimeCmd.Flags().BoolVar(&commonFreetimeWorkHours, "work-hours", false, "Respect work hour settings")
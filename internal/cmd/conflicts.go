@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/config"
+	"github.com/yjwong/lark-cli/internal/conflicts"
+	"github.com/yjwong/lark-cli/internal/output"
+	"github.com/yjwong/lark-cli/internal/parallel"
+	timex "github.com/yjwong/lark-cli/internal/time"
+)
+
+// conflictLookupWorkers bounds concurrent attendee and RSVP lookups in cal
+// conflicts
+const conflictLookupWorkers = 5
+
+var (
+	conflictsWithin        string
+	conflictsFrom          string
+	conflictsTo            string
+	conflictsBufferMinutes int
+	conflictsSearchDays    int
+	conflictsNoSuggest     bool
+)
+
+var conflictsCmd = &cobra.Command{
+	Use:   "conflicts",
+	Short: "Find and resolve calendar conflicts",
+	Long: `Report conflicting events across all your calendars, with suggestions.
+
+Events are weighted by your RSVP: declined events and events shown as free
+never conflict, and conflicts involving tentative or unanswered invitations
+are graded lower than those between accepted events (severity high, medium
+or low).
+
+For each conflict the event with the weaker RSVP (or the later one) is
+suggested for declining, if you haven't accepted it, or for moving to the
+nearest time when all of its attendees are free.
+
+Use --calendar to check a single calendar.
+
+Examples:
+  lark cal conflicts
+  lark cal conflicts --within "next week" --buffer-minutes 10
+  lark cal conflicts --from 2026-01-05 --to 2026-01-30 --no-suggest`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if conflictsSearchDays < 1 || conflictsSearchDays > 6 {
+			output.Fatalf("VALIDATION_ERROR", "--search-days must be between 1 and 6")
+		}
+
+		// Parse timezone
+		tz := config.GetTimezone()
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			loc = time.Local
+			tz = loc.String()
+		}
+		now := time.Now().In(loc)
+		client := api.NewClient()
+
+		startTime, endTime, err := parseWithin(client, conflictsWithin, now)
+		if err != nil {
+			output.Fatalf("PARSE_ERROR", "Failed to parse --within: %v", err)
+		}
+		if conflictsFrom != "" {
+			startTime, err = timex.Parse(conflictsFrom, loc)
+			if err != nil {
+				output.Fatalf("PARSE_ERROR", "Failed to parse --from: %v", err)
+			}
+			if !containsTimeSpec(conflictsFrom) {
+				startTime = timex.StartOfDay(startTime)
+			}
+		}
+		if conflictsTo != "" {
+			endTime, err = timex.Parse(conflictsTo, loc)
+			if err != nil {
+				output.Fatalf("PARSE_ERROR", "Failed to parse --to: %v", err)
+			}
+			if !containsTimeSpec(conflictsTo) {
+				endTime = timex.EndOfDay(endTime)
+			}
+		}
+		if !endTime.After(startTime) {
+			output.Fatalf("VALIDATION_ERROR", "end of range must be after start")
+		}
+
+		// All readable calendars, unless --calendar narrows it to one
+		var calendars []api.Calendar
+		if calCalendar != "" {
+			var cal *api.Calendar
+			cal, err = resolveCalendar(client)
+			if cal != nil {
+				calendars = []api.Calendar{*cal}
+			}
+		} else {
+			calendars, err = listReadableCalendars(client)
+		}
+		if err != nil {
+			output.Fatal("CALENDAR_ERROR", err)
+		}
+
+		events, eventCalendars, err := fetchCalendarEvents(client, calendars, startTime, endTime, loc)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+
+		currentUser, err := client.GetCurrentUser()
+		if err != nil {
+			output.Fatal("USER_ERROR", err)
+		}
+
+		// Attendees give both the RSVP weighting and who must be free for a move
+		rsvps := make([]string, len(events))
+		parallel.Run(len(events), conflictLookupWorkers, func(i int) {
+			attendees, err := client.ListEventAttendees(eventCalendars[i].CalendarID, events[i].EventID)
+			if err == nil {
+				events[i].Attendees = attendees
+			}
+			rsvps[i] = api.ExtractUserRsvpStatus(events[i], currentUser.OpenID, eventCalendars[i].CalendarID, client)
+		})
+
+		outputEvents := api.ConvertToOutputEvents(events)
+		eventsByID := make(map[string]api.Event, len(events))
+		for i := range outputEvents {
+			outputEvents[i].RsvpStatus = rsvps[i]
+			outputEvents[i].CalendarID = eventCalendars[i].CalendarID
+			outputEvents[i].CalendarName = eventCalendars[i].DisplayName()
+			outputEvents[i].Attendees = nil
+			outputEvents[i].Organizer = ""
+			eventsByID[events[i].EventID] = events[i]
+		}
+
+		parsed, err := conflicts.ParseEventTimes(outputEvents, loc)
+		if err != nil {
+			output.Fatal("CONFLICT_DETECTION_ERROR", err)
+		}
+		// Events on shared calendars only take your time if you attend them
+		var slots []conflicts.EventTimeSlot
+		for i, slot := range parsed {
+			if outputEvents[i].RsvpStatus == "" && eventCalendars[i].Type != "primary" {
+				continue
+			}
+			slots = append(slots, slot)
+		}
+		result := conflicts.Detect(slots, conflicts.Options{
+			BufferMinutes: conflictsBufferMinutes,
+		})
+
+		if !conflictsNoSuggest {
+			window := time.Duration(conflictsSearchDays) * 24 * time.Hour
+			wh, err := loadWorkingHours(client, "", startTime, endTime.Add(window))
+			if err != nil {
+				output.Fatal("CONFIG_ERROR", err)
+			}
+			conflicts.Suggest(&result, slots, conflicts.SuggestOptions{
+				FindFree: func(eventID string, from, to time.Time) ([]conflicts.Interval, error) {
+					return findEventFreeTime(client, eventsByID[eventID], currentUser.OpenID, from, to, tz, loc, wh)
+				},
+				Window: window,
+				Now:    now,
+			})
+		}
+		conflicts.ApplyToEvents(outputEvents, result)
+
+		involved := []api.OutputEvent{}
+		for _, e := range outputEvents {
+			if len(e.ConflictsWith) > 0 {
+				involved = append(involved, e)
+			}
+		}
+
+		calendarIDs := make([]string, len(calendars))
+		for i, c := range calendars {
+			calendarIDs[i] = c.CalendarID
+		}
+
+		output.JSON(api.OutputConflictReport{
+			From:      startTime.Format(time.RFC3339),
+			To:        endTime.Format(time.RFC3339),
+			Calendars: calendarIDs,
+			Events:    involved,
+			Conflicts: result.Conflicts,
+			Count:     len(result.Conflicts),
+		})
+	},
+}
+
+func init() {
+	conflictsCmd.Flags().StringVar(&conflictsWithin, "within", "next 7 days", "Range to check (e.g. \"this week\", \"next 14 days\")")
+	conflictsCmd.Flags().StringVar(&conflictsFrom, "from", "", "Start of range (overrides --within)")
+	conflictsCmd.Flags().StringVar(&conflictsTo, "to", "", "End of range (overrides --within)")
+	conflictsCmd.Flags().IntVar(&conflictsBufferMinutes, "buffer-minutes", 0, "Minimum buffer between meetings")
+	conflictsCmd.Flags().IntVar(&conflictsSearchDays, "search-days", 3, "Days either side of an event to search for a new time (1-6)")
+	conflictsCmd.Flags().BoolVar(&conflictsNoSuggest, "no-suggest", false, "Skip resolution suggestions")
+}
+
+// findEventFreeTime returns the common free time of an event's attendees
+// (and the current user) within their working hours and yours, skipping
+// your holidays
+func findEventFreeTime(client *api.Client, event api.Event, selfID string, from, to time.Time, tz string, loc *time.Location, wh timex.WorkingHours) ([]conflicts.Interval, error) {
+	userIDs := []string{selfID}
+	for _, att := range event.Attendees {
+		if att.Type != "user" || att.UserID == "" || att.RsvpStatus == "decline" || containsString(userIDs, att.UserID) {
+			continue
+		}
+		userIDs = append(userIDs, att.UserID)
+		if len(userIDs) == 10 {
+			break // API limit
+		}
+	}
+
+	minLength := 0
+	if event.StartTime != nil && event.EndTime != nil {
+		minLength = int(eventTime(event.EndTime, loc).Sub(eventTime(event.StartTime, loc)).Seconds())
+	}
+
+	slots, err := client.GetCommonFreeTime(api.CommonFreeTimeOptions{
+		UserIDs:        userIDs,
+		StartTime:      from,
+		EndTime:        to,
+		Timezone:       tz,
+		EnableWorkHour: true,
+		MinTimeLength:  minLength,
+		Limit:          50,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var free []conflicts.Interval
+	for _, s := range slots {
+		start, err1 := api.ParseAPITime(s.StartTime, loc)
+		end, err2 := api.ParseAPITime(s.EndTime, loc)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		for _, part := range wh.Clip(start, end) {
+			if minLength > 0 && part[1].Sub(part[0]) < time.Duration(minLength)*time.Second {
+				continue
+			}
+			free = append(free, conflicts.Interval{Start: part[0].In(loc), End: part[1].In(loc)})
+		}
+	}
+	return free, nil
+}
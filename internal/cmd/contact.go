@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
 	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/directory"
 	"github.com/yjwong/lark-cli/internal/output"
 )
 
@@ -20,20 +24,32 @@ var contactCmd = &cobra.Command{
 var contactGetIDType string
 
 var contactGetCmd = &cobra.Command{
-	Use:   "get <user_id>",
+	Use:   "get <user>",
 	Short: "Get user information by ID",
 	Long: `Look up a single user's information by their user ID.
 
+With the default --id-type open_id, the user can also be given by email,
+name (@alice) or an alias from config.
+
 Examples:
   lark contact get ou_xxxx
   lark contact get ou_xxxx --id-type open_id
-  lark contact get 12345 --id-type user_id`,
+  lark contact get 12345 --id-type user_id
+  lark contact get alice@example.com`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		userID := args[0]
 
 		client := api.NewClient()
 
+		if contactGetIDType == "open_id" && !strings.HasPrefix(userID, "ou_") {
+			user, err := resolveUserRef(client, userID)
+			if err != nil {
+				output.Fatal("NOT_FOUND", err)
+			}
+			userID = user.ID
+		}
+
 		user, err := client.GetUser(userID, contactGetIDType)
 		if err != nil {
 			output.Fatal("API_ERROR", err)
@@ -139,52 +155,82 @@ Examples:
 
 // --- contact search ---
 
+var (
+	contactSearchOffline bool
+	contactSearchOnline  bool
+	contactSearchLimit   int
+)
+
 var contactSearchCmd = &cobra.Command{
 	Use:   "search <query>",
 	Short: "Search for users by name",
-	Long: `Search for users by name keyword.
+	Long: `Search for users by name, English name, nickname or email.
+
+The local directory cache (see 'lark contact sync') is searched first. It
+matches Chinese names by pinyin and initials too ("zhang san", "zhangsan",
+"zs") and tolerates small typos. When nothing matches locally, Lark's user
+search is asked and its results are cached.
 
 Examples:
   lark contact search "Zheng Peng"
-  lark contact search "Bryan"`,
+  lark contact search "Bryan"
+  lark contact search zhangsan --offline
+  lark contact search alice@example.com --online`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		query := args[0]
 
-		client := api.NewClient()
+		if contactSearchOffline && contactSearchOnline {
+			output.Fatalf("VALIDATION_ERROR", "--offline and --online can't be used together")
+		}
 
-		// Fetch all matching users (paginated)
-		var allUsers []api.SearchUserResult
-		var pageToken string
-		hasMore := true
+		cache, err := directory.OpenCache()
+		if err != nil {
+			output.Fatal("CACHE_ERROR", err)
+		}
+		defer cache.Close()
 
-		for hasMore {
-			users, more, nextToken, err := client.SearchUsers(query, 50, pageToken)
-			if err != nil {
-				output.Fatal("API_ERROR", err)
-			}
-			allUsers = append(allUsers, users...)
-			hasMore = more
-			pageToken = nextToken
+		var client *api.Client
+		if !contactSearchOffline {
+			client = api.NewClient()
+		}
+		resolver := newDirectoryResolver(cache, client)
+
+		matches, err := resolver.Search(query, contactSearchLimit, contactSearchOnline)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
 		}
 
 		// Convert to output format
-		contacts := make([]api.OutputContact, len(allUsers))
-		for i, u := range allUsers {
+		contacts := make([]api.OutputContact, len(matches))
+		for i, m := range matches {
 			// Get department name for first department
 			var deptName string
-			if len(u.DepartmentIDs) > 0 {
-				dept, err := client.GetDepartment(u.DepartmentIDs[0])
-				if err == nil && dept != nil {
-					deptName = dept.Name
+			if len(m.DepartmentIDs) > 0 {
+				deptName = resolver.DepartmentName(m.DepartmentIDs[0])
+				if deptName == "" && client != nil {
+					dept, err := client.GetDepartment(m.DepartmentIDs[0])
+					if err == nil && dept != nil {
+						deptName = dept.Name
+						cache.PutDepartments([]api.Department{*dept}, time.Now())
+					}
 				}
 			}
 
+			email := m.Email
+			if email == "" {
+				email = m.EnterpriseEmail
+			}
 			contacts[i] = api.OutputContact{
-				UserID:     u.OpenID,
-				OpenID:     u.OpenID,
-				Name:       u.Name,
+				UserID:     m.OpenID,
+				OpenID:     m.OpenID,
+				Name:       m.Name,
+				EnName:     m.EnName,
+				Email:      email,
+				JobTitle:   m.JobTitle,
 				Department: deptName,
+				Score:      m.Score,
+				MatchedOn:  m.MatchedOn,
 			}
 		}
 
@@ -254,9 +300,21 @@ func init() {
 	// contact list-dept flags
 	contactListDeptCmd.Flags().IntVar(&contactListDeptPageSize, "page-size", 50, "Number of results per page (max 50)")
 
+	// contact search flags
+	contactSearchCmd.Flags().BoolVar(&contactSearchOffline, "offline", false, "Only search the local directory cache")
+	contactSearchCmd.Flags().BoolVar(&contactSearchOnline, "online", false, "Also ask Lark's user search when the cache has matches")
+	contactSearchCmd.Flags().IntVar(&contactSearchLimit, "limit", 20, "Maximum number of results")
+
 	// Register subcommands
 	contactCmd.AddCommand(contactGetCmd)
 	contactCmd.AddCommand(contactListDeptCmd)
 	contactCmd.AddCommand(contactSearchCmd)
 	contactCmd.AddCommand(contactSearchDeptCmd)
+	contactCmd.AddCommand(contactSyncCmd)
+	contactCmd.AddCommand(contactResolveCmd)
+	contactCmd.AddCommand(contactTreeCmd)
+	contactCmd.AddCommand(contactManagerCmd)
+	contactCmd.AddCommand(contactReportsCmd)
+	contactCmd.AddCommand(contactConvertCmd)
+	contactCmd.AddCommand(contactExportCmd)
 }
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/directory"
+	"github.com/yjwong/lark-cli/internal/output"
+)
+
+var (
+	contactConvertFrom   string
+	contactConvertTo     string
+	contactConvertColumn string
+	contactConvertFormat string
+)
+
+var contactConvertCmd = &cobra.Command{
+	Use:   "convert [id]...",
+	Short: "Convert user IDs between open_id, union_id, user_id and email",
+	Long: `Map user IDs of one type to another, for IDs handed over by other apps.
+
+ID types are open_id, union_id, user_id, email and mobile. IDs are given as
+arguments, or read from stdin one per line. With --column, stdin is a CSV
+file with a header row and the IDs are taken from that column (by name or
+1-based position).
+
+IDs are looked up in batches of 50, several at a time. The output maps each
+ID to its converted value; IDs that match no user are listed under
+"unresolved" (on stderr with --format csv).
+
+Examples:
+  lark contact convert --from user_id --to open_id 1a2b3c 4d5e6f
+  lark contact convert --from union_id --to email < union_ids.txt
+  lark contact convert --from email --to user_id --column Email < roster.csv
+  lark contact convert --from user_id --to open_id --format csv < ids.txt > mapping.csv`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if contactConvertFormat != "json" && contactConvertFormat != "csv" {
+			output.Fatalf("VALIDATION_ERROR", "Invalid --format: %s (use json or csv)", contactConvertFormat)
+		}
+		if !containsString(directory.IDTypes, contactConvertFrom) || !containsString(directory.IDTypes, contactConvertTo) {
+			output.Fatalf("VALIDATION_ERROR", "--from and --to must be one of %s", strings.Join(directory.IDTypes, ", "))
+		}
+		if contactConvertFrom == contactConvertTo {
+			output.Fatalf("VALIDATION_ERROR", "--from and --to are both %s", contactConvertFrom)
+		}
+
+		var ids []string
+		var err error
+		if len(args) > 0 && !(len(args) == 1 && args[0] == "-") {
+			ids = uniqueIDs(args)
+		} else {
+			ids, err = readConvertInput(os.Stdin, contactConvertColumn)
+			if err != nil {
+				output.Fatal("INPUT_ERROR", err)
+			}
+		}
+		if len(ids) == 0 {
+			output.Fatalf("VALIDATION_ERROR", "No IDs given")
+		}
+
+		client := api.NewClient()
+
+		cache, err := directory.OpenCache()
+		if err != nil {
+			output.Fatal("CACHE_ERROR", err)
+		}
+		defer cache.Close()
+
+		converted, err := directory.Convert(client, cache, ids, contactConvertFrom, contactConvertTo)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+
+		var mappings []map[string]string
+		unresolved := []string{}
+		for _, id := range ids {
+			value, ok := converted[id]
+			if !ok {
+				unresolved = append(unresolved, id)
+				continue
+			}
+			mappings = append(mappings, map[string]string{
+				contactConvertFrom: id,
+				contactConvertTo:   value,
+			})
+		}
+
+		if contactConvertFormat == "csv" {
+			w := csv.NewWriter(os.Stdout)
+			w.Write([]string{contactConvertFrom, contactConvertTo})
+			for _, m := range mappings {
+				w.Write([]string{m[contactConvertFrom], m[contactConvertTo]})
+			}
+			w.Flush()
+			for _, id := range unresolved {
+				fmt.Fprintf(os.Stderr, "unresolved: %s\n", id)
+			}
+			return
+		}
+
+		if mappings == nil {
+			mappings = []map[string]string{}
+		}
+		output.JSON(map[string]interface{}{
+			"from":       contactConvertFrom,
+			"to":         contactConvertTo,
+			"mappings":   mappings,
+			"count":      len(mappings),
+			"unresolved": unresolved,
+		})
+	},
+}
+
+// readConvertInput reads IDs one per line, or from a CSV column when column
+// names a header or gives a 1-based position
+func readConvertInput(r io.Reader, column string) ([]string, error) {
+	if column == "" {
+		var ids []string
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			ids = append(ids, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("reading stdin: %w", err)
+		}
+		return uniqueIDs(ids), nil
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	index := -1
+	if n, err := strconv.Atoi(column); err == nil {
+		index = n - 1
+	} else {
+		for i, name := range records[0] {
+			if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")), column) {
+				index = i
+				break
+			}
+		}
+	}
+	if index < 0 || index >= len(records[0]) {
+		return nil, fmt.Errorf("no column %q in the CSV header", column)
+	}
+
+	var ids []string
+	for _, record := range records[1:] {
+		if index < len(record) {
+			ids = append(ids, record[index])
+		}
+	}
+	return uniqueIDs(ids), nil
+}
+
+// uniqueIDs trims IDs and drops blanks and duplicates, keeping the order
+func uniqueIDs(ids []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id != "" && !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func init() {
+	contactConvertCmd.Flags().StringVar(&contactConvertFrom, "from", "", "Type of the given IDs (open_id, union_id, user_id, email, mobile)")
+	contactConvertCmd.Flags().StringVar(&contactConvertTo, "to", "open_id", "Type to convert to (open_id, union_id, user_id, email, mobile)")
+	contactConvertCmd.Flags().StringVar(&contactConvertColumn, "column", "", "Read IDs from this column of CSV on stdin (header name or 1-based position)")
+	contactConvertCmd.Flags().StringVar(&contactConvertFormat, "format", "json", "Output format: json or csv")
+	contactConvertCmd.MarkFlagRequired("from")
+}
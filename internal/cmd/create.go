@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -9,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/yjwong/lark-cli/internal/api"
 	"github.com/yjwong/lark-cli/internal/config"
+	"github.com/yjwong/lark-cli/internal/directory"
 	"github.com/yjwong/lark-cli/internal/output"
 	timex "github.com/yjwong/lark-cli/internal/time"
 )
@@ -27,6 +31,13 @@ var (
 	createVisibility      string
 	createAttendeeAbility string
 	createExcludeSelf     bool
+	createRepeat          string
+	createRRule           string
+	createUntil           string
+	createCount           int
+	createRooms           []string
+	createVC              string
+	createAttach          []string
 )
 
 var createCmd = &cobra.Command{
@@ -37,7 +48,18 @@ var createCmd = &cobra.Command{
 Examples:
   lark cal create --summary "Team standup" --start 2026-01-03T09:00:00+08:00 --duration 30m
   lark cal create --summary "1:1 with John" --start 2026-01-03T14:00:00+08:00 --duration 30m --attendee john@example.com
-  lark cal create --summary "Focus Time" --start 2026-01-03T14:00:00+08:00 --duration 2h --color "#9CA2A9"`,
+  lark cal create --summary "Retro" --start 2026-01-09T15:00:00+08:00 --duration 1h --attendee @alice --attendee "chat:Team Standup"
+  lark cal create --summary "Design review" --start 2026-01-06T14:00:00+08:00 --duration 1h --room omm_xxxxxxxxxx
+  lark cal create --summary "Focus Time" --start 2026-01-03T14:00:00+08:00 --duration 2h --color "#9CA2A9"
+  lark cal create --summary "Weekly sync" --start 2026-01-05T10:00:00+08:00 --duration 30m --repeat weekly --until 2026-03-31
+  lark cal create --summary "Standup" --start 2026-01-05T09:30:00+08:00 --duration 15m --rrule "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR" --count 20
+  lark cal create --summary "Sync" --start tomorrow 10:00 --duration 30m --vc lark
+  lark cal create --summary "Client call" --start 2026-01-06T16:00:00+08:00 --duration 1h --vc url:https://zoom.us/j/123456789
+  lark cal create --summary "Planning" --start 2026-01-07T10:00:00+08:00 --duration 1h --attach agenda.pdf
+
+--vc sets the video meeting: "lark" generates a Lark meeting link, "none" turns
+video meetings off, and "url:<link>" uses your own link. The join URL is
+returned as join_url. --attach uploads a file (up to 20 MB) and attaches it.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if createSummary == "" {
 			output.Fatalf("VALIDATION_ERROR", "--summary is required")
@@ -51,8 +73,8 @@ Examples:
 
 		client := api.NewClient()
 
-		// Get primary calendar
-		cal, err := client.GetPrimaryCalendar()
+		// Get the selected calendar (primary unless --calendar is set)
+		cal, err := resolveCalendar(client)
 		if err != nil {
 			output.Fatal("CALENDAR_ERROR", err)
 		}
@@ -99,6 +121,12 @@ Examples:
 			},
 		}
 
+		recurrence, err := buildRecurrence(createRepeat, createRRule, createUntil, createCount, loc)
+		if err != nil {
+			output.Fatal("VALIDATION_ERROR", err)
+		}
+		req.Recurrence = recurrence
+
 		if createLocation != "" {
 			req.Location = &api.Location{Name: createLocation}
 		}
@@ -147,6 +175,29 @@ Examples:
 			output.Fatalf("VALIDATION_ERROR", "Invalid attendee-ability: %s (must be none, can_see_others, can_invite_others, or can_modify_event)", attendeeAbility)
 		}
 
+		if createVC != "" {
+			req.Vchat, err = parseVchat(createVC)
+			if err != nil {
+				output.Fatal("VALIDATION_ERROR", err)
+			}
+		}
+
+		// Resolve meeting rooms before creating the event so a bad name fails early
+		rooms, err := resolveRooms(client, createRooms)
+		if err != nil {
+			output.Fatal("VALIDATION_ERROR", err)
+		}
+
+		if len(createAttach) > 0 {
+			req.Attachments, err = uploadAttachments(client, cal.CalendarID, createAttach)
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					output.Fatal("FILE_ERROR", err)
+				}
+				output.Fatal("API_ERROR", err)
+			}
+		}
+
 		// Create event
 		event, err := client.CreateEvent(cal.CalendarID, req)
 		if err != nil {
@@ -176,6 +227,7 @@ Examples:
 			}
 			attendees = append(attendees, parsedAttendees...)
 		}
+		attendees = append(attendees, rooms...)
 
 		// Add all attendees to the event
 		if len(attendees) > 0 {
@@ -187,13 +239,19 @@ Examples:
 			event.Attendees = addedAttendees
 		}
 
+		ensureMeetingURL(client, cal.CalendarID, event)
+
 		// Output created event
 		outputEvent := api.ConvertToOutputEvent(*event)
-		output.JSON(map[string]interface{}{
+		result := map[string]interface{}{
 			"success": true,
 			"message": fmt.Sprintf("Event created: %s", event.EventID),
 			"event":   outputEvent,
-		})
+		}
+		if outputEvent.MeetingURL != "" {
+			result["join_url"] = outputEvent.MeetingURL
+		}
+		output.JSON(result)
 	},
 }
 
@@ -207,75 +265,98 @@ func init() {
 	createCmd.Flags().StringVar(&createColor, "color", "", "Event color (hex format, e.g., #9CA2A9)")
 	createCmd.Flags().IntVar(&createReminder, "reminder", 0, "Reminder minutes before event")
 	createCmd.Flags().BoolVar(&createNoNotify, "no-notify", false, "Don't send notifications")
-	createCmd.Flags().StringSliceVar(&createAttendees, "attendee", []string{}, "Add attendee by email (repeatable)")
+	createCmd.Flags().StringSliceVar(&createAttendees, "attendee", []string{}, "Add attendee: email, name, open_id, chat:<name>, dept:<name> or alias (repeatable)")
+	createCmd.Flags().StringSliceVar(&createRooms, "room", []string{}, "Book a meeting room by room ID or name (repeatable)")
 	createCmd.Flags().StringVar(&createVisibility, "visibility", "", "Event visibility (default, public, private)")
 	createCmd.Flags().StringVar(&createAttendeeAbility, "attendee-ability", "", "Guest permissions (none, can_see_others, can_invite_others, can_modify_event)")
 	createCmd.Flags().BoolVar(&createExcludeSelf, "exclude-self", false, "Don't add yourself as an attendee")
+	createCmd.Flags().StringVar(&createRepeat, "repeat", "", "Repeat the event (daily, weekly, monthly)")
+	createCmd.Flags().StringVar(&createRRule, "rrule", "", "Custom recurrence rule (RFC 5545 RRULE, e.g. FREQ=WEEKLY;BYDAY=MO,WE)")
+	createCmd.Flags().StringVar(&createUntil, "until", "", "Last date of the recurrence (requires --repeat or --rrule)")
+	createCmd.Flags().IntVar(&createCount, "count", 0, "Number of occurrences (requires --repeat or --rrule)")
+	createCmd.Flags().StringVar(&createVC, "vc", "", "Video meeting: lark, none, or url:<link>")
+	createCmd.Flags().StringSliceVar(&createAttach, "attach", []string{}, "Upload and attach a file (repeatable)")
 
 	createCmd.MarkFlagRequired("summary")
 	createCmd.MarkFlagRequired("start")
 }
 
-// parseAttendees converts attendee strings to Attendee structs.
-// It auto-resolves emails to internal Lark users when possible,
-// falling back to third-party (external) attendees.
+// parseAttendees converts attendee references to Attendee structs. Emails
+// of Lark users, names, open_ids and aliases become user attendees, chats
+// become chat attendees and departments expand to their members; other
+// emails are added as third-party (external) attendees.
 func parseAttendees(client *api.Client, attendeeStrs []string) ([]api.Attendee, error) {
-	// Collect all emails for batch lookup
-	var emails []string
+	var refs []string
 	for _, s := range attendeeStrs {
-		if strings.HasPrefix(s, "email:") {
-			emails = append(emails, strings.TrimPrefix(s, "email:"))
-		} else if strings.Contains(s, "@") {
-			emails = append(emails, s)
-		} else {
-			return nil, fmt.Errorf("unknown attendee format: %s (use email address like user@example.com)", s)
+		if s = strings.TrimSpace(strings.TrimPrefix(s, "email:")); s != "" {
+			refs = append(refs, s)
 		}
 	}
 
-	// Batch resolve emails to internal Lark users
-	resolved := resolveEmails(client, emails)
+	people, err := resolvePeople(client, refs, true)
+	if err != nil {
+		return nil, err
+	}
+	return peopleAttendees(people), nil
+}
 
-	// Build attendee list using resolved users where possible
+// peopleAttendees turns resolved users, chats and emails into attendees
+func peopleAttendees(people []directory.Identity) []api.Attendee {
 	var attendees []api.Attendee
-	for _, email := range emails {
-		if userID, ok := resolved[email]; ok {
-			attendees = append(attendees, api.Attendee{
-				Type:   "user",
-				UserID: userID,
-			})
-		} else {
-			attendees = append(attendees, api.Attendee{
-				Type:            "third_party",
-				ThirdPartyEmail: email,
-			})
+	for _, p := range people {
+		switch p.Kind {
+		case directory.KindUser:
+			attendees = append(attendees, api.Attendee{Type: "user", UserID: p.ID})
+		case directory.KindChat:
+			attendees = append(attendees, api.Attendee{Type: "chat", ChatID: p.ID})
+		case directory.KindEmail:
+			attendees = append(attendees, api.Attendee{Type: "third_party", ThirdPartyEmail: p.Email})
 		}
 	}
-	return attendees, nil
+	return attendees
 }
 
-// resolveEmails looks up emails via the contacts API and returns a map
-// of email -> open_id for internal Lark users. Emails that don't resolve
-// are simply omitted from the map.
-func resolveEmails(client *api.Client, emails []string) map[string]string {
-	resolved := make(map[string]string)
-	if len(emails) == 0 {
-		return resolved
+// parseVchat converts a --vc value to the event's video meeting settings
+func parseVchat(spec string) (*api.Vchat, error) {
+	switch {
+	case spec == "lark":
+		return &api.Vchat{VcType: "vc"}, nil
+	case spec == "none":
+		return &api.Vchat{VcType: "no_meeting"}, nil
+	case strings.HasPrefix(spec, "url:"):
+		link := strings.TrimSpace(strings.TrimPrefix(spec, "url:"))
+		u, err := url.Parse(link)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid --vc link: %s (must be an http or https URL)", link)
+		}
+		return &api.Vchat{VcType: "third_party", MeetingURL: link, IconType: "default"}, nil
+	default:
+		return nil, fmt.Errorf("invalid --vc: %s (use lark, none, or url:<link>)", spec)
 	}
+}
 
-	users, err := client.LookupUsers(api.UserLookupOptions{
-		Emails: emails,
-	})
-	if err != nil {
-		// Lookup failed - fall back to all third-party
-		return resolved
+// uploadAttachments uploads files to the calendar for attaching to an event
+func uploadAttachments(client *api.Client, calendarID string, paths []string) ([]api.Attachment, error) {
+	var attachments []api.Attachment
+	for _, p := range paths {
+		a, err := client.UploadEventAttachment(calendarID, p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload %s: %w", p, err)
+		}
+		attachments = append(attachments, *a)
 	}
+	return attachments, nil
+}
 
-	for _, u := range users {
-		if u.UserID != "" && u.Email != "" {
-			resolved[u.Email] = u.UserID
-		}
+// ensureMeetingURL refetches an event whose Lark meeting link wasn't in the
+// create or update response, which can happen while the link is generated
+func ensureMeetingURL(client *api.Client, calendarID string, event *api.Event) {
+	if event.Vchat == nil || event.Vchat.VcType != "vc" || event.Vchat.MeetingURL != "" {
+		return
+	}
+	if fetched, err := client.GetEvent(calendarID, event.EventID); err == nil && fetched.Vchat != nil {
+		event.Vchat = fetched.Vchat
 	}
-	return resolved
 }
 
 // parseHexColor converts a hex color string (e.g., "#9CA2A9") to an int32 RGB value for Lark API
@@ -2,38 +2,95 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/config"
 	"github.com/yjwong/lark-cli/internal/output"
 )
 
+var deleteScope string
+
 var deleteCmd = &cobra.Command{
 	Use:   "delete <event-id>",
 	Short: "Delete an event",
 	Long: `Delete a calendar event.
 
-Example:
-  lark cal delete efa67a98-06a8-4df5-8559-746c8f4477ef_0`,
+For occurrences of a recurring event, --scope selects what is deleted:
+  instance   only this occurrence (default)
+  following  this and all following occurrences
+  series     the entire series
+
+Examples:
+  lark cal delete efa67a98-06a8-4df5-8559-746c8f4477ef_0
+  lark cal delete efa67a98-06a8-4df5-8559-746c8f4477ef_1767582000 --scope following`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		eventID := args[0]
+		if err := validateRecurrenceScope(deleteScope); err != nil {
+			output.Fatal("VALIDATION_ERROR", err)
+		}
+
 		client := api.NewClient()
 
-		// Get primary calendar
-		cal, err := client.GetPrimaryCalendar()
+		// Get the selected calendar (primary unless --calendar is set)
+		cal, err := resolveCalendar(client)
 		if err != nil {
 			output.Fatal("CALENDAR_ERROR", err)
 		}
 
+		targetID := eventID
+		if deleteScope == scopeFollowing || deleteScope == scopeSeries {
+			event, err := client.GetEvent(cal.CalendarID, eventID)
+			if err != nil {
+				output.Fatal("EVENT_NOT_FOUND", err)
+			}
+			targetID = seriesID(event)
+
+			if deleteScope == scopeFollowing {
+				if event.RecurringEventID == "" {
+					output.Fatalf("VALIDATION_ERROR", "--scope following requires an occurrence of a recurring event")
+				}
+
+				loc, err := time.LoadLocation(config.GetTimezone())
+				if err != nil {
+					loc = time.Local
+				}
+
+				series, err := client.GetEvent(cal.CalendarID, targetID)
+				if err != nil {
+					output.Fatalf("API_ERROR", "Failed to fetch recurring series: %v", err)
+				}
+
+				// Deleting from the first occurrence on removes the whole series
+				splitAt := eventTime(event.StartTime, loc)
+				if eventTime(series.StartTime, loc).Before(splitAt) {
+					if _, err := truncateSeries(client, cal.CalendarID, series, splitAt); err != nil {
+						output.Fatal("API_ERROR", err)
+					}
+
+					output.JSON(map[string]interface{}{
+						"success": true,
+						"message": fmt.Sprintf("Deleted occurrences of %s from %s onwards", targetID, splitAt.Format(time.RFC3339)),
+					})
+					return
+				}
+			}
+		}
+
 		// Delete event
-		if err := client.DeleteEvent(cal.CalendarID, eventID); err != nil {
+		if err := client.DeleteEvent(cal.CalendarID, targetID); err != nil {
 			output.Fatal("API_ERROR", err)
 		}
 
 		output.JSON(map[string]interface{}{
 			"success": true,
-			"message": fmt.Sprintf("Event deleted: %s", eventID),
+			"message": fmt.Sprintf("Event deleted: %s", targetID),
 		})
 	},
 }
+
+func init() {
+	deleteCmd.Flags().StringVar(&deleteScope, "scope", "", "For recurring events: instance, following, or series")
+}
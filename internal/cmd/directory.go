@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/config"
+	"github.com/yjwong/lark-cli/internal/directory"
+	"github.com/yjwong/lark-cli/internal/output"
+	timex "github.com/yjwong/lark-cli/internal/time"
+)
+
+// rawIDs is the global --raw-ids flag
+var rawIDs bool
+
+// newDirectoryResolver wraps an open directory cache in a resolver using the
+// configured TTL. A nil client keeps it offline.
+func newDirectoryResolver(cache *directory.Cache, client *api.Client) *directory.Resolver {
+	r := &directory.Resolver{Cache: cache, Client: client, Aliases: make(map[string]string)}
+	for alias, ref := range config.GetAliases() {
+		r.Aliases[strings.ToLower(alias)] = ref
+	}
+	if ttl := config.GetDirectoryTTL(); ttl != "" {
+		if d, err := timex.ParseDuration(ttl); err == nil {
+			r.TTL = d
+		}
+	}
+	return r
+}
+
+// isAlias reports whether a reference is an alias from config
+func isAlias(ref string) bool {
+	for alias := range config.GetAliases() {
+		if strings.EqualFold(alias, ref) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveRefs resolves references to people, chats and departments (emails,
+// @names, open_ids, chat:<name>, dept:<name> or config aliases) through the
+// directory cache
+func resolveRefs(client *api.Client, refs []string) ([]directory.Identity, error) {
+	cache, err := directory.OpenCache()
+	if err != nil {
+		return nil, err
+	}
+	defer cache.Close()
+	return newDirectoryResolver(cache, client).ResolveAll(refs)
+}
+
+// resolveRef resolves a single reference, see resolveRefs
+func resolveRef(client *api.Client, ref string) (*directory.Identity, error) {
+	ids, err := resolveRefs(client, []string{ref})
+	if err != nil {
+		return nil, err
+	}
+	return &ids[0], nil
+}
+
+// resolveUserRef resolves a reference that must be a single Lark user
+func resolveUserRef(client *api.Client, ref string) (*directory.Identity, error) {
+	id, err := resolveRef(client, ref)
+	if err != nil {
+		return nil, err
+	}
+	switch id.Kind {
+	case directory.KindUser:
+		return id, nil
+	case directory.KindEmail:
+		return nil, fmt.Errorf("no Lark user found for %s", ref)
+	default:
+		return nil, fmt.Errorf("%s is a %s, not a user", ref, id.Kind)
+	}
+}
+
+// resolvePeople resolves references to users, expanding departments, and
+// chats unless keepChats is set, to their members. Duplicates are dropped;
+// emails that aren't Lark users are kept with kind "email".
+func resolvePeople(client *api.Client, refs []string, keepChats bool) ([]directory.Identity, error) {
+	var trimmed []string
+	for _, ref := range refs {
+		if ref = strings.TrimSpace(ref); ref != "" {
+			trimmed = append(trimmed, ref)
+		}
+	}
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+
+	cache, err := directory.OpenCache()
+	if err != nil {
+		return nil, err
+	}
+	defer cache.Close()
+	resolver := newDirectoryResolver(cache, client)
+
+	ids, err := resolver.ResolveAll(trimmed)
+	if err != nil {
+		return nil, err
+	}
+	var people []directory.Identity
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		members := []directory.Identity{id}
+		if id.Kind != directory.KindChat || !keepChats {
+			if members, err = resolver.Members(id); err != nil {
+				return nil, err
+			}
+		}
+		for _, m := range members {
+			key := m.ID
+			if key == "" {
+				key = strings.ToLower(m.Email)
+			}
+			if !seen[key] {
+				seen[key] = true
+				people = append(people, m)
+			}
+		}
+	}
+	return people, nil
+}
+
+// resolveAttendeeRefs is resolvePeople for attendee flags, which also take
+// raw user_ids as they did before names were accepted. Like a message
+// recipient, a bare token with a digit is a user_id unless it is an alias,
+// and one that names no user falls back to a user_id.
+func resolveAttendeeRefs(client *api.Client, refs []string) ([]directory.Identity, error) {
+	var people []directory.Identity
+	for _, ref := range refs {
+		ref = strings.TrimSpace(ref)
+		if ref == "" {
+			continue
+		}
+		raw := directory.Identity{Ref: ref, Kind: directory.KindUser, ID: ref}
+		if rawUserIDRe.MatchString(ref) && strings.ContainsAny(ref, "0123456789") && !isAlias(ref) {
+			people = append(people, raw)
+			continue
+		}
+		found, err := resolvePeople(client, []string{ref}, true)
+		var noMatch *directory.NoMatchError
+		if errors.As(err, &noMatch) && rawUserIDRe.MatchString(ref) {
+			people = append(people, raw)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		people = append(people, found...)
+	}
+	return people, nil
+}
+
+// outputNameResolver resolves open_ids in command output from the directory
+// cache only: IDs it doesn't hold are left without a name rather than looked
+// up, so output never waits on the network. It does nothing until the cache
+// holds users (after 'lark contact sync', a search or a name lookup).
+func outputNameResolver() output.NameResolver {
+	var once sync.Once
+	var resolver *directory.Resolver
+	return func(openIDs []string) map[string]string {
+		once.Do(func() {
+			if _, err := os.Stat(directory.CacheFilePath()); err != nil {
+				return
+			}
+			cache, err := directory.OpenCache()
+			if err != nil {
+				return
+			}
+			if users, _, err := cache.Counts(); err != nil || users == 0 {
+				cache.Close()
+				return
+			}
+			resolver = newDirectoryResolver(cache, nil)
+		})
+		if resolver == nil {
+			return nil
+		}
+		return resolver.Names(openIDs)
+	}
+}
+
+// --- contact sync ---
+
+var contactSyncDept string
+
+var contactSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync the company directory to the local cache",
+	Long: `Fetch every department and user into the local directory cache.
+
+The cache backs 'lark contact search' and adds names next to open_ids in the
+output of every command (disable with --raw-ids). Entries older than
+directory.ttl in the config (default 168h) are refetched when they are used;
+run sync again to pick up new joiners and drop leavers.
+
+Examples:
+  lark contact sync
+  lark contact sync --dept od_xxxx`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := api.NewClient()
+
+		cache, err := directory.OpenCache()
+		if err != nil {
+			output.Fatal("SYNC_ERROR", err)
+		}
+		defer cache.Close()
+
+		result, err := directory.Sync(client, cache, &directory.SyncOptions{
+			Root:     contactSyncDept,
+			Progress: os.Stderr,
+		})
+		if err != nil {
+			output.Fatal("SYNC_ERROR", err)
+		}
+
+		output.JSON(result)
+	},
+}
+
+// --- contact resolve ---
+
+var contactResolveExpand bool
+
+var contactResolveCmd = &cobra.Command{
+	Use:   "resolve <ref>...",
+	Short: "Resolve people, chats and departments to IDs",
+	Long: `Show what references resolve to. Every command that takes people or chats
+accepts the same forms:
+
+  alice@example.com     a user by email (kind "email" if not a Lark user)
+  @alice, "Alice Wong"  a user by name, nickname, email name or pinyin
+  ou_xxx                a user by open_id
+  oc_xxx, chat:<name>   a group chat by ID or name
+  od_xxx, dept:<name>   a department by ID or name
+  <alias>               an entry under aliases in the config, e.g.
+                          aliases:
+                            boss: alice@example.com
+                            standup: "chat:Team Standup"
+
+Names must match one user; an ambiguous name lists the candidates. Results
+are cached in the directory cache.
+
+Examples:
+  lark contact resolve alice@example.com @bob "chat:Team Standup" dept:Design
+  lark contact resolve standup --expand`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := api.NewClient()
+
+		var ids []directory.Identity
+		var err error
+		if contactResolveExpand {
+			ids, err = resolvePeople(client, args, false)
+		} else {
+			ids, err = resolveRefs(client, args)
+		}
+		if err != nil {
+			output.Fatal("RESOLVE_ERROR", err)
+		}
+
+		output.JSON(map[string]interface{}{
+			"identities": ids,
+			"count":      len(ids),
+		})
+	},
+}
+
+func init() {
+	contactResolveCmd.Flags().BoolVar(&contactResolveExpand, "expand", false, "Expand chats and departments to their members")
+	contactSyncCmd.Flags().StringVar(&contactSyncDept, "dept", "0", "Only sync this department and its sub-departments")
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/config"
+	"github.com/yjwong/lark-cli/internal/directory"
+)
+
+// Output names come from the cache alone: with no cache, an empty one or
+// stale entries, nothing is fetched
+func TestOutputNameResolverOffline(t *testing.T) {
+	t.Setenv("LARK_CONFIG_DIR", t.TempDir())
+	if err := config.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	if names := outputNameResolver()([]string{"ou_a1"}); names != nil {
+		t.Errorf("no cache: got %v, want nil", names)
+	}
+
+	cache, err := directory.OpenCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+	if names := outputNameResolver()([]string{"ou_a1"}); names != nil {
+		t.Errorf("empty cache: got %v, want nil", names)
+	}
+
+	stale := time.Now().AddDate(-1, 0, 0)
+	if err := cache.PutUsers([]api.ContactUser{{OpenID: "ou_a1", Name: "Alice"}}, stale); err != nil {
+		t.Fatal(err)
+	}
+	names := outputNameResolver()([]string{"ou_a1", "ou_b2"})
+	if len(names) != 1 || names["ou_a1"] != "Alice" {
+		t.Errorf("stale cache: got %v, want only ou_a1 -> Alice", names)
+	}
+}
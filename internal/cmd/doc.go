@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/directory"
 	"github.com/yjwong/lark-cli/internal/output"
 )
 
@@ -604,7 +605,8 @@ Document types: doc, sheet, slide, bitable, mindnote, file
 Examples:
   lark doc search "project plan"
   lark doc search "budget" --type sheet
-  lark doc search "meeting notes" --type doc --type sheet`,
+  lark doc search "meeting notes" --type doc --type sheet
+  lark doc search "roadmap" --owner alice@example.com`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		query := args[0]
@@ -614,6 +616,25 @@ Examples:
 
 		client := api.NewClient()
 
+		// Owners and chats can be given by email, name or alias too
+		for i, ref := range ownerIDs {
+			owner, err := resolveUserRef(client, ref)
+			if err != nil {
+				output.Fatal("VALIDATION_ERROR", err)
+			}
+			ownerIDs[i] = owner.ID
+		}
+		for i, ref := range chatIDs {
+			chat, err := resolveRef(client, ref)
+			if err != nil {
+				output.Fatal("VALIDATION_ERROR", err)
+			}
+			if chat.Kind != directory.KindChat {
+				output.Fatalf("VALIDATION_ERROR", "%s is a %s, not a chat", ref, chat.Kind)
+			}
+			chatIDs[i] = chat.ID
+		}
+
 		results, total, err := client.SearchDocuments(query, ownerIDs, chatIDs, docTypes)
 		if err != nil {
 			output.Fatal("API_ERROR", err)
@@ -1017,8 +1038,8 @@ func init() {
 	docWikiListCmd.Flags().String("node-token", "", "Resolve this node and list its immediate children")
 
 	// Flags for doc search
-	docSearchCmd.Flags().StringSlice("owner", nil, "Filter by owner user ID (can be repeated)")
-	docSearchCmd.Flags().StringSlice("chat", nil, "Filter by chat ID (can be repeated)")
+	docSearchCmd.Flags().StringSlice("owner", nil, "Filter by owner: open_id, email, name or alias (can be repeated)")
+	docSearchCmd.Flags().StringSlice("chat", nil, "Filter by chat: chat ID, chat:<name> or alias (can be repeated)")
 	docSearchCmd.Flags().StringSlice("type", nil, "Filter by doc type: doc, sheet, slide, bitable, mindnote, file (can be repeated)")
 
 	// Flags for doc image
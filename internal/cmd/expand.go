@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/config"
+	"github.com/yjwong/lark-cli/internal/output"
+	timex "github.com/yjwong/lark-cli/internal/time"
+)
+
+var (
+	expandRRules   []string
+	expandStart    string
+	expandFrom     string
+	expandTo       string
+	expandExDates  []string
+	expandRDates   []string
+	expandDuration string
+	expandTimezone string
+	expandEvent    string
+	expandLimit    int
+)
+
+var expandCmd = &cobra.Command{
+	Use:   "expand",
+	Short: "List the occurrences of a recurrence rule",
+	Long: `Expand an RFC 5545 recurrence rule locally, without calling the API.
+
+Supports FREQ (YEARLY to SECONDLY), INTERVAL, COUNT, UNTIL, BYMONTH,
+BYWEEKNO, BYYEARDAY, BYMONTHDAY, BYDAY (including 1MO, -1FR), BYHOUR,
+BYMINUTE, BYSECOND, BYSETPOS and WKST, plus EXDATE and RDATE.
+
+Occurrences keep their wall-clock time in --timezone across DST changes. A
+time that doesn't exist on a DST change day moves forward by the gap, as
+RFC 5545 specifies. --start (DTSTART) is always the first occurrence and
+counts toward COUNT.
+
+With --event, the rule, start and duration come from an existing recurring
+event; pass --rrule as well to preview a change to its rule.
+
+Examples:
+  lark cal expand --rrule "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10" --start 2026-01-05T09:00
+  lark cal expand --rrule "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1" --from 2026-01-01 --to 2026-12-31
+  lark cal expand --rrule "FREQ=DAILY" --start 2026-03-06T09:00 --to 2026-03-12 --timezone America/New_York --exdate 2026-03-09
+  lark cal expand --event <event-id> --rrule "FREQ=WEEKLY;INTERVAL=2" --to "in 3 months"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(expandRRules) == 0 && expandEvent == "" {
+			output.Fatalf("VALIDATION_ERROR", "--rrule or --event is required")
+		}
+		if expandLimit < 1 {
+			output.Fatalf("VALIDATION_ERROR", "--limit must be positive")
+		}
+
+		tz := config.GetTimezone()
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			loc = time.Local
+		}
+		if expandTimezone != "" {
+			if loc, err = timex.LoadLocation(expandTimezone); err != nil {
+				output.Fatal("VALIDATION_ERROR", err)
+			}
+		}
+		tz = loc.String()
+
+		var start time.Time
+		var duration time.Duration
+		rules := expandRRules
+		var eventSummary string
+		if expandEvent != "" {
+			client := api.NewClient()
+
+			// Get the selected calendar (primary unless --calendar is set)
+			cal, err := resolveCalendar(client)
+			if err != nil {
+				output.Fatal("CALENDAR_ERROR", err)
+			}
+			event, err := client.GetEvent(cal.CalendarID, expandEvent)
+			if err != nil {
+				output.Fatal("EVENT_NOT_FOUND", err)
+			}
+			// Occurrences don't carry the RRULE; use the series
+			if event.RecurringEventID != "" {
+				if event, err = client.GetEvent(cal.CalendarID, event.RecurringEventID); err != nil {
+					output.Fatal("EVENT_NOT_FOUND", err)
+				}
+			}
+			if len(rules) == 0 {
+				if event.Recurrence == "" {
+					output.Fatalf("VALIDATION_ERROR", "Event %s is not recurring", expandEvent)
+				}
+				rules = []string{event.Recurrence}
+			}
+			if event.StartTime != nil && event.StartTime.Timezone != "" && expandTimezone == "" {
+				if eventLoc, err := timex.LoadLocation(event.StartTime.Timezone); err == nil {
+					loc, tz = eventLoc, eventLoc.String()
+				}
+			}
+			start = eventTime(event.StartTime, loc)
+			duration = eventTime(event.EndTime, loc).Sub(start)
+			eventSummary = event.Summary
+		}
+
+		if expandStart != "" {
+			if start, err = timex.Parse(expandStart, loc); err != nil {
+				output.Fatalf("PARSE_ERROR", "Failed to parse --start: %v", err)
+			}
+		}
+		if expandDuration != "" {
+			if duration, err = timex.ParseDuration(expandDuration); err != nil {
+				output.Fatalf("PARSE_ERROR", "Failed to parse --duration: %v", err)
+			}
+		}
+
+		from := start
+		if expandFrom != "" {
+			if from, err = timex.Parse(expandFrom, loc); err != nil {
+				output.Fatalf("PARSE_ERROR", "Failed to parse --from: %v", err)
+			}
+		}
+		if start.IsZero() {
+			if from.IsZero() {
+				output.Fatalf("VALIDATION_ERROR", "--start or --from is required")
+			}
+			start = from
+		}
+		// Wall-clock arithmetic needs the IANA zone, not a fixed offset
+		start = start.In(loc)
+
+		to := from.AddDate(1, 0, 0)
+		if expandTo != "" {
+			if to, err = timex.Parse(expandTo, loc); err != nil {
+				output.Fatalf("PARSE_ERROR", "Failed to parse --to: %v", err)
+			}
+			if !containsTimeSpec(expandTo) {
+				to = timex.EndOfDay(to)
+			}
+		}
+		if to.Before(from) {
+			output.Fatalf("VALIDATION_ERROR", "--to must be after --from")
+		}
+
+		rec, err := timex.ParseRecurrence(strings.Join(rules, "\n"), start)
+		if err != nil {
+			output.Fatal("PARSE_ERROR", err)
+		}
+		for _, d := range expandExDates {
+			if err := rec.AddDate("EXDATE", d); err != nil {
+				output.Fatal("PARSE_ERROR", err)
+			}
+		}
+		for _, d := range expandRDates {
+			if err := rec.AddDate("RDATE", d); err != nil {
+				output.Fatal("PARSE_ERROR", err)
+			}
+		}
+
+		occurrences, truncated := rec.Between(from, to, expandLimit)
+		outOccurrences := make([]map[string]interface{}, len(occurrences))
+		for i, t := range occurrences {
+			occ := map[string]interface{}{"start": t.Format(time.RFC3339)}
+			if duration > 0 {
+				occ["end"] = t.Add(duration).Format(time.RFC3339)
+			}
+			outOccurrences[i] = occ
+		}
+
+		normalized := make([]string, len(rec.Rules))
+		for i, r := range rec.Rules {
+			normalized[i] = r.String()
+		}
+
+		result := map[string]interface{}{
+			"rrules":      normalized,
+			"start":       start.Format(time.RFC3339),
+			"timezone":    tz,
+			"from":        from.Format(time.RFC3339),
+			"to":          to.Format(time.RFC3339),
+			"occurrences": outOccurrences,
+			"count":       len(outOccurrences),
+			"truncated":   truncated,
+		}
+		if expandEvent != "" {
+			result["event_id"] = expandEvent
+			result["summary"] = eventSummary
+		}
+		output.JSON(result)
+	},
+}
+
+func init() {
+	expandCmd.Flags().StringArrayVar(&expandRRules, "rrule", []string{}, "Recurrence rule, e.g. FREQ=WEEKLY;BYDAY=MO (repeatable)")
+	expandCmd.Flags().StringVar(&expandStart, "start", "", "First occurrence, DTSTART (default: --from)")
+	expandCmd.Flags().StringVar(&expandFrom, "from", "", "List occurrences from (default: --start)")
+	expandCmd.Flags().StringVar(&expandTo, "to", "", "List occurrences until (default: a year after --from)")
+	expandCmd.Flags().StringSliceVar(&expandExDates, "exdate", []string{}, "Exclude an occurrence, or a whole day when no time is given (repeatable)")
+	expandCmd.Flags().StringSliceVar(&expandRDates, "rdate", []string{}, "Add an occurrence; a day uses the start's time (repeatable)")
+	expandCmd.Flags().StringVar(&expandDuration, "duration", "", "Length of each occurrence, to include end times (e.g. 30m, 1h)")
+	expandCmd.Flags().StringVar(&expandTimezone, "timezone", "", "IANA zone the rule is evaluated in (default: config timezone, or the event's)")
+	expandCmd.Flags().StringVar(&expandEvent, "event", "", "Take the rule, start and duration from this recurring event")
+	expandCmd.Flags().IntVar(&expandLimit, "limit", 500, "Maximum occurrences to list")
+}
@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/config"
+	"github.com/yjwong/lark-cli/internal/ics"
+	"github.com/yjwong/lark-cli/internal/output"
+	timex "github.com/yjwong/lark-cli/internal/time"
+)
+
+// icsUIDSuffix is appended to Lark event IDs to form iCalendar UIDs
+const icsUIDSuffix = "@lark"
+
+var (
+	exportFrom   string
+	exportTo     string
+	exportFormat string
+	exportOutput string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export events as iCalendar",
+	Long: `Export events in a date range as an RFC 5545 iCalendar (.ics) file.
+
+Each occurrence of a recurring event is exported as its own VEVENT. Attendees,
+location, meeting URL and reminders are included. Timed events keep their
+time zone (with a matching VTIMEZONE); all-day events are exported as dates.
+
+Examples:
+  lark cal export --from 2026-01-01 --to 2026-01-31 --output january.ics
+  lark cal export --from 2026-01-05 --to 2026-01-09`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if exportFormat != "ics" {
+			output.Fatalf("VALIDATION_ERROR", "Invalid format: %s (only ics is supported)", exportFormat)
+		}
+
+		client := api.NewClient()
+
+		// Get the selected calendar (primary unless --calendar is set)
+		cal, err := resolveCalendar(client)
+		if err != nil {
+			output.Fatal("CALENDAR_ERROR", err)
+		}
+
+		tz := config.GetTimezone()
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			loc = time.Local
+		}
+		now := time.Now().In(loc)
+
+		startTime := timex.StartOfDay(now)
+		if exportFrom != "" {
+			startTime, err = timex.Parse(exportFrom, loc)
+			if err != nil {
+				output.Fatalf("PARSE_ERROR", "Failed to parse --from: %v", err)
+			}
+			startTime = timex.StartOfDay(startTime)
+		}
+		endTime := timex.EndOfDay(startTime.AddDate(0, 1, 0))
+		if exportTo != "" {
+			endTime, err = timex.Parse(exportTo, loc)
+			if err != nil {
+				output.Fatalf("PARSE_ERROR", "Failed to parse --to: %v", err)
+			}
+			endTime = timex.EndOfDay(endTime)
+		}
+
+		events, err := client.ListEvents(api.ListEventsOptions{
+			CalendarID: cal.CalendarID,
+			StartTime:  startTime,
+			EndTime:    endTime,
+		})
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+
+		// The instance view omits reminders and may truncate attendees
+		for i := range events {
+			full, err := client.GetEvent(cal.CalendarID, events[i].EventID)
+			if err != nil || full == nil {
+				continue
+			}
+			events[i].Reminders = full.Reminders
+			events[i].CreateTime = full.CreateTime
+			if len(full.Attendees) > 0 {
+				events[i].Attendees = full.Attendees
+			}
+			if full.HasMoreAttendee {
+				if attendees, err := client.ListEventAttendees(cal.CalendarID, events[i].EventID); err == nil {
+					events[i].Attendees = attendees
+				}
+			}
+		}
+
+		emails := resolveAttendeeEmails(client, events)
+
+		icsEvents := make([]ics.Event, len(events))
+		for i, e := range events {
+			icsEvents[i] = eventToICS(e, loc, emails)
+		}
+
+		var buf bytes.Buffer
+		if err := ics.Encode(&buf, icsEvents); err != nil {
+			output.Fatal("EXPORT_ERROR", err)
+		}
+
+		result := map[string]interface{}{
+			"format": exportFormat,
+			"from":   startTime.Format(time.RFC3339),
+			"to":     endTime.Format(time.RFC3339),
+			"count":  len(icsEvents),
+		}
+
+		if exportOutput != "" {
+			if err := os.WriteFile(exportOutput, buf.Bytes(), 0644); err != nil {
+				output.Fatal("FILE_ERROR", err)
+			}
+			result["success"] = true
+			result["file"] = exportOutput
+			output.JSON(result)
+			return
+		}
+
+		result["content"] = buf.String()
+		output.JSON(result)
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFrom, "from", "", "Start date (default: today)")
+	exportCmd.Flags().StringVar(&exportTo, "to", "", "End date (default: one month after --from)")
+	exportCmd.Flags().StringVar(&exportFormat, "format", "ics", "Export format (ics)")
+	exportCmd.Flags().StringVar(&exportOutput, "output", "", "Write to file instead of stdout")
+}
+
+// resolveAttendeeEmails looks up email addresses for Lark user attendees.
+// Users that can't be resolved are omitted from the map.
+func resolveAttendeeEmails(client *api.Client, events []api.Event) map[string]string {
+	emails := make(map[string]string)
+	tried := make(map[string]bool)
+	for _, e := range events {
+		for _, att := range e.Attendees {
+			if att.Type != "user" || att.UserID == "" || tried[att.UserID] {
+				continue
+			}
+			tried[att.UserID] = true
+			user, err := client.GetUser(att.UserID, "open_id")
+			if err != nil || user == nil {
+				continue
+			}
+			if user.Email != "" {
+				emails[att.UserID] = user.Email
+			} else if user.EnterpriseEmail != "" {
+				emails[att.UserID] = user.EnterpriseEmail
+			}
+		}
+	}
+	return emails
+}
+
+// eventToICS converts a Lark event to its iCalendar form
+func eventToICS(e api.Event, defaultLoc *time.Location, emails map[string]string) ics.Event {
+	out := ics.Event{
+		UID:         e.EventID + icsUIDSuffix,
+		Summary:     e.Summary,
+		Description: e.Description,
+		Status:      strings.ToUpper(e.Status),
+		Transparent: e.FreeBusyStatus == "free",
+	}
+
+	if e.StartTime != nil && e.StartTime.Date != "" {
+		out.AllDay = true
+		out.Start, _ = time.Parse("2006-01-02", e.StartTime.Date)
+		if e.EndTime != nil && e.EndTime.Date != "" {
+			out.End, _ = time.Parse("2006-01-02", e.EndTime.Date)
+		}
+	} else {
+		out.Start = eventTime(e.StartTime, eventLocation(e.StartTime, defaultLoc))
+		out.End = eventTime(e.EndTime, eventLocation(e.EndTime, defaultLoc))
+	}
+
+	if e.Location != nil {
+		out.Location = e.Location.Name
+		if e.Location.Address != "" && e.Location.Address != e.Location.Name {
+			if out.Location != "" {
+				out.Location += ", "
+			}
+			out.Location += e.Location.Address
+		}
+	}
+	if e.Vchat != nil {
+		out.URL = e.Vchat.MeetingURL
+	}
+
+	switch e.Visibility {
+	case "private":
+		out.Class = "PRIVATE"
+	case "public":
+		out.Class = "PUBLIC"
+	}
+
+	if e.CreateTime != "" {
+		out.Created = eventTime(&api.TimeInfo{Timestamp: e.CreateTime}, time.UTC)
+	}
+
+	for _, r := range e.Reminders {
+		out.Alarms = append(out.Alarms, r.Minutes)
+	}
+
+	for _, att := range e.Attendees {
+		a := ics.Attendee{
+			Name:     att.DisplayName,
+			PartStat: icsPartStat(att.RsvpStatus),
+			Optional: att.IsOptional,
+		}
+		switch att.Type {
+		case "user":
+			if email, ok := emails[att.UserID]; ok {
+				a.Email = email
+			} else {
+				a.URI = "urn:x-lark:open_id:" + att.UserID
+			}
+		case "third_party":
+			a.Email = att.ThirdPartyEmail
+		case "chat":
+			a.CUType = "GROUP"
+			a.URI = "urn:x-lark:chat:" + att.ChatID
+		case "resource":
+			a.CUType = "ROOM"
+			a.URI = "urn:x-lark:room:" + att.RoomID
+		}
+		if a.Email == "" && a.URI == "" {
+			continue
+		}
+		if att.IsOrganizer {
+			org := a
+			out.Organizer = &org
+		}
+		out.Attendees = append(out.Attendees, a)
+	}
+
+	return out
+}
+
+// eventLocation returns the time zone recorded on a Lark time, or def
+func eventLocation(ti *api.TimeInfo, def *time.Location) *time.Location {
+	if ti != nil && ti.Timezone != "" {
+		if loc, err := time.LoadLocation(ti.Timezone); err == nil {
+			return loc
+		}
+	}
+	return def
+}
+
+// icsPartStat maps Lark RSVP status to an iCalendar PARTSTAT
+func icsPartStat(rsvp string) string {
+	switch rsvp {
+	case "accept":
+		return "ACCEPTED"
+	case "decline":
+		return "DECLINED"
+	case "tentative":
+		return "TENTATIVE"
+	case "needs_action":
+		return "NEEDS-ACTION"
+	}
+	return ""
+}
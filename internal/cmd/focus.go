@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/config"
+	"github.com/yjwong/lark-cli/internal/conflicts"
+	"github.com/yjwong/lark-cli/internal/output"
+	"github.com/yjwong/lark-cli/internal/scheduler"
+	timex "github.com/yjwong/lark-cli/internal/time"
+)
+
+var (
+	focusHours     float64
+	focusWeek      bool
+	focusFrom      string
+	focusTo        string
+	focusMinBlock  string
+	focusMaxBlock  string
+	focusKeepFree  string
+	focusBuffer    int
+	focusWorkHours string
+	focusSummary   string
+	focusDryRun    bool
+)
+
+var focusCmd = &cobra.Command{
+	Use:   "focus",
+	Short: "Block focus time in free gaps",
+	Long: `Create busy focus events in free working time until a weekly target is met.
+
+Gaps are found from your free/busy, so declined and free events don't block
+focus time. Blocks are spread across days, at least --min-block and at most
+--max-block long, keep --buffer minutes from other meetings, and leave
+--keep-free of each day open for meetings that come up. Existing events
+named like --summary count toward the target, so re-running is safe.
+
+By default, plans the rest of the current week. Use --dry-run to preview.
+
+Examples:
+  lark cal focus --hours 10 --week --dry-run
+  lark cal focus --hours 10 --week
+  lark cal focus --hours 6 --from tomorrow --to friday --min-block 90m --max-block 2h
+  lark cal focus --hours 8 --keep-free 2h --summary "Deep work"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if focusHours <= 0 {
+			output.Fatalf("VALIDATION_ERROR", "--hours must be positive")
+		}
+		minBlock, err := timex.ParseDuration(focusMinBlock)
+		if err != nil {
+			output.Fatalf("PARSE_ERROR", "Failed to parse --min-block: %v", err)
+		}
+		maxBlock, err := timex.ParseDuration(focusMaxBlock)
+		if err != nil {
+			output.Fatalf("PARSE_ERROR", "Failed to parse --max-block: %v", err)
+		}
+		if maxBlock < minBlock {
+			output.Fatalf("VALIDATION_ERROR", "--max-block must be at least --min-block")
+		}
+		keepFree, err := timex.ParseDuration(focusKeepFree)
+		if err != nil {
+			output.Fatalf("PARSE_ERROR", "Failed to parse --keep-free: %v", err)
+		}
+		if focusWorkHours != "" {
+			if _, _, err := timex.ParseClockRange(focusWorkHours); err != nil {
+				output.Fatal("VALIDATION_ERROR", err)
+			}
+		}
+
+		// Parse timezone
+		tz := config.GetTimezone()
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			loc = time.Local
+			tz = loc.String()
+		}
+		now := time.Now().In(loc)
+
+		startTime := timex.StartOfWeek(now)
+		endTime := timex.EndOfWeek(now)
+		if !focusWeek && focusFrom != "" {
+			startTime, err = timex.Parse(focusFrom, loc)
+			if err != nil {
+				output.Fatalf("PARSE_ERROR", "Failed to parse --from: %v", err)
+			}
+			if !containsTimeSpec(focusFrom) {
+				startTime = timex.StartOfDay(startTime)
+			}
+		}
+		if !focusWeek && focusTo != "" {
+			endTime, err = timex.Parse(focusTo, loc)
+			if err != nil {
+				output.Fatalf("PARSE_ERROR", "Failed to parse --to: %v", err)
+			}
+			if !containsTimeSpec(focusTo) {
+				endTime = timex.EndOfDay(endTime)
+			}
+		}
+		if !endTime.After(startTime) {
+			output.Fatalf("VALIDATION_ERROR", "--to must be after --from")
+		}
+
+		client := api.NewClient()
+
+		// Get the selected calendar (primary unless --calendar is set)
+		cal, err := resolveCalendar(client)
+		if err != nil {
+			output.Fatal("CALENDAR_ERROR", err)
+		}
+
+		wh, err := loadWorkingHours(client, focusWorkHours, startTime, endTime)
+		if err != nil {
+			output.Fatal("CONFIG_ERROR", err)
+		}
+
+		currentUser, err := client.GetCurrentUser()
+		if err != nil {
+			output.Fatal("USER_ERROR", err)
+		}
+		self, err := scheduleParticipant(client, currentUser.OpenID, false, loc, startTime, endTime)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+
+		// Focus events already on the calendar count toward the target
+		events, err := client.ListEvents(api.ListEventsOptions{
+			CalendarID: cal.CalendarID,
+			StartTime:  startTime,
+			EndTime:    endTime,
+		})
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+		var existing time.Duration
+		for _, e := range events {
+			if e.Status == "cancelled" || e.StartTime == nil || e.StartTime.Date != "" || !strings.EqualFold(strings.TrimSpace(e.Summary), focusSummary) {
+				continue
+			}
+			s, end := eventTime(e.StartTime, loc), eventTime(e.EndTime, loc)
+			if s.Before(startTime) {
+				s = startTime
+			}
+			if end.After(endTime) {
+				end = endTime
+			}
+			if end.After(s) {
+				existing += end.Sub(s)
+			}
+		}
+
+		target := time.Duration(focusHours*float64(time.Hour)) - existing
+		blocks := scheduler.PlanFocus(self.Busy, startTime, endTime, scheduler.FocusOptions{
+			Target:       target,
+			MinBlock:     minBlock,
+			MaxBlock:     maxBlock,
+			KeepFree:     keepFree,
+			Buffer:       time.Duration(focusBuffer) * time.Minute,
+			WorkingHours: wh,
+			Now:          now,
+		})
+
+		// Double-check the plan against your calendar with the conflict detector
+		blocks, planConflicts := dropConflictingBlocks(blocks, self.Busy, focusBuffer)
+
+		var planned time.Duration
+		outBlocks := []api.OutputBlock{}
+		for _, b := range blocks {
+			planned += b.End.Sub(b.Start)
+			outBlocks = append(outBlocks, api.OutputBlock{
+				Summary: focusSummary,
+				Start:   b.Start.In(loc).Format(time.RFC3339),
+				End:     b.End.In(loc).Format(time.RFC3339),
+				Hours:   hoursOf(b.End.Sub(b.Start)),
+			})
+		}
+
+		if !focusDryRun {
+			for i, b := range blocks {
+				event, err := client.CreateEvent(cal.CalendarID, &api.CreateEventRequest{
+					Summary: focusSummary,
+					StartTime: &api.TimeInfo{
+						Timestamp: strconv.FormatInt(b.Start.Unix(), 10),
+						Timezone:  tz,
+					},
+					EndTime: &api.TimeInfo{
+						Timestamp: strconv.FormatInt(b.End.Unix(), 10),
+						Timezone:  tz,
+					},
+					FreeBusyStatus: "busy",
+				})
+				if err != nil {
+					output.Fatalf("API_ERROR", "Failed to create focus block at %s (created %d of %d): %v", outBlocks[i].Start, i, len(blocks), err)
+				}
+				outBlocks[i].EventID = event.EventID
+			}
+		}
+
+		result := map[string]interface{}{
+			"dry_run":        focusDryRun,
+			"target_hours":   focusHours,
+			"existing_hours": hoursOf(existing),
+			"planned_hours":  hoursOf(planned),
+			"blocks":         outBlocks,
+			"conflicts":      planConflicts,
+		}
+		if existing+planned < time.Duration(focusHours*float64(time.Hour)) {
+			result["message"] = fmt.Sprintf("Only %.1f of %.1f focus hours fit in free working time", hoursOf(existing+planned), focusHours)
+		}
+		output.JSON(result)
+	},
+}
+
+func init() {
+	focusCmd.Flags().Float64Var(&focusHours, "hours", 10, "Target focus hours for the range")
+	focusCmd.Flags().BoolVar(&focusWeek, "week", false, "Plan this week (default)")
+	focusCmd.Flags().StringVar(&focusFrom, "from", "", "Start of range (default: start of this week)")
+	focusCmd.Flags().StringVar(&focusTo, "to", "", "End of range (default: end of this week)")
+	focusCmd.Flags().StringVar(&focusMinBlock, "min-block", "1h", "Shortest focus block")
+	focusCmd.Flags().StringVar(&focusMaxBlock, "max-block", "3h", "Longest focus block")
+	focusCmd.Flags().StringVar(&focusKeepFree, "keep-free", "1h", "Free time to leave open each day")
+	focusCmd.Flags().IntVar(&focusBuffer, "buffer", 10, "Minutes to keep between focus blocks and meetings")
+	focusCmd.Flags().StringVar(&focusWorkHours, "work-hours", "", "Working hours, HH:MM-HH:MM (default: working_hours from config)")
+	focusCmd.Flags().StringVar(&focusSummary, "summary", "Focus", "Title of focus events")
+	focusCmd.Flags().BoolVar(&focusDryRun, "dry-run", false, "Preview blocks without creating events")
+}
+
+// dropConflictingBlocks runs the conflict detector over the busy periods
+// and planned blocks, removing any block that conflicts. It returns the
+// remaining blocks and the conflicts found.
+func dropConflictingBlocks(blocks, busy []scheduler.Interval, bufferMinutes int) ([]scheduler.Interval, []api.Conflict) {
+	var slots []conflicts.EventTimeSlot
+	for i, b := range busy {
+		slots = append(slots, conflicts.EventTimeSlot{ID: fmt.Sprintf("busy-%d", i), Start: b.Start, End: b.End})
+	}
+	for i, b := range blocks {
+		slots = append(slots, conflicts.EventTimeSlot{ID: fmt.Sprintf("block-%d", i), Start: b.Start, End: b.End})
+	}
+
+	result := conflicts.Detect(slots, conflicts.Options{BufferMinutes: bufferMinutes})
+
+	var kept []scheduler.Interval
+	for i, b := range blocks {
+		if _, conflicting := result.ConflictMap[fmt.Sprintf("block-%d", i)]; !conflicting {
+			kept = append(kept, b)
+		}
+	}
+
+	// Conflicts between existing meetings aren't ours to report
+	found := []api.Conflict{}
+	for _, c := range result.Conflicts {
+		if strings.HasPrefix(c.EventIDs[0], "block-") || strings.HasPrefix(c.EventIDs[1], "block-") {
+			found = append(found, c)
+		}
+	}
+	return kept, found
+}
+
+// hoursOf converts a duration to hours, rounded to two decimals
+func hoursOf(d time.Duration) float64 {
+	return math.Round(d.Hours()*100) / 100
+}
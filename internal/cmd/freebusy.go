@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -28,6 +27,8 @@ By default, queries your own availability. Use --user or --room to check others.
 Examples:
   lark cal freebusy --from 2026-01-03T09:00:00+08:00 --to 2026-01-03T18:00:00+08:00
   lark cal freebusy --from 2026-01-03 --to 2026-01-03 --user ou_xxxxxxxxxx
+  lark cal freebusy --from "tomorrow 9am" --to "tomorrow 6pm" --user alice@example.com
+  lark cal freebusy --from "tomorrow 9am" --to "tomorrow 6pm"
   lark cal freebusy --from 2026-01-06 --to 2026-01-10 --room omm_xxxxxxxxxx`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if freebusyFrom == "" || freebusyTo == "" {
@@ -71,9 +72,15 @@ Examples:
 
 		client := api.NewClient()
 
-		// If no user or room specified, get current user's open_id
-		userID := freebusyUser
-		if userID == "" && freebusyRoom == "" {
+		// Resolve --user, or use the current user's open_id if no user or room is specified
+		var userID string
+		if freebusyUser != "" {
+			user, err := resolveUserRef(client, freebusyUser)
+			if err != nil {
+				output.Fatal("USER_ERROR", err)
+			}
+			userID = user.ID
+		} else if freebusyRoom == "" {
 			user, err := client.GetCurrentUser()
 			if err != nil {
 				output.Fatal("USER_ERROR", err)
@@ -95,12 +102,12 @@ Examples:
 			output.Fatal("API_ERROR", err)
 		}
 
-		// Convert to output format
+		// Convert to output format, in the same zone as the query
 		busyPeriods := make([]api.OutputFreebusyPeriod, len(periods))
 		for i, p := range periods {
 			busyPeriods[i] = api.OutputFreebusyPeriod{
-				Start: p.StartTime,
-				End:   p.EndTime,
+				Start: inLocation(p.StartTime, loc),
+				End:   inLocation(p.EndTime, loc),
 			}
 		}
 
@@ -119,24 +126,24 @@ Examples:
 }
 
 func init() {
-	freebusyCmd.Flags().StringVar(&freebusyFrom, "from", "", "Start time (required, ISO 8601)")
-	freebusyCmd.Flags().StringVar(&freebusyTo, "to", "", "End time (required, ISO 8601)")
-	freebusyCmd.Flags().StringVar(&freebusyUser, "user", "", "User open_id to check (default: self)")
+	freebusyCmd.Flags().StringVar(&freebusyFrom, "from", "", "Start time (required, ISO 8601 or expression like tomorrow 9am)")
+	freebusyCmd.Flags().StringVar(&freebusyTo, "to", "", "End time (required, ISO 8601 or expression like tomorrow 6pm)")
+	freebusyCmd.Flags().StringVar(&freebusyUser, "user", "", "User to check: open_id, email, name or alias (default: self)")
 	freebusyCmd.Flags().StringVar(&freebusyRoom, "room", "", "Meeting room room_id to check")
 }
 
-// containsTimeSpec checks if a time string contains a time specification
+// containsTimeSpec checks if a time string contains a time specification,
+// as opposed to naming a whole day
 func containsTimeSpec(s string) bool {
-	s = strings.ToLower(s)
-	// Check for time patterns like "10:00", "T15:00"
-	if strings.Contains(s, ":") {
-		return true
-	}
-	// Check for ISO 8601 time separator (T followed by digit)
-	for i := 0; i < len(s)-1; i++ {
-		if s[i] == 't' && s[i+1] >= '0' && s[i+1] <= '9' {
-			return true
-		}
+	return timex.HasTime(s)
+}
+
+// inLocation re-renders an API timestamp (RFC 3339, or "YYYY-MM-DD HH:MM:SS"
+// read in loc) as RFC 3339 in loc, leaving anything unparseable as is
+func inLocation(ts string, loc *time.Location) string {
+	t, err := api.ParseAPITime(ts, loc)
+	if err != nil {
+		return ts
 	}
-	return false
+	return t.In(loc).Format(time.RFC3339)
 }
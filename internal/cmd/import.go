@@ -0,0 +1,329 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/config"
+	"github.com/yjwong/lark-cli/internal/ics"
+	"github.com/yjwong/lark-cli/internal/output"
+)
+
+var (
+	importDryRun      bool
+	importNoNotify    bool
+	importExcludeSelf bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <file.ics>",
+	Short: "Import events from an iCalendar file",
+	Long: `Create events from an RFC 5545 iCalendar (.ics) file.
+
+Events are deduplicated by UID: UIDs already imported into this calendar, and
+events that were exported from it with 'lark cal export', are skipped.
+Overridden occurrences (RECURRENCE-ID) and cancelled events are skipped.
+Use - to read from stdin.
+
+Examples:
+  lark cal import team-offsite.ics --dry-run
+  lark cal import team-offsite.ics
+  cat invite.ics | lark cal import -`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		tz := config.GetTimezone()
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			loc = time.Local
+		}
+
+		var r io.Reader = os.Stdin
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				output.Fatal("FILE_ERROR", err)
+			}
+			defer f.Close()
+			r = f
+		}
+
+		icsEvents, err := ics.Decode(r, loc)
+		if err != nil {
+			output.Fatal("PARSE_ERROR", err)
+		}
+
+		client := api.NewClient()
+
+		// Get the selected calendar (primary unless --calendar is set)
+		cal, err := resolveCalendar(client)
+		if err != nil {
+			output.Fatal("CALENDAR_ERROR", err)
+		}
+
+		ledger, err := loadImportLedger()
+		if err != nil {
+			output.Fatal("FILE_ERROR", err)
+		}
+		imported := ledger[cal.CalendarID]
+		if imported == nil {
+			imported = make(map[string]string)
+			ledger[cal.CalendarID] = imported
+		}
+
+		// Keep the latest revision of each UID and decide what to skip
+		var planned []ics.Event
+		var skipped []map[string]interface{}
+		index := make(map[string]int)
+		for _, e := range icsEvents {
+			reason := ""
+			switch {
+			case e.Status == "CANCELLED":
+				reason = "cancelled"
+			case e.RecurrenceID != "":
+				reason = "overridden occurrence (RECURRENCE-ID) not supported"
+			case e.UID != "" && imported[e.UID] != "" && eventExists(client, cal.CalendarID, imported[e.UID]):
+				reason = "already imported as " + imported[e.UID]
+			case strings.HasSuffix(e.UID, icsUIDSuffix) && eventExists(client, cal.CalendarID, strings.TrimSuffix(e.UID, icsUIDSuffix)):
+				reason = "already in calendar"
+			}
+			if reason != "" {
+				skipped = append(skipped, importSkip(e, reason))
+				continue
+			}
+
+			if i, ok := index[e.UID]; ok && e.UID != "" {
+				if e.Sequence >= planned[i].Sequence {
+					skipped = append(skipped, importSkip(planned[i], "superseded by a later revision in the file"))
+					planned[i] = e
+				} else {
+					skipped = append(skipped, importSkip(e, "duplicate UID in file"))
+				}
+				continue
+			}
+			index[e.UID] = len(planned)
+			planned = append(planned, e)
+		}
+
+		if importDryRun {
+			preview := make([]map[string]interface{}, len(planned))
+			for i, e := range planned {
+				item := map[string]interface{}{
+					"uid":   e.UID,
+					"event": api.ConvertToOutputEvent(icsToEvent(e, tz)),
+				}
+				if warnings := importWarnings(e); len(warnings) > 0 {
+					item["warnings"] = warnings
+				}
+				preview[i] = item
+			}
+			output.JSON(map[string]interface{}{
+				"dry_run":   true,
+				"to_create": preview,
+				"skipped":   skipped,
+				"count":     len(preview),
+			})
+			return
+		}
+
+		var selfAttendee []api.Attendee
+		if !importExcludeSelf && len(planned) > 0 {
+			currentUser, err := client.GetCurrentUser()
+			if err != nil {
+				output.Fatalf("USER_ERROR", "Failed to get current user: %v", err)
+			}
+			selfAttendee = []api.Attendee{{Type: "user", UserID: currentUser.OpenID}}
+		}
+
+		var created []api.OutputEvent
+		var failed, warnings []map[string]interface{}
+		for _, e := range planned {
+			event := icsToEvent(e, tz)
+			req := createRequestFromEvent(&event)
+			req.Recurrence = event.Recurrence
+			if importNoNotify {
+				noNotify := false
+				req.NeedNotify = &noNotify
+			}
+
+			newEvent, err := client.CreateEvent(cal.CalendarID, req)
+			if err != nil {
+				failed = append(failed, map[string]interface{}{"uid": e.UID, "summary": e.Summary, "error": err.Error()})
+				continue
+			}
+
+			attendees := append([]api.Attendee{}, selfAttendee...)
+			var emails []string
+			for _, a := range e.Attendees {
+				if a.Email != "" && (e.Organizer == nil || !strings.EqualFold(a.Email, e.Organizer.Email)) {
+					emails = append(emails, a.Email)
+				}
+			}
+			if len(emails) > 0 {
+				parsed, err := parseAttendees(client, emails)
+				if err != nil {
+					warnings = append(warnings, map[string]interface{}{"uid": e.UID, "summary": e.Summary, "event_id": newEvent.EventID, "warning": fmt.Sprintf("attendees not added: %v", err)})
+				} else {
+					attendees = append(attendees, parsed...)
+				}
+			}
+			if len(attendees) > 0 {
+				added, err := client.CreateEventAttendees(cal.CalendarID, newEvent.EventID, attendees, !importNoNotify)
+				if err != nil {
+					failed = append(failed, map[string]interface{}{"uid": e.UID, "summary": e.Summary, "event_id": newEvent.EventID, "error": fmt.Sprintf("event created but attendees failed: %v", err)})
+				} else {
+					newEvent.Attendees = added
+				}
+			}
+
+			if e.UID != "" {
+				imported[e.UID] = newEvent.EventID
+				if err := saveImportLedger(ledger); err != nil {
+					output.Fatal("FILE_ERROR", err)
+				}
+			}
+			created = append(created, api.ConvertToOutputEvent(*newEvent))
+		}
+
+		output.JSON(map[string]interface{}{
+			"success":  len(failed) == 0,
+			"message":  fmt.Sprintf("Imported %d event(s), skipped %d, failed %d", len(created), len(skipped), len(failed)),
+			"created":  created,
+			"skipped":  skipped,
+			"failed":   failed,
+			"warnings": warnings,
+		})
+	},
+}
+
+func init() {
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Preview the events that would be created")
+	importCmd.Flags().BoolVar(&importNoNotify, "no-notify", false, "Don't send notifications to attendees")
+	importCmd.Flags().BoolVar(&importExcludeSelf, "exclude-self", false, "Don't add yourself as an attendee")
+}
+
+// icsToEvent converts an iCalendar event into a Lark event (not yet created)
+func icsToEvent(e ics.Event, defaultTZ string) api.Event {
+	out := api.Event{
+		Summary:     e.Summary,
+		Description: e.Description,
+		Recurrence:  e.RRule,
+	}
+	if out.Summary == "" {
+		out.Summary = "(No title)"
+	}
+
+	if e.AllDay {
+		out.StartTime = &api.TimeInfo{Date: e.Start.Format("2006-01-02")}
+		out.EndTime = &api.TimeInfo{Date: e.End.Format("2006-01-02")}
+	} else {
+		tz := defaultTZ
+		if name := e.Start.Location().String(); name != "UTC" && name != "Local" {
+			if _, err := time.LoadLocation(name); err == nil {
+				tz = name
+			}
+		}
+		out.StartTime = &api.TimeInfo{Timestamp: strconv.FormatInt(e.Start.Unix(), 10), Timezone: tz}
+		out.EndTime = &api.TimeInfo{Timestamp: strconv.FormatInt(e.End.Unix(), 10), Timezone: tz}
+	}
+
+	if e.Location != "" {
+		out.Location = &api.Location{Name: e.Location}
+	}
+	if e.URL != "" {
+		out.Vchat = &api.Vchat{VcType: "third_party", MeetingURL: e.URL}
+	}
+	switch e.Class {
+	case "PRIVATE", "CONFIDENTIAL":
+		out.Visibility = "private"
+	case "PUBLIC":
+		out.Visibility = "public"
+	}
+	if e.Transparent {
+		out.FreeBusyStatus = "free"
+	}
+	for _, minutes := range e.Alarms {
+		out.Reminders = append(out.Reminders, api.Reminder{Minutes: minutes})
+	}
+	for _, a := range e.Attendees {
+		att := api.Attendee{DisplayName: a.Name, IsOptional: a.Optional, Type: "third_party", ThirdPartyEmail: a.Email}
+		if att.DisplayName == "" {
+			att.DisplayName = a.Email
+		}
+		if e.Organizer != nil && a.URI == e.Organizer.URI {
+			att.IsOrganizer = true
+		}
+		out.Attendees = append(out.Attendees, att)
+	}
+	return out
+}
+
+// importWarnings lists parts of an iCalendar event that won't survive the import
+func importWarnings(e ics.Event) []string {
+	var warnings []string
+	if len(e.ExDates) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d EXDATE value(s) ignored", len(e.ExDates)))
+	}
+	for _, a := range e.Attendees {
+		if a.Email == "" {
+			warnings = append(warnings, fmt.Sprintf("attendee %s has no email address and will be skipped", a.URI))
+		}
+	}
+	if e.UID == "" {
+		warnings = append(warnings, "no UID; re-importing this file will create a duplicate")
+	}
+	return warnings
+}
+
+func importSkip(e ics.Event, reason string) map[string]interface{} {
+	return map[string]interface{}{
+		"uid":     e.UID,
+		"summary": e.Summary,
+		"reason":  reason,
+	}
+}
+
+// eventExists reports whether an event can still be fetched from the calendar
+func eventExists(client *api.Client, calendarID, eventID string) bool {
+	event, err := client.GetEvent(calendarID, eventID)
+	return err == nil && event != nil && event.Status != "cancelled"
+}
+
+// importLedgerFilePath returns the path to the record of imported UIDs
+func importLedgerFilePath() string {
+	return filepath.Join(config.GetConfigDir(), "ics_imports.json")
+}
+
+// loadImportLedger reads the calendar_id -> UID -> event_id map of past imports
+func loadImportLedger() (map[string]map[string]string, error) {
+	ledger := make(map[string]map[string]string)
+	data, err := os.ReadFile(importLedgerFilePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ledger, nil
+		}
+		return nil, fmt.Errorf("failed to read import ledger: %w", err)
+	}
+	if err := json.Unmarshal(data, &ledger); err != nil {
+		return nil, fmt.Errorf("failed to parse import ledger: %w", err)
+	}
+	return ledger, nil
+}
+
+func saveImportLedger(ledger map[string]map[string]string) error {
+	data, err := json.MarshalIndent(ledger, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal import ledger: %w", err)
+	}
+	if err := os.WriteFile(importLedgerFilePath(), data, 0600); err != nil {
+		return fmt.Errorf("failed to write import ledger: %w", err)
+	}
+	return nil
+}
@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/calendar"
 	"github.com/yjwong/lark-cli/internal/config"
 	"github.com/yjwong/lark-cli/internal/conflicts"
 	"github.com/yjwong/lark-cli/internal/output"
@@ -21,6 +24,8 @@ var (
 	listBufferMinutes   int
 	listPending         bool
 	listRsvp            bool
+	listAllCalendars    bool
+	listOffline         bool
 )
 
 var listCmd = &cobra.Command{
@@ -36,14 +41,38 @@ Examples:
   lark cal list --week                           # This week
   lark cal list --from 2026-01-02 --to 2026-01-05
   lark cal list --from 2026-01-05 --to 2026-01-10
+  lark cal list --from tomorrow --to "end of week"
   lark cal list --rsvp                           # Include your RSVP status
   lark cal list --attendees                      # Include all attendees info
-  lark cal list --pending                        # Events awaiting your RSVP`,
+  lark cal list --pending                        # Events awaiting your RSVP
+  lark cal list --calendar "Team Calendar"       # A shared calendar
+  lark cal list --all-calendars --week           # Merge all subscribed calendars
+  lark cal list --week --offline                 # From the local cache (see 'lark cal sync')`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if listOffline && (listAttendees || listRsvp || listPending) {
+			output.Fatalf("VALIDATION_ERROR", "--attendees, --rsvp and --pending cannot be used with --offline")
+		}
+
 		client := api.NewClient()
 
-		// Get primary calendar
-		cal, err := client.GetPrimaryCalendar()
+		// Calendars to list: the selected one, or every calendar with --all-calendars
+		var calendars []api.Calendar
+		var err error
+		switch {
+		case listOffline:
+			// Read from the cache's calendars below
+		case listAllCalendars:
+			if calCalendar != "" {
+				output.Fatalf("VALIDATION_ERROR", "--calendar and --all-calendars cannot be used together")
+			}
+			calendars, err = listReadableCalendars(client)
+		default:
+			var cal *api.Calendar
+			cal, err = resolveCalendar(client)
+			if cal != nil {
+				calendars = []api.Calendar{*cal}
+			}
+		}
 		if err != nil {
 			output.Fatal("CALENDAR_ERROR", err)
 		}
@@ -56,45 +85,36 @@ Examples:
 		}
 		now := time.Now().In(loc)
 
-		var startTime, endTime time.Time
+		startTime, endTime, err := eventRange(listFrom, listTo, listWeek, now, loc)
+		if err != nil {
+			output.Fatal("PARSE_ERROR", err)
+		}
 
-		if listWeek {
-			startTime = timex.StartOfWeek(now)
-			endTime = timex.EndOfWeek(now)
-		} else if listFrom != "" || listTo != "" {
-			if listFrom != "" {
-				startTime, err = timex.Parse(listFrom, loc)
-				if err != nil {
-					output.Fatalf("PARSE_ERROR", "Failed to parse --from: %v", err)
-				}
-				startTime = timex.StartOfDay(startTime)
-			} else {
-				startTime = timex.StartOfDay(now)
+		// Fetch events, remembering which calendar each came from
+		var events []api.Event
+		var eventCalendars []api.Calendar
+		var cacheUsed *api.OutputCache
+		if listOffline {
+			cache, err := calendar.OpenCache()
+			if err != nil {
+				output.Fatal("CACHE_ERROR", err)
 			}
+			defer cache.Close()
 
-			if listTo != "" {
-				endTime, err = timex.Parse(listTo, loc)
-				if err != nil {
-					output.Fatalf("PARSE_ERROR", "Failed to parse --to: %v", err)
-				}
-				endTime = timex.EndOfDay(endTime)
-			} else {
-				endTime = timex.EndOfDay(now.AddDate(0, 1, 0)) // 1 month default
+			states, err := offlineCalendars(cache, listAllCalendars)
+			if err != nil {
+				output.Fatal("CACHE_ERROR", err)
 			}
+			events, eventCalendars, err = cachedCalendarEvents(cache, states, startTime, endTime, loc)
+			if err != nil {
+				output.Fatal("CACHE_ERROR", err)
+			}
+			cacheUsed = cacheInfo(states, loc)
 		} else {
-			// Default: today
-			startTime = timex.StartOfDay(now)
-			endTime = timex.EndOfDay(now)
-		}
-
-		// Fetch events
-		events, err := client.ListEvents(api.ListEventsOptions{
-			CalendarID: cal.CalendarID,
-			StartTime:  startTime,
-			EndTime:    endTime,
-		})
-		if err != nil {
-			output.Fatal("API_ERROR", err)
+			events, eventCalendars, err = fetchCalendarEvents(client, calendars, startTime, endTime, loc)
+			if err != nil {
+				output.Fatal("API_ERROR", err)
+			}
 		}
 
 		// Get current user's open_id if we need to filter by pending RSVP or show RSVP status
@@ -112,7 +132,7 @@ Examples:
 			for i := range events {
 				// Always use the instance EventID to get instance-specific RSVP status
 				// (RecurringEventID returns series-level status which may be stale)
-				attendees, err := client.ListEventAttendees(cal.CalendarID, events[i].EventID)
+				attendees, err := client.ListEventAttendees(eventCalendars[i].CalendarID, events[i].EventID)
 				if err == nil {
 					events[i].Attendees = attendees
 				}
@@ -123,7 +143,8 @@ Examples:
 		// Filter to events where current user's RSVP is needs_action
 		if listPending {
 			var pendingEvents []api.Event
-			for _, event := range events {
+			var pendingCalendars []api.Calendar
+			for i, event := range events {
 				isPending := false
 
 				for _, att := range event.Attendees {
@@ -135,7 +156,7 @@ Examples:
 
 					// Check chat group attendees - expand to find user's individual RSVP
 					if att.Type == "chat" && att.AttendeeID != "" {
-						members, err := client.ListChatMemberAttendees(cal.CalendarID, event.EventID, att.AttendeeID)
+						members, err := client.ListChatMemberAttendees(eventCalendars[i].CalendarID, event.EventID, att.AttendeeID)
 						if err == nil {
 							for _, member := range members {
 								if member.OpenID == currentUserOpenID && member.RsvpStatus == "needs_action" {
@@ -152,18 +173,26 @@ Examples:
 
 				if isPending {
 					pendingEvents = append(pendingEvents, event)
+					pendingCalendars = append(pendingCalendars, eventCalendars[i])
 				}
 			}
 			events = pendingEvents
+			eventCalendars = pendingCalendars
 		}
 
 		// Convert to output format
 		outputEvents := api.ConvertToOutputEvents(events)
+		if listAllCalendars {
+			for i := range outputEvents {
+				outputEvents[i].CalendarID = eventCalendars[i].CalendarID
+				outputEvents[i].CalendarName = eventCalendars[i].DisplayName()
+			}
+		}
 
 		// Populate user's RSVP status if requested (and we have attendees data)
 		if listRsvp && currentUserOpenID != "" {
 			for i := range outputEvents {
-				outputEvents[i].RsvpStatus = api.ExtractUserRsvpStatus(events[i], currentUserOpenID, cal.CalendarID, client)
+				outputEvents[i].RsvpStatus = api.ExtractUserRsvpStatus(events[i], currentUserOpenID, eventCalendars[i].CalendarID, client)
 				// If --rsvp only (not --attendees), clear the attendees list to keep output clean
 				if !listAttendees {
 					outputEvents[i].Attendees = nil
@@ -190,6 +219,7 @@ Examples:
 			Count:        len(outputEvents),
 			Conflicts:    conflictResult.Conflicts,
 			HasConflicts: conflictResult.HasConflicts,
+			Cache:        cacheUsed,
 		})
 	},
 }
@@ -204,4 +234,91 @@ func init() {
 	listCmd.Flags().BoolVar(&listDetectConflicts, "detect-conflicts", false, "Detect overlapping events")
 	listCmd.Flags().IntVar(&listBufferMinutes, "buffer-minutes", 0, "Minimum buffer between meetings (requires --detect-conflicts)")
 	listCmd.Flags().BoolVar(&listPending, "pending", false, "Only show events awaiting your RSVP")
+	listCmd.Flags().BoolVar(&listAllCalendars, "all-calendars", false, "Merge events from all your calendars")
+	listCmd.Flags().BoolVar(&listOffline, "offline", false, "Read events from the local cache instead of the API")
+}
+
+// eventRange resolves the --from/--to/--week flags of list-style commands.
+// Without any of them the range is today; --from alone runs for a month.
+func eventRange(from, to string, week bool, now time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	if week {
+		return timex.StartOfWeek(now), timex.EndOfWeek(now), nil
+	}
+	if from == "" && to == "" {
+		return timex.StartOfDay(now), timex.EndOfDay(now), nil
+	}
+
+	startTime := timex.StartOfDay(now)
+	if from != "" {
+		t, err := timex.Parse(from, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("failed to parse --from: %v", err)
+		}
+		startTime = timex.StartOfDay(t)
+	}
+
+	endTime := timex.EndOfDay(now.AddDate(0, 1, 0)) // 1 month default
+	if to != "" {
+		t, err := timex.Parse(to, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("failed to parse --to: %v", err)
+		}
+		endTime = timex.EndOfDay(t)
+	}
+	return startTime, endTime, nil
+}
+
+// fetchCalendarEvents lists events across calendars, returning each event with
+// the calendar it came from. With several calendars, unreadable calendars are
+// skipped, duplicates are dropped and events are sorted by start time.
+func fetchCalendarEvents(client *api.Client, calendars []api.Calendar, startTime, endTime time.Time, loc *time.Location) ([]api.Event, []api.Calendar, error) {
+	var events []api.Event
+	var eventCalendars []api.Calendar
+	seen := make(map[string]bool)
+	for _, c := range calendars {
+		calEvents, err := client.ListEvents(api.ListEventsOptions{
+			CalendarID: c.CalendarID,
+			StartTime:  startTime,
+			EndTime:    endTime,
+		})
+		if err != nil {
+			if len(calendars) > 1 {
+				continue // Skip calendars we can't read rather than failing the whole list
+			}
+			return nil, nil, err
+		}
+		for _, e := range calEvents {
+			// An event you attend can show up on several calendars; keep the first
+			if seen[e.EventID] {
+				continue
+			}
+			seen[e.EventID] = true
+			events = append(events, e)
+			eventCalendars = append(eventCalendars, c)
+		}
+	}
+	if len(calendars) > 1 {
+		sortEventsByStart(events, eventCalendars, loc)
+	}
+	return events, eventCalendars, nil
+}
+
+// sortEventsByStart orders events (and their parallel calendars) by start time
+func sortEventsByStart(events []api.Event, calendars []api.Calendar, loc *time.Location) {
+	idx := make([]int, len(events))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		return eventTime(events[idx[a]].StartTime, loc).Before(eventTime(events[idx[b]].StartTime, loc))
+	})
+
+	sortedEvents := make([]api.Event, len(events))
+	sortedCalendars := make([]api.Calendar, len(calendars))
+	for i, j := range idx {
+		sortedEvents[i] = events[j]
+		sortedCalendars[i] = calendars[j]
+	}
+	copy(events, sortedEvents)
+	copy(calendars, sortedCalendars)
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -13,7 +14,9 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/yjwong/lark-cli/internal/api"
 	"github.com/yjwong/lark-cli/internal/config"
+	"github.com/yjwong/lark-cli/internal/directory"
 	"github.com/yjwong/lark-cli/internal/output"
+	timex "github.com/yjwong/lark-cli/internal/time"
 )
 
 var msgCmd = &cobra.Command{
@@ -49,6 +52,7 @@ Examples:
   lark msg history --chat-id oc_xxxxx --limit 50
   lark msg history --chat-id oc_xxxxx --start 1704067200 --end 1704153600
   lark msg history --chat-id oc_xxxxx --sort desc
+  lark msg history --chat-id "chat:Team Standup"
   lark msg history --chat-id thread_xxxxx --type thread`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if msgHistoryChatID == "" {
@@ -57,6 +61,19 @@ Examples:
 
 		client := api.NewClient()
 
+		// Chats can be given by name or alias as well as ID
+		chatID := msgHistoryChatID
+		if msgHistoryType == "chat" {
+			chat, err := resolveRef(client, msgHistoryChatID)
+			if err != nil {
+				output.Fatal("VALIDATION_ERROR", err)
+			}
+			if chat.Kind != directory.KindChat {
+				output.Fatalf("VALIDATION_ERROR", "%s is a %s, not a chat", msgHistoryChatID, chat.Kind)
+			}
+			chatID = chat.ID
+		}
+
 		// Build options
 		opts := &api.ListMessagesOptions{}
 
@@ -91,7 +108,7 @@ Examples:
 			opts.PageSize = pageSize
 			opts.PageToken = pageToken
 
-			messages, more, nextToken, err := client.ListMessages(msgHistoryType, msgHistoryChatID, opts)
+			messages, more, nextToken, err := client.ListMessages(msgHistoryType, chatID, opts)
 			if err != nil {
 				output.Fatal("API_ERROR", err)
 			}
@@ -123,7 +140,7 @@ Examples:
 		result := api.OutputMessageList{
 			Messages: outputMessages,
 			Count:    len(outputMessages),
-			ChatID:   msgHistoryChatID,
+			ChatID:   chatID,
 		}
 
 		output.JSON(result)
@@ -146,7 +163,15 @@ func parseTimeArg(s string) string {
 			// Try date only
 			t, err = time.Parse("2006-01-02", s)
 			if err != nil {
-				output.Fatalf("PARSE_ERROR", "invalid time format: %s (use Unix timestamp or ISO 8601)", s)
+				// Fall back to relative expressions like "yesterday" or "-2h"
+				loc, lerr := time.LoadLocation(config.GetTimezone())
+				if lerr != nil {
+					loc = time.Local
+				}
+				t, err = timex.Parse(s, loc)
+				if err != nil {
+					output.Fatalf("PARSE_ERROR", "invalid time format: %s (use Unix timestamp, ISO 8601, or an expression like yesterday or -2h)", s)
+				}
 			}
 		}
 	}
@@ -303,6 +328,14 @@ Examples:
 	# Send text to user
 	lark msg send --to ou_xxx --text "Hello!"
 
+	# Recipients can be emails, names, aliases or chats by name
+	lark msg send --to alice@example.com --text "Hello!"
+	lark msg send --to "chat:Team Standup" --text "Standup in 5"
+
+	# Raw user_ids are sent as user_ids; --to-type forces the type
+	lark msg send --to 1a2b3c4d --text "Hello!"
+	lark msg send --to abcdef --to-type user_id --text "Hello!"
+
 	# Send to group chat with line breaks
 	lark msg send --to oc_xxx --text "Line 1\nLine 2\nLine 3"
 
@@ -349,13 +382,17 @@ Examples:
 			output.Fatalf("VALIDATION_ERROR", "--parent-id is required when --root-id is set")
 		}
 
-		// Auto-detect receive_id_type if not specified
-		receiveIDType := msgSendToType
+		client := api.NewClient()
+
+		// Resolve the recipient unless its ID type is given
+		receiveID, receiveIDType := msgSendTo, msgSendToType
 		if receiveIDType == "" {
-			receiveIDType = detectIDType(msgSendTo)
+			var err error
+			receiveID, receiveIDType, err = resolveRecipient(client, msgSendTo)
+			if err != nil {
+				output.Fatal("VALIDATION_ERROR", err)
+			}
 		}
-
-		client := api.NewClient()
 		imageKeys := make([]string, 0, len(msgSendImages))
 		for _, imagePath := range msgSendImages {
 			imageKey, err := client.UploadMessageImage(imagePath)
@@ -389,7 +426,7 @@ Examples:
 		if msgSendParentID != "" {
 			resp, err = client.ReplyMessage(msgSendParentID, msgType, content, msgSendRootID, true)
 		} else {
-			resp, err = client.SendMessage(receiveIDType, msgSendTo, msgType, content)
+			resp, err = client.SendMessage(receiveIDType, receiveID, msgType, content)
 		}
 		if err != nil {
 			output.Fatal("API_ERROR", err)
@@ -772,23 +809,36 @@ Examples:
 	},
 }
 
-// detectIDType infers the receive_id_type from the ID format
-func detectIDType(id string) string {
-	if strings.HasPrefix(id, "ou_") {
-		return "open_id"
-	}
-	if strings.HasPrefix(id, "oc_") {
-		return "chat_id"
+// rawUserIDRe matches a bare token that may be a user_id rather than a name
+var rawUserIDRe = regexp.MustCompile(`^[0-9a-zA-Z]+$`)
+
+// resolveRecipient turns --to into a receive ID and its type. Tokens with a
+// digit (user_ids such as 1a2b3c4d) are user_ids unless they are aliases;
+// anything else goes through the identity resolver, so names, aliases and
+// chat:<name> work too. A bare token no user is confidently named falls back
+// to a user_id, never to a fuzzy match.
+func resolveRecipient(client *api.Client, to string) (string, string, error) {
+	if rawUserIDRe.MatchString(to) && strings.ContainsAny(to, "0123456789") && !isAlias(to) {
+		return to, "user_id", nil
 	}
-	if strings.Contains(id, "@") {
-		return "email"
+	id, err := resolveRef(client, to)
+	if err != nil {
+		var ambiguous *directory.AmbiguousError
+		if rawUserIDRe.MatchString(to) && !errors.As(err, &ambiguous) {
+			return to, "user_id", nil
+		}
+		return "", "", err
 	}
-	// Assume user_id if all numeric
-	if _, err := strconv.Atoi(id); err == nil {
-		return "user_id"
+	switch id.Kind {
+	case directory.KindUser:
+		return id.ID, "open_id", nil
+	case directory.KindChat:
+		return id.ID, "chat_id", nil
+	case directory.KindEmail:
+		return id.Email, "email", nil
+	default:
+		return "", "", fmt.Errorf("can't send a message to a %s (%s)", id.Kind, to)
 	}
-	// Default to open_id
-	return "open_id"
 }
 
 // unescapeString processes escape sequences like \n, \t, \r, etc.
@@ -1084,10 +1134,10 @@ Examples:
 
 func init() {
 	// msg history flags
-	msgHistoryCmd.Flags().StringVar(&msgHistoryChatID, "chat-id", "", "Chat ID or thread ID (required)")
+	msgHistoryCmd.Flags().StringVar(&msgHistoryChatID, "chat-id", "", "Chat ID, chat:<name> or alias, or thread ID (required)")
 	msgHistoryCmd.Flags().StringVar(&msgHistoryType, "type", "chat", "Container type: 'chat' or 'thread'")
-	msgHistoryCmd.Flags().StringVar(&msgHistoryStartTime, "start", "", "Start time (Unix timestamp or ISO 8601)")
-	msgHistoryCmd.Flags().StringVar(&msgHistoryEndTime, "end", "", "End time (Unix timestamp or ISO 8601)")
+	msgHistoryCmd.Flags().StringVar(&msgHistoryStartTime, "start", "", "Start time (Unix timestamp, ISO 8601, or expression like yesterday)")
+	msgHistoryCmd.Flags().StringVar(&msgHistoryEndTime, "end", "", "End time (Unix timestamp, ISO 8601, or expression like -2h)")
 	msgHistoryCmd.Flags().StringVar(&msgHistorySort, "sort", "", "Sort order: 'asc' or 'desc' (default: asc)")
 	msgHistoryCmd.Flags().IntVar(&msgHistoryLimit, "limit", 0, "Maximum number of messages to retrieve (0 = no limit)")
 
@@ -1098,8 +1148,8 @@ func init() {
 	msgResourceCmd.Flags().StringVar(&msgResourceOutput, "output", "", "Output file path (required)")
 
 	// msg send flags
-	msgSendCmd.Flags().StringVar(&msgSendTo, "to", "", "Recipient ID (user ID, open_id, email, or chat_id) (required)")
-	msgSendCmd.Flags().StringVar(&msgSendToType, "to-type", "", "Recipient ID type: open_id, user_id, email, chat_id (auto-detected if not specified)")
+	msgSendCmd.Flags().StringVar(&msgSendTo, "to", "", "Recipient: open_id, user ID, email, name, chat_id, chat:<name> or alias (required)")
+	msgSendCmd.Flags().StringVar(&msgSendToType, "to-type", "", "Recipient ID type: open_id, user_id, email, chat_id (resolved from --to if not specified)")
 	msgSendCmd.Flags().StringVar(&msgSendText, "text", "", "Message text (markdown-lite). Use {{image}} to place images")
 	msgSendCmd.Flags().StringSliceVar(&msgSendImages, "image", nil, "Image file path (repeatable)")
 	msgSendCmd.Flags().StringVar(&msgSendMsgType, "msg-type", "post", "Message type: post (default) or text")
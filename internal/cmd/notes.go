@@ -0,0 +1,353 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/config"
+	"github.com/yjwong/lark-cli/internal/output"
+)
+
+// notesBatchSize is the most blocks created per API call
+const notesBatchSize = 50
+
+var (
+	notesTemplate string
+	notesFolder   string
+	notesTitle    string
+	notesNoLink   bool
+	notesNotify   bool
+)
+
+var notesCmd = &cobra.Command{
+	Use:   "notes <event-id>",
+	Short: "Create a meeting notes doc for an event",
+	Long: `Create a meeting notes document for an event and link it from the event.
+
+The doc lists the time, location and attendees (as @mentions, with group
+chats expanded), an agenda taken from the event description, and an empty
+notes section. The event description is then updated with a link to the
+doc, without notifying attendees unless --notify is set.
+
+With --template, the top-level blocks of another doc are copied instead.
+Text, headings, lists, quotes, code, todos and dividers are copied; other
+blocks and nested blocks are skipped and counted. These variables are
+substituted in the template's text and title:
+
+  {{title}}        event summary
+  {{date}}         e.g. Mon 5 Jan 2026
+  {{time}}         e.g. 14:00-15:00 SGT, or "All day"
+  {{location}}     location name
+  {{meeting_url}}  video meeting link
+  {{organizer}}    organizer's name
+  {{description}}  event description ({{agenda}} is the same)
+  {{attendees}}    attendees as @mentions
+
+Examples:
+  lark cal notes <event-id>
+  lark cal notes <event-id> --template doxcnXXXXXXXX
+  lark cal notes <event-id> --folder fldcnXXXXXXXX --title "Weekly sync notes"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		eventID := args[0]
+
+		tz := config.GetTimezone()
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			loc = time.Local
+		}
+
+		client := api.NewClient()
+
+		// Get the selected calendar (primary unless --calendar is set)
+		cal, err := resolveCalendar(client)
+		if err != nil {
+			output.Fatal("CALENDAR_ERROR", err)
+		}
+
+		event, err := client.GetEvent(cal.CalendarID, eventID)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+		attendees, err := client.ListEventAttendees(cal.CalendarID, eventID)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+		people, _, err := expandAttendees(client, cal.CalendarID, eventID, attendees)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+
+		vars := notesVariables(event, people, loc)
+		mentions := attendeeMentions(people)
+
+		title := notesTitle
+		var blocks []api.DocumentBlock
+		skipped := 0
+		if notesTemplate != "" {
+			tmpl, err := client.GetDocument(notesTemplate)
+			if err != nil {
+				output.Fatalf("API_ERROR", "Failed to read template: %v", err)
+			}
+			tmplBlocks, err := client.GetDocumentBlocks(notesTemplate)
+			if err != nil {
+				output.Fatalf("API_ERROR", "Failed to read template: %v", err)
+			}
+			blocks, skipped = templateBlocks(tmplBlocks, vars, mentions)
+			if title == "" && strings.Contains(tmpl.Title, "{{") {
+				title = substituteVariables(tmpl.Title, vars)
+			}
+		} else {
+			blocks = defaultNotesBlocks(event, vars, mentions)
+		}
+		if title == "" {
+			title = fmt.Sprintf("%s - Notes (%s)", vars["title"], vars["date"])
+		}
+
+		doc, err := client.CreateDocument(title, notesFolder)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+		for start := 0; start < len(blocks); start += notesBatchSize {
+			end := start + notesBatchSize
+			if end > len(blocks) {
+				end = len(blocks)
+			}
+			if _, _, err := client.CreateDocumentBlocks(doc.DocumentID, doc.DocumentID, blocks[start:end], -1); err != nil {
+				output.Fatalf("API_ERROR", "Created document %s but failed to fill it in: %v", doc.DocumentID, err)
+			}
+		}
+
+		result := map[string]interface{}{
+			"success":     true,
+			"document_id": doc.DocumentID,
+			"title":       title,
+			"event_id":    eventID,
+			"blocks":      len(blocks),
+		}
+		if notesTemplate != "" {
+			result["template"] = notesTemplate
+			result["skipped_blocks"] = skipped
+		}
+
+		docURL, err := client.GetDocumentURL(doc.DocumentID)
+		if err != nil {
+			result["url_error"] = err.Error()
+		} else {
+			result["url"] = docURL
+		}
+
+		if !notesNoLink && docURL != "" {
+			description := strings.TrimRight(event.Description, " \n")
+			if description != "" {
+				description += "\n\n"
+			}
+			description += "Meeting notes: " + docURL
+			notify := notesNotify
+			if _, err := client.UpdateEvent(cal.CalendarID, eventID, &api.UpdateEventRequest{
+				Description: description,
+				NeedNotify:  &notify,
+			}); err != nil {
+				output.Fatalf("API_ERROR", "Created document %s but failed to link it from the event: %v", doc.DocumentID, err)
+			}
+			result["event_linked"] = true
+		}
+
+		output.JSON(result)
+	},
+}
+
+func init() {
+	notesCmd.Flags().StringVar(&notesTemplate, "template", "", "Document ID of a template to copy")
+	notesCmd.Flags().StringVar(&notesFolder, "folder", "", "Folder token to create the doc in (default: root)")
+	notesCmd.Flags().StringVar(&notesTitle, "title", "", "Document title (default: \"<summary> - Notes (<date>)\")")
+	notesCmd.Flags().BoolVar(&notesNoLink, "no-link", false, "Don't add the doc link to the event description")
+	notesCmd.Flags().BoolVar(&notesNotify, "notify", false, "Notify attendees when the event description is updated")
+}
+
+// notesVariables returns the template variables for an event
+func notesVariables(event *api.Event, people []api.OutputRsvpPerson, loc *time.Location) map[string]string {
+	vars := map[string]string{
+		"title":       event.Summary,
+		"description": event.Description,
+		"agenda":      event.Description,
+	}
+	if event.StartTime != nil {
+		start := eventTime(event.StartTime, loc)
+		vars["date"] = start.Format("Mon 2 Jan 2006")
+		if event.StartTime.Date != "" {
+			vars["time"] = "All day"
+		} else {
+			vars["time"] = start.Format("15:04") + "-" + eventTime(event.EndTime, loc).Format("15:04 MST")
+		}
+	}
+	if event.Location != nil {
+		vars["location"] = event.Location.Name
+	}
+	if event.Vchat != nil {
+		vars["meeting_url"] = event.Vchat.MeetingURL
+	}
+	var names []string
+	for _, p := range people {
+		if p.IsOrganizer && vars["organizer"] == "" {
+			vars["organizer"] = p.Name
+		}
+		names = append(names, p.Name)
+	}
+	vars["attendees"] = strings.Join(names, ", ")
+	return vars
+}
+
+// substituteVariables replaces {{name}} placeholders in s. {{attendees}}
+// becomes a plain list of names here; in doc text it becomes @mentions.
+func substituteVariables(s string, vars map[string]string) string {
+	var pairs []string
+	for name, value := range vars {
+		pairs = append(pairs, "{{"+name+"}}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(s)
+}
+
+// attendeeMentions renders attendees as @mentions separated by commas.
+// External attendees, who can't be mentioned, are written by name.
+func attendeeMentions(people []api.OutputRsvpPerson) []api.TextElement {
+	var elems []api.TextElement
+	for i, p := range people {
+		if i > 0 {
+			elems = append(elems, api.TextElement{TextRun: &api.TextRun{Content: ", "}})
+		}
+		if p.UserID != "" && !p.IsExternal {
+			elems = append(elems, api.TextElement{MentionUser: &api.MentionUser{UserID: p.UserID}})
+		} else {
+			elems = append(elems, api.TextElement{TextRun: &api.TextRun{Content: p.Name}})
+		}
+	}
+	return elems
+}
+
+// labelled returns a text block starting with a bold label
+func labelled(label string, value []api.TextElement) api.DocumentBlock {
+	elems := []api.TextElement{{TextRun: &api.TextRun{
+		Content:          label + ": ",
+		TextElementStyle: &api.TextElementStyle{Bold: true},
+	}}}
+	return api.DocumentBlock{
+		BlockType: 2, // text
+		Text:      &api.TextBlock{Elements: append(elems, value...)},
+	}
+}
+
+// defaultNotesBlocks lays out the notes doc when no template is given
+func defaultNotesBlocks(event *api.Event, vars map[string]string, mentions []api.TextElement) []api.DocumentBlock {
+	text := func(s string) []api.TextElement {
+		return []api.TextElement{{TextRun: &api.TextRun{Content: s}}}
+	}
+
+	blocks := []api.DocumentBlock{labelled("Time", text(vars["date"]+", "+vars["time"]))}
+	if vars["location"] != "" {
+		blocks = append(blocks, labelled("Location", text(vars["location"])))
+	}
+	if vars["meeting_url"] != "" {
+		blocks = append(blocks, labelled("Meeting link", text(vars["meeting_url"])))
+	}
+	if len(mentions) > 0 {
+		blocks = append(blocks, labelled("Attendees", mentions))
+	}
+
+	blocks = append(blocks, api.DocumentBlock{BlockType: blockTypeForHeadingLevel(2), Heading2: makeTextBlock("Agenda")})
+	agenda := 0
+	for _, line := range strings.Split(event.Description, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*•"))
+		if line == "" {
+			continue
+		}
+		blocks = append(blocks, api.DocumentBlock{BlockType: 12, Bullet: makeTextBlock(line)})
+		agenda++
+	}
+	if agenda == 0 {
+		blocks = append(blocks, api.DocumentBlock{BlockType: 12, Bullet: &api.TextBlock{}})
+	}
+
+	blocks = append(blocks,
+		api.DocumentBlock{BlockType: blockTypeForHeadingLevel(2), Heading2: makeTextBlock("Notes")},
+		api.DocumentBlock{BlockType: 2, Text: &api.TextBlock{}},
+	)
+	return blocks
+}
+
+// templateBlocks copies the top-level blocks of a template document with
+// variables substituted. It returns the blocks and how many were skipped.
+func templateBlocks(tmpl []api.DocumentBlock, vars map[string]string, mentions []api.TextElement) ([]api.DocumentBlock, int) {
+	if len(tmpl) == 0 {
+		return nil, 0
+	}
+	byID := make(map[string]api.DocumentBlock, len(tmpl))
+	for _, b := range tmpl {
+		byID[b.BlockID] = b
+	}
+
+	var blocks []api.DocumentBlock
+	skipped := 0
+	for _, id := range tmpl[0].Children { // The first block is the page
+		b, ok := byID[id]
+		if !ok || !copyableBlock(b.BlockType) {
+			skipped++
+			continue
+		}
+		skipped += len(b.Children)
+		b.BlockID, b.ParentID, b.Children = "", "", nil
+		for _, field := range blockTexts(&b) {
+			if *field != nil {
+				tb := **field
+				tb.Elements = substituteElements(tb.Elements, vars, mentions)
+				*field = &tb
+			}
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks, skipped
+}
+
+// copyableBlock reports whether a block type can be recreated from its
+// fields: text, headings, lists, code, quote, todo and divider
+func copyableBlock(blockType int) bool {
+	return (blockType >= 2 && blockType <= 15) || blockType == 17 || blockType == 22
+}
+
+// blockTexts returns the text fields of a block
+func blockTexts(b *api.DocumentBlock) []**api.TextBlock {
+	return []**api.TextBlock{
+		&b.Text, &b.Heading1, &b.Heading2, &b.Heading3, &b.Heading4, &b.Heading5,
+		&b.Heading6, &b.Heading7, &b.Heading8, &b.Heading9, &b.Bullet, &b.Ordered,
+		&b.Code, &b.Quote, &b.TodoBlock,
+	}
+}
+
+// substituteElements replaces variables in text runs, expanding
+// {{attendees}} into mentions
+func substituteElements(elems []api.TextElement, vars map[string]string, mentions []api.TextElement) []api.TextElement {
+	var out []api.TextElement
+	for _, e := range elems {
+		if e.TextRun == nil {
+			out = append(out, e)
+			continue
+		}
+		parts := strings.Split(e.TextRun.Content, "{{attendees}}")
+		for i, part := range parts {
+			if i > 0 {
+				out = append(out, mentions...)
+			}
+			if part == "" {
+				continue
+			}
+			out = append(out, api.TextElement{TextRun: &api.TextRun{
+				Content:          substituteVariables(part, vars),
+				TextElementStyle: e.TextRun.TextElementStyle,
+			}})
+		}
+	}
+	return out
+}
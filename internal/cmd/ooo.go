@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/config"
+	"github.com/yjwong/lark-cli/internal/conflicts"
+	"github.com/yjwong/lark-cli/internal/output"
+	timex "github.com/yjwong/lark-cli/internal/time"
+)
+
+// oooEventID identifies the out-of-office block during conflict detection
+const oooEventID = "ooo"
+
+var (
+	oooFrom    string
+	oooTo      string
+	oooSummary string
+	oooDecline bool
+	oooMessage string
+	oooDryRun  bool
+)
+
+var oooCmd = &cobra.Command{
+	Use:   "ooo",
+	Short: "Block out-of-office days",
+	Long: `Create an all-day out-of-office event and optionally decline conflicting invitations.
+
+Conflicting events are found with the conflict detector, so events you have
+already declined and events shown as free are left alone. Events you organize
+are reported but never declined; reschedule or cancel those yourself.
+
+With --message, the organizer of each declined event is also sent the
+message by the bot (the app needs permission to send messages).
+
+Examples:
+  lark cal ooo --from 2026-02-02 --to 2026-02-06 --dry-run
+  lark cal ooo --from 2026-02-02 --to 2026-02-06 --decline
+  lark cal ooo --from "next monday" --to "next friday" --decline --message "On leave, back on the 9th"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if oooFrom == "" || oooTo == "" {
+			output.Fatalf("VALIDATION_ERROR", "--from and --to are required")
+		}
+		if oooMessage != "" && !oooDecline {
+			output.Fatalf("VALIDATION_ERROR", "--message requires --decline")
+		}
+
+		// Parse timezone
+		tz := config.GetTimezone()
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			loc = time.Local
+		}
+
+		firstDay, err := timex.Parse(oooFrom, loc)
+		if err != nil {
+			output.Fatalf("PARSE_ERROR", "Failed to parse --from: %v", err)
+		}
+		lastDay, err := timex.Parse(oooTo, loc)
+		if err != nil {
+			output.Fatalf("PARSE_ERROR", "Failed to parse --to: %v", err)
+		}
+		startTime := timex.StartOfDay(firstDay)
+		endTime := timex.StartOfDay(lastDay).AddDate(0, 0, 1) // All-day end dates are exclusive
+		if !endTime.After(startTime) {
+			output.Fatalf("VALIDATION_ERROR", "--to must not be before --from")
+		}
+
+		client := api.NewClient()
+
+		// Get the selected calendar (primary unless --calendar is set)
+		cal, err := resolveCalendar(client)
+		if err != nil {
+			output.Fatal("CALENDAR_ERROR", err)
+		}
+
+		currentUser, err := client.GetCurrentUser()
+		if err != nil {
+			output.Fatal("USER_ERROR", err)
+		}
+
+		events, err := client.ListEvents(api.ListEventsOptions{
+			CalendarID: cal.CalendarID,
+			StartTime:  startTime,
+			EndTime:    endTime,
+		})
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+		for i := range events {
+			attendees, err := client.ListEventAttendees(cal.CalendarID, events[i].EventID)
+			if err == nil {
+				events[i].Attendees = attendees
+			}
+		}
+
+		outputEvents := api.ConvertToOutputEvents(events)
+		for i := range outputEvents {
+			outputEvents[i].RsvpStatus = api.ExtractUserRsvpStatus(events[i], currentUser.OpenID, cal.CalendarID, client)
+		}
+		slots, err := conflicts.ParseEventTimes(outputEvents, loc)
+		if err != nil {
+			output.Fatal("CONFLICT_DETECTION_ERROR", err)
+		}
+		slots = append(slots, conflicts.EventTimeSlot{ID: oooEventID, Start: startTime, End: endTime, AllDay: true})
+		result := conflicts.Detect(slots, conflicts.Options{})
+
+		eventsByID := make(map[string]int, len(events))
+		for i, e := range events {
+			eventsByID[e.EventID] = i
+		}
+
+		block := api.OutputBlock{
+			Summary: oooSummary,
+			Start:   startTime.Format("2006-01-02"),
+			End:     endTime.Format("2006-01-02"),
+			AllDay:  true,
+		}
+		if !oooDryRun {
+			event, err := client.CreateEvent(cal.CalendarID, &api.CreateEventRequest{
+				Summary:        oooSummary,
+				StartTime:      &api.TimeInfo{Date: block.Start},
+				EndTime:        &api.TimeInfo{Date: block.End},
+				FreeBusyStatus: "busy",
+			})
+			if err != nil {
+				output.Fatal("API_ERROR", err)
+			}
+			block.EventID = event.EventID
+		}
+
+		affected := []map[string]interface{}{}
+		declined := 0
+		for _, eventID := range result.ConflictMap[oooEventID] {
+			i, ok := eventsByID[eventID]
+			if !ok {
+				continue
+			}
+			e, out := events[i], outputEvents[i]
+			entry := map[string]interface{}{
+				"event_id":    e.EventID,
+				"summary":     e.Summary,
+				"start":       out.Start,
+				"end":         out.End,
+				"rsvp_status": out.RsvpStatus,
+			}
+			affected = append(affected, entry)
+
+			organizerID := eventOrganizerID(e)
+			switch {
+			case organizerID == currentUser.OpenID || e.OrganizerCalendarID == cal.CalendarID:
+				entry["action"] = "skip"
+				entry["reason"] = "you organize this event; reschedule or cancel it"
+				continue
+			case out.RsvpStatus == "":
+				entry["action"] = "skip"
+				entry["reason"] = "not an invitation"
+				continue
+			case !oooDecline:
+				entry["action"] = "none"
+				continue
+			}
+
+			entry["action"] = "decline"
+			if oooDryRun {
+				continue
+			}
+			if err := client.ReplyToEvent(cal.CalendarID, e.EventID, "decline"); err != nil {
+				entry["error"] = err.Error()
+				continue
+			}
+			entry["declined"] = true
+			declined++
+
+			if oooMessage != "" && organizerID != "" {
+				text := fmt.Sprintf("%s\n\n(Declined: %s, %s)", oooMessage, e.Summary, out.Start)
+				content, err := buildTextContent(text)
+				if err == nil {
+					_, err = client.SendMessage("open_id", organizerID, "text", content)
+				}
+				if err != nil {
+					entry["message_error"] = err.Error()
+				} else {
+					entry["message_sent"] = true
+				}
+			}
+		}
+
+		output.JSON(map[string]interface{}{
+			"dry_run":   oooDryRun,
+			"event":     block,
+			"conflicts": affected,
+			"declined":  declined,
+		})
+	},
+}
+
+func init() {
+	oooCmd.Flags().StringVar(&oooFrom, "from", "", "First day out of office (required)")
+	oooCmd.Flags().StringVar(&oooTo, "to", "", "Last day out of office (required)")
+	oooCmd.Flags().StringVar(&oooSummary, "summary", "Out of office", "Title of the out-of-office event")
+	oooCmd.Flags().BoolVar(&oooDecline, "decline", false, "Decline conflicting invitations")
+	oooCmd.Flags().StringVar(&oooMessage, "message", "", "Message sent to the organizer of each declined event")
+	oooCmd.Flags().BoolVar(&oooDryRun, "dry-run", false, "Preview without creating or declining anything")
+}
+
+// eventOrganizerID returns the open_id of the event's organizer, if known
+func eventOrganizerID(e api.Event) string {
+	for _, att := range e.Attendees {
+		if att.IsOrganizer {
+			return att.UserID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,320 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/directory"
+	"github.com/yjwong/lark-cli/internal/output"
+)
+
+// treeNode is a line of an indented tree and the lines nested under it
+type treeNode struct {
+	label    string
+	children []treeNode
+}
+
+// printTree draws a tree with box-drawing branches, like tree(1)
+func printTree(w io.Writer, root treeNode) {
+	fmt.Fprintln(w, root.label)
+	var walk func(nodes []treeNode, prefix string)
+	walk = func(nodes []treeNode, prefix string) {
+		for i, n := range nodes {
+			branch, indent := "├── ", "│   "
+			if i == len(nodes)-1 {
+				branch, indent = "└── ", "    "
+			}
+			fmt.Fprintln(w, prefix+branch+n.label)
+			walk(n.children, prefix+indent)
+		}
+	}
+	walk(root.children, "")
+}
+
+// validateTreeFormat checks a --format flag that takes json or tree
+func validateTreeFormat(format string) {
+	if format != "json" && format != "tree" {
+		output.Fatalf("VALIDATION_ERROR", "Invalid --format: %s (use json or tree)", format)
+	}
+}
+
+// personLabel renders a person as "Name, Job Title <email>"
+func personLabel(p *directory.Person) string {
+	label := p.Name
+	if label == "" {
+		label = p.OpenID
+	}
+	if p.JobTitle != "" {
+		label += ", " + p.JobTitle
+	}
+	if p.Email != "" {
+		label += " <" + p.Email + ">"
+	}
+	return label
+}
+
+// resolveDepartmentRef resolves a department argument: an open_department_id,
+// "0" for the whole company, or a reference such as dept:Design
+func resolveDepartmentRef(client *api.Client, ref string) string {
+	if ref == "0" || strings.HasPrefix(ref, "od_") {
+		return ref
+	}
+	id, err := resolveRef(client, ref)
+	if err != nil {
+		output.Fatal("NOT_FOUND", err)
+	}
+	if id.Kind != directory.KindDepartment {
+		output.Fatalf("VALIDATION_ERROR", "%s is a %s, not a department", ref, id.Kind)
+	}
+	return id.ID
+}
+
+// --- contact tree ---
+
+var (
+	contactTreeDepth  int
+	contactTreeFormat string
+)
+
+var contactTreeCmd = &cobra.Command{
+	Use:   "tree [department]",
+	Short: "Show the department tree with head counts",
+	Long: `Walk a department's sub-departments recursively and show them as a tree.
+
+Each department shows its head count (members including sub-departments) and
+leader. Without a department the whole company is shown. The department can
+be an open_department_id or dept:<name>.
+
+--depth limits how many levels are walked (0 walks them all).
+
+Examples:
+  lark contact tree
+  lark contact tree od_xxxx --depth 2
+  lark contact tree dept:Engineering --format tree`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		validateTreeFormat(contactTreeFormat)
+		if contactTreeDepth < 0 {
+			output.Fatalf("VALIDATION_ERROR", "--depth must be 0 or more")
+		}
+
+		client := api.NewClient()
+
+		root := "0"
+		if len(args) > 0 {
+			root = resolveDepartmentRef(client, args[0])
+		}
+
+		cache, err := directory.OpenCache()
+		if err != nil {
+			output.Fatal("CACHE_ERROR", err)
+		}
+		defer cache.Close()
+
+		tree, err := directory.Tree(client, cache, root, contactTreeDepth)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+
+		count := 0
+		var leaders []string
+		var collect func(n *directory.DeptNode)
+		collect = func(n *directory.DeptNode) {
+			count++
+			if n.LeaderID != "" {
+				leaders = append(leaders, n.LeaderID)
+			}
+			for _, c := range n.Children {
+				collect(c)
+			}
+		}
+		collect(tree)
+
+		if contactTreeFormat == "json" {
+			output.JSON(map[string]interface{}{
+				"tree":        tree,
+				"departments": count,
+			})
+			return
+		}
+
+		var names map[string]string
+		if !rawIDs {
+			names = newDirectoryResolver(cache, client).Names(leaders)
+		}
+		var build func(n *directory.DeptNode) treeNode
+		build = func(n *directory.DeptNode) treeNode {
+			name := n.Name
+			if name == "" {
+				name = n.DepartmentID
+			}
+			label := fmt.Sprintf("%s (%d)", name, n.HeadCount)
+			if n.LeaderID != "" {
+				leader := names[n.LeaderID]
+				if leader == "" {
+					leader = n.LeaderID
+				}
+				label += " - " + leader
+			}
+			node := treeNode{label: label}
+			for _, c := range n.Children {
+				node.children = append(node.children, build(c))
+			}
+			return node
+		}
+		printTree(os.Stdout, build(tree))
+	},
+}
+
+// --- contact manager ---
+
+var (
+	contactManagerChain  bool
+	contactManagerFormat string
+)
+
+var contactManagerCmd = &cobra.Command{
+	Use:   "manager <user>",
+	Short: "Show a user's manager or management chain",
+	Long: `Show a user's direct manager, or with --chain every manager up to the top,
+by following each user's leader in the directory.
+
+Managers are listed from the direct manager up. The user can be an email,
+name (@alice), open_id or alias.
+
+Examples:
+  lark contact manager alice@example.com
+  lark contact manager @alice --chain
+  lark contact manager ou_xxxx --chain --format tree`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		validateTreeFormat(contactManagerFormat)
+
+		client := api.NewClient()
+
+		id, err := resolveUserRef(client, args[0])
+		if err != nil {
+			output.Fatal("NOT_FOUND", err)
+		}
+
+		cache, err := directory.OpenCache()
+		if err != nil {
+			output.Fatal("CACHE_ERROR", err)
+		}
+		defer cache.Close()
+
+		user, chain, err := newDirectoryResolver(cache, client).ManagerChain(id.ID, contactManagerChain)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+
+		managers := make([]*directory.Person, len(chain))
+		for i := range chain {
+			managers[i] = directory.NewPerson(&chain[i])
+		}
+
+		if contactManagerFormat == "json" {
+			output.JSON(map[string]interface{}{
+				"user":     directory.NewPerson(user),
+				"managers": managers,
+				"count":    len(managers),
+			})
+			return
+		}
+
+		// Top of the chain first, down to the user
+		node := treeNode{label: personLabel(directory.NewPerson(user))}
+		for _, m := range managers {
+			node = treeNode{label: personLabel(m), children: []treeNode{node}}
+		}
+		printTree(os.Stdout, node)
+	},
+}
+
+// --- contact reports ---
+
+var (
+	contactReportsRecursive bool
+	contactReportsFormat    string
+)
+
+var contactReportsCmd = &cobra.Command{
+	Use:   "reports <user>",
+	Short: "List the people who report to a user",
+	Long: `List a user's direct reports, or with --recursive their whole organization.
+
+Reporting lines come from the local directory cache, which is synced first if
+it never has been. Run 'lark contact sync' to pick up recent changes. The
+user can be an email, name (@alice), open_id or alias.
+
+Examples:
+  lark contact reports alice@example.com
+  lark contact reports @alice --recursive
+  lark contact reports ou_xxxx --recursive --format tree`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		validateTreeFormat(contactReportsFormat)
+
+		client := api.NewClient()
+
+		id, err := resolveUserRef(client, args[0])
+		if err != nil {
+			output.Fatal("NOT_FOUND", err)
+		}
+
+		cache, err := directory.OpenCache()
+		if err != nil {
+			output.Fatal("CACHE_ERROR", err)
+		}
+		defer cache.Close()
+
+		lastSync, err := cache.LastSync()
+		if err != nil {
+			output.Fatal("CACHE_ERROR", err)
+		}
+		if lastSync.IsZero() {
+			fmt.Fprintln(os.Stderr, "Syncing the directory, this only happens once...")
+			if _, err := directory.Sync(client, cache, &directory.SyncOptions{Progress: os.Stderr}); err != nil {
+				output.Fatal("SYNC_ERROR", err)
+			}
+		}
+
+		user, count, err := newDirectoryResolver(cache, client).Reports(id.ID, contactReportsRecursive)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+
+		if contactReportsFormat == "json" {
+			output.JSON(map[string]interface{}{
+				"user":  user,
+				"count": count,
+			})
+			return
+		}
+
+		var build func(p *directory.Person) treeNode
+		build = func(p *directory.Person) treeNode {
+			node := treeNode{label: personLabel(p)}
+			for _, r := range p.Reports {
+				node.children = append(node.children, build(r))
+			}
+			return node
+		}
+		printTree(os.Stdout, build(user))
+	},
+}
+
+func init() {
+	contactTreeCmd.Flags().IntVar(&contactTreeDepth, "depth", 0, "Levels of sub-departments to walk (0 for all)")
+	contactTreeCmd.Flags().StringVar(&contactTreeFormat, "format", "json", "Output format: json or tree")
+
+	contactManagerCmd.Flags().BoolVar(&contactManagerChain, "chain", false, "Show every manager up to the top")
+	contactManagerCmd.Flags().StringVar(&contactManagerFormat, "format", "json", "Output format: json or tree")
+
+	contactReportsCmd.Flags().BoolVar(&contactReportsRecursive, "recursive", false, "Include everyone below the direct reports")
+	contactReportsCmd.Flags().StringVar(&contactReportsFormat, "format", "json", "Output format: json or tree")
+}
@@ -0,0 +1,338 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yjwong/lark-cli/internal/api"
+	timex "github.com/yjwong/lark-cli/internal/time"
+)
+
+// Recurrence scopes for update/delete of recurring events
+const (
+	scopeInstance  = "instance"
+	scopeFollowing = "following"
+	scopeSeries    = "series"
+)
+
+// rruleUntilFormat is the UTC basic format Lark expects for UNTIL
+const rruleUntilFormat = "20060102T150405Z"
+
+// validateRecurrenceScope checks the value passed to --scope
+func validateRecurrenceScope(scope string) error {
+	switch scope {
+	case "", scopeInstance, scopeFollowing, scopeSeries:
+		return nil
+	default:
+		return fmt.Errorf("invalid scope: %s (must be instance, following, or series)", scope)
+	}
+}
+
+// buildRecurrence assembles an RRULE from the --repeat/--rrule/--until/--count flags.
+// Returns an empty string when no recurrence was requested.
+func buildRecurrence(repeat, rrule, until string, count int, loc *time.Location) (string, error) {
+	if repeat != "" && rrule != "" {
+		return "", fmt.Errorf("--repeat and --rrule cannot be used together")
+	}
+	if until != "" && count > 0 {
+		return "", fmt.Errorf("--until and --count cannot be used together")
+	}
+	if count < 0 {
+		return "", fmt.Errorf("--count must be positive")
+	}
+
+	var parts []rrulePart
+	switch strings.ToLower(repeat) {
+	case "":
+		if rrule == "" {
+			if until != "" || count > 0 {
+				return "", fmt.Errorf("--until and --count require --repeat or --rrule")
+			}
+			return "", nil
+		}
+		parts = parseRRule(rrule)
+		if getRRulePart(parts, "FREQ") == "" {
+			return "", fmt.Errorf("invalid --rrule: missing FREQ")
+		}
+	case "daily", "weekly", "monthly":
+		parts = []rrulePart{{"FREQ", strings.ToUpper(repeat)}, {"INTERVAL", "1"}}
+	default:
+		return "", fmt.Errorf("invalid --repeat: %s (must be daily, weekly, or monthly)", repeat)
+	}
+
+	if until != "" {
+		untilTime, err := timex.Parse(until, loc)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse --until: %v", err)
+		}
+		if !containsTimeSpec(until) {
+			untilTime = timex.EndOfDay(untilTime).Truncate(time.Second)
+		}
+		parts = deleteRRulePart(parts, "COUNT")
+		parts = setRRulePart(parts, "UNTIL", untilTime.UTC().Format(rruleUntilFormat))
+	}
+	if count > 0 {
+		parts = deleteRRulePart(parts, "UNTIL")
+		parts = setRRulePart(parts, "COUNT", strconv.Itoa(count))
+	}
+
+	return formatRRule(parts), nil
+}
+
+// rrulePart is a single KEY=VALUE component of an RRULE, kept in order and as
+// written so that parts this file doesn't edit are passed through unchanged
+type rrulePart struct {
+	Key   string
+	Value string
+}
+
+// parseRRule splits an RRULE string into its components
+func parseRRule(rrule string) []rrulePart {
+	rrule = strings.TrimSpace(rrule)
+	rrule = strings.TrimPrefix(strings.TrimPrefix(rrule, "RRULE:"), "rrule:")
+
+	var parts []rrulePart
+	for _, p := range strings.Split(rrule, ";") {
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			continue
+		}
+		parts = append(parts, rrulePart{Key: strings.TrimSpace(kv[0]), Value: strings.TrimSpace(kv[1])})
+	}
+	return parts
+}
+
+// formatRRule joins RRULE components back into a string
+func formatRRule(parts []rrulePart) string {
+	s := make([]string, len(parts))
+	for i, p := range parts {
+		s[i] = p.Key + "=" + p.Value
+	}
+	return strings.Join(s, ";")
+}
+
+func getRRulePart(parts []rrulePart, key string) string {
+	for _, p := range parts {
+		if strings.EqualFold(p.Key, key) {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+func setRRulePart(parts []rrulePart, key, value string) []rrulePart {
+	for i := range parts {
+		if strings.EqualFold(parts[i].Key, key) {
+			parts[i].Value = value
+			return parts
+		}
+	}
+	return append(parts, rrulePart{Key: key, Value: value})
+}
+
+func deleteRRulePart(parts []rrulePart, key string) []rrulePart {
+	out := parts[:0]
+	for _, p := range parts {
+		if !strings.EqualFold(p.Key, key) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// eventTime converts a Lark TimeInfo to a time.Time in loc.
+// All-day dates are interpreted as midnight in loc.
+func eventTime(ti *api.TimeInfo, loc *time.Location) time.Time {
+	if ti == nil {
+		return time.Time{}
+	}
+	if ti.Date != "" {
+		t, _ := time.ParseInLocation("2006-01-02", ti.Date, loc)
+		return t
+	}
+	ts, _ := strconv.ParseInt(ti.Timestamp, 10, 64)
+	return time.Unix(ts, 0).In(loc)
+}
+
+// seriesID returns the ID of the recurring series an event belongs to,
+// or the event's own ID if it is not an occurrence of a series
+func seriesID(e *api.Event) string {
+	if e.RecurringEventID != "" {
+		return e.RecurringEventID
+	}
+	return e.EventID
+}
+
+// truncateSeries ends a recurring series just before the given time, so that
+// occurrences starting at or after it are removed
+func truncateSeries(client *api.Client, calendarID string, series *api.Event, before time.Time) (*api.Event, error) {
+	parts := parseRRule(series.Recurrence)
+	parts = deleteRRulePart(parts, "COUNT")
+	parts = setRRulePart(parts, "UNTIL", before.Add(-time.Second).UTC().Format(rruleUntilFormat))
+
+	return client.UpdateEvent(calendarID, series.EventID, &api.UpdateEventRequest{
+		Recurrence: formatRRule(parts),
+	})
+}
+
+// countOccurrencesBefore counts the occurrences of a series that start before the given time
+func countOccurrencesBefore(client *api.Client, calendarID string, series *api.Event, before time.Time) (int, error) {
+	start := eventTime(series.StartTime, before.Location())
+	if !start.Before(before) {
+		return 0, nil
+	}
+
+	events, err := client.ListEvents(api.ListEventsOptions{
+		CalendarID: calendarID,
+		StartTime:  start,
+		EndTime:    before,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, e := range events {
+		if e.RecurringEventID == series.EventID && eventTime(e.StartTime, before.Location()).Before(before) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// splitSeries implements the "this and following" scope: the original series is
+// ended before the occurrence at splitAt, and a new series is created from req
+// starting at the (possibly updated) start time. Attendees are carried over.
+func splitSeries(client *api.Client, calendarID string, series *api.Event, splitAt time.Time, req *api.CreateEventRequest) (*api.Event, error) {
+	// Carry the remaining COUNT over to the new series
+	parts := parseRRule(series.Recurrence)
+	if countStr := getRRulePart(parts, "COUNT"); countStr != "" {
+		total, _ := strconv.Atoi(countStr)
+		before, err := countOccurrencesBefore(client, calendarID, series, splitAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count past occurrences: %w", err)
+		}
+		remaining := total - before
+		if remaining < 1 {
+			remaining = 1
+		}
+		parts = setRRulePart(parts, "COUNT", strconv.Itoa(remaining))
+	}
+	if req.Recurrence == "" {
+		req.Recurrence = formatRRule(parts)
+	}
+
+	attendees, err := client.ListEventAttendees(calendarID, series.EventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list series attendees: %w", err)
+	}
+
+	if _, err := truncateSeries(client, calendarID, series, splitAt); err != nil {
+		return nil, fmt.Errorf("failed to end original series: %w", err)
+	}
+
+	created, err := client.CreateEvent(calendarID, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new series: %w", err)
+	}
+
+	if copied := creatableAttendees(attendees); len(copied) > 0 {
+		notify := req.NeedNotify == nil || *req.NeedNotify
+		added, err := client.CreateEventAttendees(calendarID, created.EventID, copied, notify)
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy attendees to new series: %w", err)
+		}
+		created.Attendees = added
+	}
+
+	return created, nil
+}
+
+// createRequestFromEvent builds a create request that reproduces an existing event
+func createRequestFromEvent(e *api.Event) *api.CreateEventRequest {
+	return &api.CreateEventRequest{
+		Summary:         e.Summary,
+		Description:     e.Description,
+		StartTime:       e.StartTime,
+		EndTime:         e.EndTime,
+		Location:        e.Location,
+		Color:           e.Color,
+		Reminders:       e.Reminders,
+		Vchat:           e.Vchat,
+		Visibility:      e.Visibility,
+		AttendeeAbility: e.AttendeeAbility,
+		FreeBusyStatus:  e.FreeBusyStatus,
+		Attachments:     e.Attachments,
+	}
+}
+
+// applyUpdateRequest overlays the fields set in an update request onto a create request
+func applyUpdateRequest(dst *api.CreateEventRequest, req *api.UpdateEventRequest) {
+	if req.Summary != "" {
+		dst.Summary = req.Summary
+	}
+	if req.Description != "" {
+		dst.Description = req.Description
+	}
+	if req.StartTime != nil {
+		dst.StartTime = req.StartTime
+	}
+	if req.EndTime != nil {
+		dst.EndTime = req.EndTime
+	}
+	if req.Location != nil {
+		dst.Location = req.Location
+	}
+	if req.Color != nil {
+		dst.Color = *req.Color
+	}
+	if len(req.Reminders) > 0 {
+		dst.Reminders = req.Reminders
+	}
+	if req.Recurrence != "" {
+		dst.Recurrence = req.Recurrence
+	}
+	if req.Vchat != nil {
+		dst.Vchat = req.Vchat
+	}
+	if req.Visibility != "" {
+		dst.Visibility = req.Visibility
+	}
+	if req.AttendeeAbility != "" {
+		dst.AttendeeAbility = req.AttendeeAbility
+	}
+	if req.NeedNotify != nil {
+		dst.NeedNotify = req.NeedNotify
+	}
+	if req.Attachments != nil {
+		dst.Attachments = req.Attachments
+	}
+}
+
+// creatableAttendees strips server-assigned fields from attendees so they can be
+// re-added to another event
+func creatableAttendees(attendees []api.Attendee) []api.Attendee {
+	var out []api.Attendee
+	for _, att := range attendees {
+		a := api.Attendee{Type: att.Type, IsOptional: att.IsOptional}
+		switch att.Type {
+		case "user":
+			a.UserID = att.UserID
+		case "chat":
+			a.ChatID = att.ChatID
+		case "resource":
+			a.RoomID = att.RoomID
+		case "third_party":
+			a.ThirdPartyEmail = att.ThirdPartyEmail
+		default:
+			continue
+		}
+		if a.UserID == "" && a.ChatID == "" && a.RoomID == "" && a.ThirdPartyEmail == "" {
+			continue
+		}
+		out = append(out, a)
+	}
+	return out
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+// Only COUNT and UNTIL are edited; every other part of a server rule must
+// come back exactly as it was written
+func TestBuildRecurrencePreservesParts(t *testing.T) {
+	loc := time.UTC
+	tests := []struct {
+		name  string
+		rrule string
+		until string
+		count int
+		want  string
+	}{
+		{
+			name:  "count replaces until",
+			rrule: "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1;WKST=SU;UNTIL=20270101T000000Z",
+			count: 5,
+			want:  "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1;WKST=SU;COUNT=5",
+		},
+		{
+			name:  "until replaces count and the prefix is dropped",
+			rrule: "RRULE:FREQ=YEARLY;BYWEEKNO=20;BYDAY=MO;COUNT=3",
+			until: "2027-03-01T10:00:00Z",
+			want:  "FREQ=YEARLY;BYWEEKNO=20;BYDAY=MO;UNTIL=20270301T100000Z",
+		},
+		{
+			name:  "unknown parts and case are kept",
+			rrule: "freq=weekly;X-NAME=value;byday=SA",
+			count: 2,
+			want:  "freq=weekly;X-NAME=value;byday=SA;COUNT=2",
+		},
+		{
+			name:  "existing count is updated in place",
+			rrule: "FREQ=DAILY;COUNT=10;INTERVAL=2",
+			count: 4,
+			want:  "FREQ=DAILY;COUNT=4;INTERVAL=2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildRecurrence("", tt.rrule, tt.until, tt.count, loc)
+			if err != nil {
+				t.Fatalf("buildRecurrence: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,464 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/config"
+	"github.com/yjwong/lark-cli/internal/output"
+	"github.com/yjwong/lark-cli/internal/parallel"
+	timex "github.com/yjwong/lark-cli/internal/time"
+)
+
+// roomFreebusyWorkers bounds concurrent freebusy queries in rooms find
+const roomFreebusyWorkers = 5
+
+var roomsCmd = &cobra.Command{
+	Use:   "rooms",
+	Short: "Find meeting rooms",
+	Long: `Browse, search, and find available meeting rooms.
+
+Rooms are organised in levels (typically buildings and floors). Room IDs
+(omm_...) can be passed to --room on 'cal create', 'cal attendee add' and
+'cal freebusy'.
+
+Requires the app permission vc:room:readonly.`,
+}
+
+// --- Room filters shared by list, search and find ---
+
+var (
+	roomsLevel     string
+	roomsCapacity  int
+	roomsEquipment []string
+)
+
+func addRoomFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&roomsLevel, "level", "", "Only rooms under this building/floor level ID")
+	cmd.Flags().IntVar(&roomsCapacity, "capacity", 0, "Minimum room capacity")
+	cmd.Flags().StringSliceVar(&roomsEquipment, "equipment", []string{}, "Required equipment, matched by name (repeatable)")
+}
+
+// --- List Rooms ---
+
+var roomsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List meeting rooms",
+	Long: `List meeting rooms with their building, floor, capacity and equipment.
+
+Examples:
+  lark cal rooms list
+  lark cal rooms list --level omb_xxxxxxxxxx
+  lark cal rooms list --capacity 8 --equipment projector`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := api.NewClient()
+
+		rooms, err := collectRooms(client, roomsLevel)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+
+		outRooms := filterRooms(describeRooms(client, rooms), roomsCapacity, roomsEquipment)
+		output.JSON(map[string]interface{}{
+			"rooms": outRooms,
+			"count": len(outRooms),
+		})
+	},
+}
+
+// --- Search Rooms ---
+
+var roomsSearchCmd = &cobra.Command{
+	Use:   "search <keyword>",
+	Short: "Search meeting rooms",
+	Long: `Search meeting rooms by name, building, or floor.
+
+Examples:
+  lark cal rooms search "Everest"
+  lark cal rooms search "Tower A" --capacity 10`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := api.NewClient()
+
+		rooms, err := client.SearchRooms(args[0], roomsLevel, true)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+
+		outRooms := filterRooms(describeRooms(client, rooms), roomsCapacity, roomsEquipment)
+		output.JSON(map[string]interface{}{
+			"query": args[0],
+			"rooms": outRooms,
+			"count": len(outRooms),
+		})
+	},
+}
+
+// --- Room Levels ---
+
+var roomsLevelsCmd = &cobra.Command{
+	Use:   "levels [level-id]",
+	Short: "List buildings and floors",
+	Long: `List the room levels (buildings, floors) under a level.
+Without an argument, lists the top level.
+
+Examples:
+  lark cal rooms levels
+  lark cal rooms levels omb_xxxxxxxxxx`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		parentID := ""
+		if len(args) > 0 {
+			parentID = args[0]
+		}
+
+		client := api.NewClient()
+		levels, err := client.ListRoomLevels(parentID)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+
+		outLevels := make([]api.OutputRoomLevel, len(levels))
+		for i, l := range levels {
+			outLevels[i] = api.OutputRoomLevel{
+				ID:       l.RoomLevelID,
+				Name:     l.Name,
+				ParentID: l.ParentID,
+				HasChild: l.HasChild,
+			}
+		}
+
+		output.JSON(map[string]interface{}{
+			"levels": outLevels,
+			"count":  len(outLevels),
+		})
+	},
+}
+
+// --- Find Available Rooms ---
+
+var (
+	roomsFindFrom    string
+	roomsFindTo      string
+	roomsFindKeyword string
+)
+
+var roomsFindCmd = &cobra.Command{
+	Use:   "find",
+	Short: "Find rooms that are free for a time range",
+	Long: `Find meeting rooms that are free for the whole time range.
+
+Candidate rooms are filtered by capacity and equipment, then checked with
+freebusy. Disabled rooms are skipped. Results are sorted smallest room first.
+
+Examples:
+  lark cal rooms find --from 2026-01-06T14:00:00+08:00 --to 2026-01-06T15:00:00+08:00 --capacity 6
+  lark cal rooms find --from 2026-01-06T14:00:00+08:00 --to 2026-01-06T15:00:00+08:00 --keyword "Tower A"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if roomsFindFrom == "" || roomsFindTo == "" {
+			output.Fatalf("VALIDATION_ERROR", "--from and --to are required")
+		}
+
+		// Parse timezone
+		tz := config.GetTimezone()
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			loc = time.Local
+		}
+
+		startTime, err := timex.Parse(roomsFindFrom, loc)
+		if err != nil {
+			output.Fatalf("PARSE_ERROR", "Failed to parse --from: %v", err)
+		}
+		endTime, err := timex.Parse(roomsFindTo, loc)
+		if err != nil {
+			output.Fatalf("PARSE_ERROR", "Failed to parse --to: %v", err)
+		}
+		if !containsTimeSpec(roomsFindFrom) {
+			startTime = timex.StartOfDay(startTime)
+		}
+		if !containsTimeSpec(roomsFindTo) {
+			endTime = timex.EndOfDay(endTime)
+		}
+		if !endTime.After(startTime) {
+			output.Fatalf("VALIDATION_ERROR", "--to must be after --from")
+		}
+
+		client := api.NewClient()
+
+		var rooms []api.Room
+		if roomsFindKeyword != "" {
+			rooms, err = client.SearchRooms(roomsFindKeyword, roomsLevel, true)
+		} else {
+			rooms, err = collectRooms(client, roomsLevel)
+		}
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+
+		candidates := filterRooms(describeRooms(client, rooms), roomsCapacity, roomsEquipment)
+		var enabled []api.OutputRoom
+		for _, r := range candidates {
+			if !r.Disabled {
+				enabled = append(enabled, r)
+			}
+		}
+
+		available, err := availableRooms(client, enabled, startTime, endTime)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+
+		sort.SliceStable(available, func(i, j int) bool {
+			if available[i].Capacity != available[j].Capacity {
+				return available[i].Capacity < available[j].Capacity
+			}
+			return available[i].Name < available[j].Name
+		})
+
+		output.JSON(map[string]interface{}{
+			"from":    startTime.Format(time.RFC3339),
+			"to":      endTime.Format(time.RFC3339),
+			"checked": len(enabled),
+			"rooms":   available,
+			"count":   len(available),
+		})
+	},
+}
+
+func init() {
+	addRoomFilterFlags(roomsListCmd)
+	addRoomFilterFlags(roomsSearchCmd)
+	addRoomFilterFlags(roomsFindCmd)
+
+	roomsFindCmd.Flags().StringVar(&roomsFindFrom, "from", "", "Start time (required)")
+	roomsFindCmd.Flags().StringVar(&roomsFindTo, "to", "", "End time (required)")
+	roomsFindCmd.Flags().StringVar(&roomsFindKeyword, "keyword", "", "Only rooms matching this name, building or floor")
+
+	roomsCmd.AddCommand(roomsListCmd)
+	roomsCmd.AddCommand(roomsSearchCmd)
+	roomsCmd.AddCommand(roomsLevelsCmd)
+	roomsCmd.AddCommand(roomsFindCmd)
+}
+
+// collectRooms returns all rooms under a level, walking down through child levels
+func collectRooms(client *api.Client, levelID string) ([]api.Room, error) {
+	var rooms []api.Room
+	queue := []string{levelID}
+	seen := make(map[string]bool)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		levelRooms, err := client.ListRooms(id)
+		if err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, levelRooms...)
+
+		children, err := client.ListRoomLevels(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			queue = append(queue, child.RoomLevelID)
+		}
+	}
+	return rooms, nil
+}
+
+// describeRooms converts rooms to output form, resolving level IDs to
+// building and floor names. Level lookups that fail leave those fields empty.
+func describeRooms(client *api.Client, rooms []api.Room) []api.OutputRoom {
+	chains := make([][]string, len(rooms))
+	var levelIDs []string
+	wanted := make(map[string]bool)
+	for i, r := range rooms {
+		var chain []string
+		for _, id := range r.Path {
+			if id != "" && id != "0" {
+				chain = append(chain, id)
+			}
+		}
+		if r.RoomLevelID != "" && r.RoomLevelID != "0" && (len(chain) == 0 || chain[len(chain)-1] != r.RoomLevelID) {
+			chain = append(chain, r.RoomLevelID)
+		}
+		chains[i] = chain
+		for _, id := range chain {
+			if !wanted[id] {
+				wanted[id] = true
+				levelIDs = append(levelIDs, id)
+			}
+		}
+	}
+
+	names := make(map[string]string)
+	if len(levelIDs) > 0 {
+		if levels, err := client.GetRoomLevels(levelIDs); err == nil {
+			for _, l := range levels {
+				names[l.RoomLevelID] = l.Name
+			}
+		}
+	}
+
+	out := make([]api.OutputRoom, len(rooms))
+	for i, r := range rooms {
+		o := api.OutputRoom{
+			RoomID:      r.RoomID,
+			Name:        r.Name,
+			Capacity:    r.Capacity,
+			Description: r.Description,
+		}
+		// The deepest two levels are the building and floor
+		chain := chains[i]
+		switch {
+		case len(chain) >= 2:
+			o.Building = names[chain[len(chain)-2]]
+			o.Floor = names[chain[len(chain)-1]]
+		case len(chain) == 1:
+			o.Building = names[chain[0]]
+		}
+		for _, d := range r.Device {
+			if d.Name != "" {
+				o.Equipment = append(o.Equipment, d.Name)
+			}
+		}
+		if r.RoomStatus != nil && !r.RoomStatus.Status {
+			o.Disabled = true
+		}
+		out[i] = o
+	}
+	return out
+}
+
+// filterRooms keeps rooms with at least minCapacity seats and all of the
+// requested equipment (case-insensitive substring match)
+func filterRooms(rooms []api.OutputRoom, minCapacity int, equipment []string) []api.OutputRoom {
+	out := []api.OutputRoom{}
+	for _, r := range rooms {
+		if r.Capacity < minCapacity {
+			continue
+		}
+		if !hasEquipment(r, equipment) {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+func hasEquipment(r api.OutputRoom, equipment []string) bool {
+	for _, want := range equipment {
+		want = strings.ToLower(strings.TrimSpace(want))
+		if want == "" {
+			continue
+		}
+		found := false
+		for _, have := range r.Equipment {
+			if strings.Contains(strings.ToLower(have), want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// availableRooms returns the rooms with no busy periods between start and end
+func availableRooms(client *api.Client, rooms []api.OutputRoom, start, end time.Time) ([]api.OutputRoom, error) {
+	free := make([]bool, len(rooms))
+	err := parallel.Do(len(rooms), roomFreebusyWorkers, func(i int) error {
+		periods, err := client.GetFreebusy(api.FreebusyOptions{
+			StartTime: start,
+			EndTime:   end,
+			RoomID:    rooms[i].RoomID,
+		})
+		if err != nil {
+			return fmt.Errorf("checking %s: %w", rooms[i].Name, err)
+		}
+		free[i] = len(periods) == 0
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	available := []api.OutputRoom{}
+	for i, r := range rooms {
+		if free[i] {
+			available = append(available, r)
+		}
+	}
+	return available, nil
+}
+
+// resolveRooms turns --room values into resource attendees. Values that are
+// not room IDs (omm_...) are looked up by name and must match a single room.
+func resolveRooms(client *api.Client, values []string) ([]api.Attendee, error) {
+	var attendees []api.Attendee
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		roomID := v
+		if !strings.HasPrefix(v, "omm_") {
+			id, err := findRoomByName(client, v)
+			if err != nil {
+				return nil, err
+			}
+			roomID = id
+		}
+		attendees = append(attendees, api.Attendee{
+			Type:   "resource",
+			RoomID: roomID,
+		})
+	}
+	return attendees, nil
+}
+
+// findRoomByName returns the ID of the room with the given name. An exact
+// (case-insensitive) match wins; otherwise the search must be unambiguous.
+func findRoomByName(client *api.Client, name string) (string, error) {
+	rooms, err := client.SearchRooms(name, "", false)
+	if err != nil {
+		return "", fmt.Errorf("failed to search rooms: %w", err)
+	}
+
+	var exact []api.Room
+	for _, r := range rooms {
+		if strings.EqualFold(r.Name, name) {
+			exact = append(exact, r)
+		}
+	}
+	if len(exact) == 1 {
+		return exact[0].RoomID, nil
+	}
+	if len(exact) == 0 && len(rooms) == 1 {
+		return rooms[0].RoomID, nil
+	}
+	if len(rooms) == 0 {
+		return "", fmt.Errorf("no meeting room found matching %q", name)
+	}
+
+	matches := exact
+	if len(matches) == 0 {
+		matches = rooms
+	}
+	var names []string
+	for _, r := range matches {
+		names = append(names, fmt.Sprintf("%s (%s)", r.Name, r.RoomID))
+	}
+	return "", fmt.Errorf("room %q is ambiguous, use a room ID: %s", name, strings.Join(names, ", "))
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/yjwong/lark-cli/internal/config"
 	"github.com/yjwong/lark-cli/internal/output"
+	timex "github.com/yjwong/lark-cli/internal/time"
 )
 
 // Version information - set via ldflags at build time
@@ -23,13 +24,18 @@ func SetVersionInfo(v, c, d string) {
 	date = d
 }
 
+// displayTZ is the global --tz flag
+var displayTZ string
+
 var rootCmd = &cobra.Command{
 	Use:   "lark",
 	Short: "Lark CLI for Claude Code",
 	Long: `A CLI tool to interact with Lark APIs.
 Designed for use by Claude Code with JSON output.
 
-All commands output JSON by default.`,
+All commands output JSON by default. Use --tz to show every timestamp in
+the output in another time zone. Once 'lark contact sync' has filled the
+directory cache, names are added next to open_ids; --raw-ids turns this off.`,
 	SilenceUsage:  true,
 	SilenceErrors: true,
 }
@@ -62,9 +68,26 @@ func Execute() {
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVar(&displayTZ, "tz", "", "Render timestamps in output in this IANA time zone (e.g. Europe/London)")
+	rootCmd.PersistentFlags().BoolVar(&rawIDs, "raw-ids", false, "Don't add names next to open_ids in output")
+	cobra.OnInitialize(func() {
+		if !rawIDs {
+			output.SetNameResolver(outputNameResolver())
+		}
+		if displayTZ == "" {
+			return
+		}
+		loc, err := timex.LoadLocation(displayTZ)
+		if err != nil {
+			output.Fatal("VALIDATION_ERROR", err)
+		}
+		output.SetTimezone(loc)
+	})
+
 	rootCmd.AddCommand(authCmd)
 	rootCmd.AddCommand(bitableCmd)
 	rootCmd.AddCommand(calCmd)
+	rootCmd.AddCommand(caldavCmd)
 	rootCmd.AddCommand(chatCmd)
 	rootCmd.AddCommand(contactCmd)
 	rootCmd.AddCommand(docCmd)
@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/directory"
+	"github.com/yjwong/lark-cli/internal/ics"
+	"github.com/yjwong/lark-cli/internal/output"
+)
+
+// rosterColumns are the columns contact export can write
+var rosterColumns = []string{
+	"name", "en_name", "email", "mobile", "job_title", "department", "manager",
+	"city", "country", "employee_type", "employee_no", "open_id", "user_id",
+}
+
+// defaultRosterColumns are written when --columns isn't given
+var defaultRosterColumns = []string{
+	"name", "email", "mobile", "job_title", "department", "manager", "city", "employee_type",
+}
+
+// employeeTypes names Lark's employee_type values
+var employeeTypes = map[int]string{
+	1: "regular",
+	2: "intern",
+	3: "outsourcing",
+	4: "labor",
+	5: "consultant",
+}
+
+var (
+	contactExportDept      string
+	contactExportRecursive bool
+	contactExportFormat    string
+	contactExportColumns   []string
+	contactExportOutput    string
+)
+
+var contactExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export a department roster as CSV, vCard or JSON",
+	Long: `Export the users in a department, and with --recursive its sub-departments,
+as CSV, vCard or JSON.
+
+Columns (pick with --columns, in the order given):
+  name, en_name, email, mobile, job_title, department (the full department
+  path), manager, city, country, employee_type, employee_no, open_id, user_id
+
+The default columns are name, email, mobile, job_title, department, manager,
+city and employee_type. vCard output (vCard 3.0) imports into phone contacts;
+the department path becomes the organization and the manager and employee
+type go in the note.
+
+The department can be an open_department_id, dept:<name> or 0 for the whole
+company. Every page of users is fetched.
+
+Examples:
+  lark contact export --dept od_xxxx --format csv --output team.csv
+  lark contact export --dept dept:Engineering --recursive --format vcf --output engineering.vcf
+  lark contact export --dept 0 --recursive --columns name,email,manager`,
+	Run: func(cmd *cobra.Command, args []string) {
+		switch contactExportFormat {
+		case "csv", "vcf", "json":
+		default:
+			output.Fatalf("VALIDATION_ERROR", "Invalid --format: %s (use csv, vcf or json)", contactExportFormat)
+		}
+		columns := defaultRosterColumns
+		if len(contactExportColumns) > 0 {
+			columns = nil
+			for _, c := range contactExportColumns {
+				c = strings.ToLower(strings.TrimSpace(c))
+				if !containsString(rosterColumns, c) {
+					output.Fatalf("VALIDATION_ERROR", "Unknown column: %s (use %s)", c, strings.Join(rosterColumns, ", "))
+				}
+				columns = append(columns, c)
+			}
+		}
+
+		client := api.NewClient()
+
+		root := resolveDepartmentRef(client, contactExportDept)
+
+		depts, users, err := directory.FetchDepartment(client, root, contactExportRecursive, os.Stderr)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+		sort.SliceStable(users, func(i, j int) bool { return users[i].Name < users[j].Name })
+
+		cache, err := directory.OpenCache()
+		if err != nil {
+			output.Fatal("CACHE_ERROR", err)
+		}
+		defer cache.Close()
+
+		now := time.Now()
+		if err := cache.PutDepartments(depts, now); err != nil {
+			output.Fatal("CACHE_ERROR", err)
+		}
+		if err := cache.PutUsers(users, now); err != nil {
+			output.Fatal("CACHE_ERROR", err)
+		}
+		resolver := newDirectoryResolver(cache, client)
+
+		var leaders []string
+		for _, u := range users {
+			if u.LeaderUserID != "" {
+				leaders = append(leaders, u.LeaderUserID)
+			}
+		}
+		// Managers outside the department come from the cache or are fetched
+		// in batches; the ones that can't be found are reported
+		managers, unresolved, err := resolver.Users(leaders)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: looking up managers: %v\n", err)
+		}
+		if unresolved == nil {
+			unresolved = []string{}
+		}
+		paths := make(map[string][]string)
+
+		rows := make([]map[string]string, len(users))
+		orgs := make([][]string, len(users))
+		for i, u := range users {
+			if len(u.DepartmentIDs) > 0 {
+				id := u.DepartmentIDs[0]
+				if _, ok := paths[id]; !ok {
+					paths[id] = resolver.DepartmentPath(id)
+				}
+				orgs[i] = paths[id]
+			}
+			rows[i] = rosterRow(&u, orgs[i], managers[u.LeaderUserID].Name)
+		}
+
+		var buf bytes.Buffer
+		switch contactExportFormat {
+		case "csv":
+			w := csv.NewWriter(&buf)
+			w.Write(columns)
+			for _, row := range rows {
+				record := make([]string, len(columns))
+				for j, c := range columns {
+					record[j] = row[c]
+				}
+				w.Write(record)
+			}
+			w.Flush()
+			if err := w.Error(); err != nil {
+				output.Fatal("EXPORT_ERROR", err)
+			}
+		case "vcf":
+			cards := make([]ics.Card, len(rows))
+			for i, row := range rows {
+				cards[i] = rosterCard(row, orgs[i], columns)
+			}
+			if err := ics.EncodeCards(&buf, cards); err != nil {
+				output.Fatal("EXPORT_ERROR", err)
+			}
+		case "json":
+			contacts := make([]map[string]string, len(rows))
+			for i, row := range rows {
+				contacts[i] = make(map[string]string)
+				for _, c := range columns {
+					if row[c] != "" {
+						contacts[i][c] = row[c]
+					}
+				}
+			}
+			if contactExportOutput == "" {
+				output.JSON(map[string]interface{}{
+					"contacts":            contacts,
+					"count":               len(contacts),
+					"unresolved_managers": unresolved,
+				})
+				return
+			}
+			data, err := json.MarshalIndent(contacts, "", "  ")
+			if err != nil {
+				output.Fatal("EXPORT_ERROR", err)
+			}
+			buf.Write(append(data, '\n'))
+		}
+
+		if contactExportOutput == "" {
+			os.Stdout.Write(buf.Bytes())
+			for _, id := range unresolved {
+				fmt.Fprintf(os.Stderr, "unresolved manager: %s\n", id)
+			}
+			return
+		}
+		if err := os.WriteFile(contactExportOutput, buf.Bytes(), 0644); err != nil {
+			output.Fatal("FILE_ERROR", err)
+		}
+		output.JSON(map[string]interface{}{
+			"success":             true,
+			"format":              contactExportFormat,
+			"file":                contactExportOutput,
+			"count":               len(rows),
+			"unresolved_managers": unresolved,
+		})
+	},
+}
+
+// rosterRow holds every export column for a user
+func rosterRow(u *api.ContactUser, deptPath []string, manager string) map[string]string {
+	email := u.Email
+	if email == "" {
+		email = u.EnterpriseEmail
+	}
+	employeeType := employeeTypes[u.EmployeeType]
+	if employeeType == "" && u.EmployeeType != 0 {
+		employeeType = strconv.Itoa(u.EmployeeType)
+	}
+	return map[string]string{
+		"name":          u.Name,
+		"en_name":       u.EnName,
+		"email":         email,
+		"mobile":        u.Mobile,
+		"job_title":     u.JobTitle,
+		"department":    strings.Join(deptPath, " / "),
+		"manager":       manager,
+		"city":          u.City,
+		"country":       u.Country,
+		"employee_type": employeeType,
+		"employee_no":   u.EmployeeNo,
+		"open_id":       u.OpenID,
+		"user_id":       u.UserID,
+	}
+}
+
+// rosterCard converts an export row to a vCard with the selected columns.
+// Columns without a vCard property are written to the note.
+func rosterCard(row map[string]string, deptPath []string, columns []string) ics.Card {
+	card := ics.Card{Name: row["name"], UID: row["open_id"]}
+	var note []string
+	for _, c := range columns {
+		switch c {
+		case "name", "open_id":
+		case "email":
+			card.Email = row[c]
+		case "mobile":
+			card.Mobile = row[c]
+		case "job_title":
+			card.Title = row[c]
+		case "department":
+			card.Org = deptPath
+		case "city":
+			card.City = row[c]
+		default:
+			if row[c] != "" {
+				note = append(note, strings.ReplaceAll(c, "_", " ")+": "+row[c])
+			}
+		}
+	}
+	card.Note = strings.Join(note, "\n")
+	return card
+}
+
+func init() {
+	contactExportCmd.Flags().StringVar(&contactExportDept, "dept", "0", "Department to export (open_department_id, dept:<name> or 0)")
+	contactExportCmd.Flags().BoolVar(&contactExportRecursive, "recursive", false, "Include sub-departments")
+	contactExportCmd.Flags().StringVar(&contactExportFormat, "format", "csv", "Output format: csv, vcf or json")
+	contactExportCmd.Flags().StringSliceVar(&contactExportColumns, "columns", nil, "Columns to export, comma-separated (default: name,email,mobile,job_title,department,manager,city,employee_type)")
+	contactExportCmd.Flags().StringVar(&contactExportOutput, "output", "", "Write to this file instead of stdout")
+}
@@ -54,8 +54,8 @@ Examples:
 
 		client := api.NewClient()
 
-		// Get primary calendar
-		cal, err := client.GetPrimaryCalendar()
+		// Get the selected calendar (primary unless --calendar is set)
+		cal, err := resolveCalendar(client)
 		if err != nil {
 			output.Fatal("CALENDAR_ERROR", err)
 		}
@@ -0,0 +1,361 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/config"
+	"github.com/yjwong/lark-cli/internal/directory"
+	"github.com/yjwong/lark-cli/internal/output"
+	"github.com/yjwong/lark-cli/internal/scheduler"
+	timex "github.com/yjwong/lark-cli/internal/time"
+)
+
+var (
+	scheduleAttendees   []string
+	scheduleOptional    []string
+	scheduleDuration    string
+	scheduleWithin      string
+	scheduleFrom        string
+	scheduleTo          string
+	scheduleWorkHours   string
+	scheduleBuffer      int
+	scheduleLimit       int
+	scheduleExcludeSelf bool
+	scheduleBook        bool
+	scheduleSummary     string
+	scheduleDescription string
+	scheduleNoNotify    bool
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Propose ranked meeting slots",
+	Long: `Find the best times to meet with a group of attendees.
+
+Free/busy is merged for every attendee. A slot is only proposed when all
+required attendees (and you, unless --exclude-self) are free and inside
+working hours in their own time zone. Working hours and days come from the
+working_hours config, and your holidays are never proposed. Optional attendees who are busy, slots
+outside their working hours, and meetings closer than --buffer minutes lower
+the score. Each slot lists the reasons for its ranking.
+
+Attendees can be emails, names (@alice), open_ids, chat:<name> or
+dept:<name> (expanded to their members), or aliases from config. External
+attendees can't be checked and are reported as unchecked.
+
+--within accepts: today, tomorrow, this week, next week, next N days,
+next N weeks, next N working days.
+
+Examples:
+  lark cal schedule --attendees alice@example.com,bob@example.com --duration 45m
+  lark cal schedule --attendees ou_xxx --optional carol@example.com --duration 30m --within "next 3 days"
+  lark cal schedule --attendees "chat:Team Standup" --optional @carol --duration 30m
+  lark cal schedule --attendees alice@example.com --duration 1h --book --summary "Planning"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(scheduleAttendees) == 0 {
+			output.Fatalf("VALIDATION_ERROR", "--attendees is required")
+		}
+		duration, err := timex.ParseDuration(scheduleDuration)
+		if err != nil {
+			output.Fatalf("PARSE_ERROR", "Failed to parse --duration: %v", err)
+		}
+		if scheduleWorkHours != "" {
+			if _, _, err := timex.ParseClockRange(scheduleWorkHours); err != nil {
+				output.Fatal("VALIDATION_ERROR", err)
+			}
+		}
+
+		// Parse timezone
+		tz := config.GetTimezone()
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			loc = time.Local
+			tz = loc.String()
+		}
+		now := time.Now().In(loc)
+		client := api.NewClient()
+
+		startTime, endTime, err := parseWithin(client, scheduleWithin, now)
+		if err != nil {
+			output.Fatalf("PARSE_ERROR", "Failed to parse --within: %v", err)
+		}
+		if scheduleFrom != "" {
+			startTime, err = timex.Parse(scheduleFrom, loc)
+			if err != nil {
+				output.Fatalf("PARSE_ERROR", "Failed to parse --from: %v", err)
+			}
+		}
+		if scheduleTo != "" {
+			endTime, err = timex.Parse(scheduleTo, loc)
+			if err != nil {
+				output.Fatalf("PARSE_ERROR", "Failed to parse --to: %v", err)
+			}
+			if !containsTimeSpec(scheduleTo) {
+				endTime = timex.EndOfDay(endTime)
+			}
+		}
+		if !endTime.After(startTime) {
+			output.Fatalf("VALIDATION_ERROR", "end of range must be after start")
+		}
+		if endTime.Sub(startTime) > 90*24*time.Hour {
+			output.Fatalf("VALIDATION_ERROR", "Time range cannot exceed 90 days")
+		}
+
+		wh, err := loadWorkingHours(client, scheduleWorkHours, startTime, endTime)
+		if err != nil {
+			output.Fatal("CONFIG_ERROR", err)
+		}
+
+		required, externalRequired, err := resolveScheduleAttendees(client, scheduleAttendees)
+		if err != nil {
+			output.Fatal("VALIDATION_ERROR", err)
+		}
+		optional, externalOptional, err := resolveScheduleAttendees(client, scheduleOptional)
+		if err != nil {
+			output.Fatal("VALIDATION_ERROR", err)
+		}
+
+		// Anyone listed as required isn't also optional
+		var optionalOnly []string
+		for _, id := range optional {
+			if !containsString(required, id) {
+				optionalOnly = append(optionalOnly, id)
+			}
+		}
+		optional = optionalOnly
+
+		var selfID string
+		if !scheduleExcludeSelf {
+			currentUser, err := client.GetCurrentUser()
+			if err != nil {
+				output.Fatalf("USER_ERROR", "Failed to get current user: %v", err)
+			}
+			selfID = currentUser.OpenID
+			if !containsString(required, selfID) {
+				required = append([]string{selfID}, required...)
+			}
+		}
+
+		// Gather busy times for everyone we can see
+		var participants []scheduler.Participant
+		for _, group := range []struct {
+			ids      []string
+			optional bool
+		}{{required, false}, {optional, true}} {
+			for _, id := range group.ids {
+				p, err := scheduleParticipant(client, id, group.optional, loc, startTime, endTime)
+				if err != nil {
+					output.Fatal("API_ERROR", err)
+				}
+				if id == selfID {
+					// Your own hours and holidays come from config
+					p.Name = "you"
+					p.Location = wh.Location
+					p.DaysOff = wh.Holidays
+				}
+				participants = append(participants, p)
+			}
+		}
+
+		slots := scheduler.Find(participants, startTime, endTime, scheduler.Options{
+			Duration:      duration,
+			Step:          30 * time.Minute,
+			BufferMinutes: scheduleBuffer,
+			WorkingHours:  wh,
+			Limit:         scheduleLimit,
+			Now:           now,
+		})
+
+		outSlots := make([]map[string]interface{}, len(slots))
+		for i, s := range slots {
+			outSlots[i] = map[string]interface{}{
+				"rank":    i + 1,
+				"start":   s.Start.In(loc).Format(time.RFC3339),
+				"end":     s.End.In(loc).Format(time.RFC3339),
+				"score":   s.Score,
+				"reasons": s.Reasons,
+			}
+		}
+
+		outAttendees := make([]map[string]interface{}, len(participants))
+		for i, p := range participants {
+			outAttendees[i] = map[string]interface{}{
+				"user_id":  p.ID,
+				"name":     p.Name,
+				"timezone": p.Location.String(),
+				"optional": p.Optional,
+			}
+		}
+
+		result := map[string]interface{}{
+			"query": map[string]interface{}{
+				"from":           startTime.Format(time.RFC3339),
+				"to":             endTime.Format(time.RFC3339),
+				"duration":       int(duration.Minutes()),
+				"work_hours":     timex.FormatClockRange(wh.Start, wh.End),
+				"buffer_minutes": scheduleBuffer,
+				"timezone":       tz,
+			},
+			"attendees": outAttendees,
+			"slots":     outSlots,
+			"count":     len(outSlots),
+		}
+		if len(externalRequired)+len(externalOptional) > 0 {
+			result["unchecked"] = append(append([]string{}, externalRequired...), externalOptional...)
+		}
+
+		if !scheduleBook {
+			output.JSON(result)
+			return
+		}
+
+		if len(slots) == 0 {
+			output.Fatalf("VALIDATION_ERROR", "No available slot to book")
+		}
+
+		event, err := bookScheduledSlot(client, slots[0], tz, required, optional, externalRequired, externalOptional)
+		if err != nil {
+			output.Fatal("API_ERROR", err)
+		}
+		result["success"] = true
+		result["message"] = fmt.Sprintf("Event created: %s", event.EventID)
+		result["event"] = api.ConvertToOutputEvent(*event)
+		output.JSON(result)
+	},
+}
+
+func init() {
+	scheduleCmd.Flags().StringSliceVar(&scheduleAttendees, "attendees", []string{}, "Required attendees: emails, names, open_ids, chat:<name>, dept:<name> or aliases (comma-separated or repeatable)")
+	scheduleCmd.Flags().StringSliceVar(&scheduleOptional, "optional", []string{}, "Optional attendees, in the same forms as --attendees")
+	scheduleCmd.Flags().StringVar(&scheduleDuration, "duration", "", "Meeting length (required, e.g. 30m, 1h)")
+	scheduleCmd.Flags().StringVar(&scheduleWithin, "within", "next 5 working days", "Range to search")
+	scheduleCmd.Flags().StringVar(&scheduleFrom, "from", "", "Start of range (overrides --within)")
+	scheduleCmd.Flags().StringVar(&scheduleTo, "to", "", "End of range (overrides --within)")
+	scheduleCmd.Flags().StringVar(&scheduleWorkHours, "work-hours", "", "Working hours in each attendee's time zone (default: working_hours from config)")
+	scheduleCmd.Flags().IntVar(&scheduleBuffer, "buffer", 10, "Preferred gap to other meetings in minutes (0 to disable)")
+	scheduleCmd.Flags().IntVar(&scheduleLimit, "limit", 5, "Maximum slots to return")
+	scheduleCmd.Flags().BoolVar(&scheduleExcludeSelf, "exclude-self", false, "Don't require your own availability or add you to the event")
+	scheduleCmd.Flags().BoolVar(&scheduleBook, "book", false, "Create the event for the top-ranked slot")
+	scheduleCmd.Flags().StringVar(&scheduleSummary, "summary", "Meeting", "Event title when booking")
+	scheduleCmd.Flags().StringVar(&scheduleDescription, "description", "", "Event description when booking")
+	scheduleCmd.Flags().BoolVar(&scheduleNoNotify, "no-notify", false, "Don't send notifications when booking")
+
+	scheduleCmd.MarkFlagRequired("attendees")
+	scheduleCmd.MarkFlagRequired("duration")
+}
+
+// resolveScheduleAttendees splits attendee references into Lark open_ids
+// and external emails that can't be checked for availability. Chats and
+// departments expand to their members.
+func resolveScheduleAttendees(client *api.Client, values []string) ([]string, []string, error) {
+	people, err := resolvePeople(client, values, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	var ids, external []string
+	for _, p := range people {
+		if p.Kind == directory.KindEmail {
+			external = append(external, p.Email)
+		} else {
+			ids = append(ids, p.ID)
+		}
+	}
+	return ids, external, nil
+}
+
+// scheduleParticipant loads a user's name, time zone and busy periods
+func scheduleParticipant(client *api.Client, openID string, optional bool, defaultLoc *time.Location, from, to time.Time) (scheduler.Participant, error) {
+	p := scheduler.Participant{ID: openID, Name: openID, Optional: optional, Location: defaultLoc}
+
+	if user, err := client.GetUser(openID, "open_id"); err == nil && user != nil {
+		if user.Name != "" {
+			p.Name = user.Name
+		}
+		if user.TimeZone != "" {
+			if loc, err := time.LoadLocation(user.TimeZone); err == nil {
+				p.Location = loc
+			}
+		}
+	}
+
+	periods, err := client.GetFreebusy(api.FreebusyOptions{
+		StartTime: from,
+		EndTime:   to,
+		UserID:    openID,
+	})
+	if err != nil {
+		return p, fmt.Errorf("failed to get free/busy for %s: %w", p.Name, err)
+	}
+	for _, period := range periods {
+		start, err1 := time.Parse(time.RFC3339, period.StartTime)
+		end, err2 := time.Parse(time.RFC3339, period.EndTime)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		p.Busy = append(p.Busy, scheduler.Interval{Start: start, End: end})
+	}
+	return p, nil
+}
+
+// bookScheduledSlot creates the event for a slot and invites everyone
+func bookScheduledSlot(client *api.Client, slot scheduler.Slot, tz string, required, optional, externalRequired, externalOptional []string) (*api.Event, error) {
+	cal, err := resolveCalendar(client)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &api.CreateEventRequest{
+		Summary:         scheduleSummary,
+		Description:     scheduleDescription,
+		StartTime:       &api.TimeInfo{Timestamp: strconv.FormatInt(slot.Start.Unix(), 10), Timezone: tz},
+		EndTime:         &api.TimeInfo{Timestamp: strconv.FormatInt(slot.End.Unix(), 10), Timezone: tz},
+		AttendeeAbility: "can_invite_others",
+	}
+	if minutes := config.Get().Defaults.ReminderMinutes; minutes > 0 {
+		req.Reminders = []api.Reminder{{Minutes: minutes}}
+	}
+	if scheduleNoNotify {
+		noNotify := false
+		req.NeedNotify = &noNotify
+	}
+
+	event, err := client.CreateEvent(cal.CalendarID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var attendees []api.Attendee
+	for _, id := range required {
+		attendees = append(attendees, api.Attendee{Type: "user", UserID: id})
+	}
+	for _, id := range optional {
+		attendees = append(attendees, api.Attendee{Type: "user", UserID: id, IsOptional: true})
+	}
+	for _, email := range externalRequired {
+		attendees = append(attendees, api.Attendee{Type: "third_party", ThirdPartyEmail: email})
+	}
+	for _, email := range externalOptional {
+		attendees = append(attendees, api.Attendee{Type: "third_party", ThirdPartyEmail: email, IsOptional: true})
+	}
+	if len(attendees) > 0 {
+		added, err := client.CreateEventAttendees(cal.CalendarID, event.EventID, attendees, !scheduleNoNotify)
+		if err != nil {
+			return nil, fmt.Errorf("event %s created but attendees failed: %w", event.EventID, err)
+		}
+		event.Attendees = added
+	}
+	return event, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -5,14 +5,16 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/calendar"
 	"github.com/yjwong/lark-cli/internal/config"
 	"github.com/yjwong/lark-cli/internal/output"
 	timex "github.com/yjwong/lark-cli/internal/time"
 )
 
 var (
-	searchFrom string
-	searchTo   string
+	searchFrom    string
+	searchTo      string
+	searchOffline bool
 )
 
 var searchCmd = &cobra.Command{
@@ -22,17 +24,14 @@ var searchCmd = &cobra.Command{
 
 Examples:
   lark cal search "standup"
-  lark cal search "1:1" --from 2026-01-01 --to 2026-01-31`,
+  lark cal search "1:1" --from 2026-01-01 --to 2026-01-31
+  lark cal search "standup" --offline
+
+With --offline, matches summaries, descriptions and locations in the local
+cache (see 'lark cal sync') without calling the API.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		query := args[0]
-		client := api.NewClient()
-
-		// Get primary calendar
-		cal, err := client.GetPrimaryCalendar()
-		if err != nil {
-			output.Fatal("CALENDAR_ERROR", err)
-		}
 
 		// Parse timezone
 		tz := config.GetTimezone()
@@ -65,6 +64,42 @@ Examples:
 			endTime = now.AddDate(0, 0, 30)
 		}
 
+		if searchOffline {
+			cache, err := calendar.OpenCache()
+			if err != nil {
+				output.Fatal("CACHE_ERROR", err)
+			}
+			defer cache.Close()
+
+			states, err := offlineCalendars(cache, false)
+			if err != nil {
+				output.Fatal("CACHE_ERROR", err)
+			}
+			if err := checkCachedRange(states[0], startTime, endTime, loc); err != nil {
+				output.Fatal("CACHE_ERROR", err)
+			}
+			events, err := cache.Search(states[0].CalendarID, query, startTime, endTime)
+			if err != nil {
+				output.Fatal("CACHE_ERROR", err)
+			}
+
+			outputEvents := api.ConvertToOutputEvents(events)
+			output.JSON(api.OutputEventList{
+				Events: outputEvents,
+				Count:  len(outputEvents),
+				Cache:  cacheInfo(states, loc),
+			})
+			return
+		}
+
+		client := api.NewClient()
+
+		// Get the selected calendar (primary unless --calendar is set)
+		cal, err := resolveCalendar(client)
+		if err != nil {
+			output.Fatal("CALENDAR_ERROR", err)
+		}
+
 		// Search events
 		events, err := client.SearchEvents(cal.CalendarID, query, startTime, endTime)
 		if err != nil {
@@ -83,4 +118,5 @@ Examples:
 func init() {
 	searchCmd.Flags().StringVar(&searchFrom, "from", "", "Start date for search range")
 	searchCmd.Flags().StringVar(&searchTo, "to", "", "End date for search range")
+	searchCmd.Flags().BoolVar(&searchOffline, "offline", false, "Search the local cache instead of the API")
 }
@@ -18,8 +18,8 @@ Example:
 		eventID := args[0]
 		client := api.NewClient()
 
-		// Get primary calendar
-		cal, err := client.GetPrimaryCalendar()
+		// Get the selected calendar (primary unless --calendar is set)
+		cal, err := resolveCalendar(client)
 		if err != nil {
 			output.Fatal("CALENDAR_ERROR", err)
 		}
@@ -39,6 +39,14 @@ Example:
 			// Silently ignore errors - use partial list from GetEvent
 		}
 
+		// Occurrences don't carry the RRULE; take it from the series
+		if event.Recurrence == "" && event.RecurringEventID != "" {
+			series, err := client.GetEvent(cal.CalendarID, event.RecurringEventID)
+			if err == nil && series != nil {
+				event.Recurrence = series.Recurrence
+			}
+		}
+
 		// Convert to output format
 		outputEvent := api.ConvertToOutputEvent(*event)
 		output.JSON(outputEvent)
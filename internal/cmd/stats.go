@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/calendar"
+	"github.com/yjwong/lark-cli/internal/config"
+	"github.com/yjwong/lark-cli/internal/output"
+	"github.com/yjwong/lark-cli/internal/stats"
+	timex "github.com/yjwong/lark-cli/internal/time"
+)
+
+var (
+	statsFrom       string
+	statsTo         string
+	statsWeek       bool
+	statsFocusHours float64
+	statsWorkHours  string
+	statsTop        int
+	statsMarkdown   bool
+	statsOffline    bool
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Report meeting load for a date range",
+	Long: `Compute meeting statistics from your calendar.
+
+Reports total meeting hours (and busy hours, counting overlaps once), hours
+per day and weekday, focus blocks of at least --focus-hours within working
+hours, top co-attendees, recurring versus ad-hoc meetings, meetings you
+organize versus attend, and invitations awaiting your RSVP.
+
+Declined events, all-day events and events shown as free are not counted.
+By default, covers the current week (Monday to Sunday).
+
+With --offline, reads events from the local cache (see 'lark cal sync').
+Attendees and RSVPs then come from the cached attendee lists, without
+expanding group chats.
+
+Examples:
+  lark cal stats
+  lark cal stats --from 2026-01-05 --to 2026-01-09
+  lark cal stats --from "last monday" --to "last friday" --markdown
+  lark cal stats --week --focus-hours 3 --work-hours 10:00-19:00
+  lark cal stats --offline`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if statsFocusHours <= 0 {
+			output.Fatalf("VALIDATION_ERROR", "--focus-hours must be positive")
+		}
+		if statsWorkHours != "" {
+			if _, _, err := timex.ParseClockRange(statsWorkHours); err != nil {
+				output.Fatal("VALIDATION_ERROR", err)
+			}
+		}
+
+		// Determine time range
+		tz := config.GetTimezone()
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			loc = time.Local
+		}
+		now := time.Now().In(loc)
+
+		startTime := timex.StartOfWeek(now)
+		endTime := timex.EndOfWeek(now)
+		if !statsWeek && statsFrom != "" {
+			startTime, err = timex.Parse(statsFrom, loc)
+			if err != nil {
+				output.Fatalf("PARSE_ERROR", "Failed to parse --from: %v", err)
+			}
+			startTime = timex.StartOfDay(startTime)
+		}
+		if !statsWeek && statsTo != "" {
+			endTime, err = timex.Parse(statsTo, loc)
+			if err != nil {
+				output.Fatalf("PARSE_ERROR", "Failed to parse --to: %v", err)
+			}
+			endTime = timex.EndOfDay(endTime)
+		}
+		if !endTime.After(startTime) {
+			output.Fatalf("VALIDATION_ERROR", "--to must be after --from")
+		}
+
+		var client *api.Client // Stays nil with --offline
+		var calendarID, selfID string
+		var events []api.Event
+		var cacheUsed *api.OutputCache
+		if statsOffline {
+			cache, err := calendar.OpenCache()
+			if err != nil {
+				output.Fatal("CACHE_ERROR", err)
+			}
+			defer cache.Close()
+
+			states, err := offlineCalendars(cache, false)
+			if err != nil {
+				output.Fatal("CACHE_ERROR", err)
+			}
+			events, _, err = cachedCalendarEvents(cache, states, startTime, endTime, loc)
+			if err != nil {
+				output.Fatal("CACHE_ERROR", err)
+			}
+			selfID, err = cache.GetMeta(cacheUserKey)
+			if err != nil {
+				output.Fatal("CACHE_ERROR", err)
+			}
+			calendarID = states[0].CalendarID
+			cacheUsed = cacheInfo(states, loc)
+		} else {
+			client = api.NewClient()
+
+			// Get the selected calendar (primary unless --calendar is set)
+			cal, err := resolveCalendar(client)
+			if err != nil {
+				output.Fatal("CALENDAR_ERROR", err)
+			}
+			calendarID = cal.CalendarID
+
+			events, err = client.ListEvents(api.ListEventsOptions{
+				CalendarID: cal.CalendarID,
+				StartTime:  startTime,
+				EndTime:    endTime,
+			})
+			if err != nil {
+				output.Fatal("API_ERROR", err)
+			}
+
+			currentUser, err := client.GetCurrentUser()
+			if err != nil {
+				output.Fatal("USER_ERROR", err)
+			}
+			selfID = currentUser.OpenID
+		}
+
+		wh, err := loadWorkingHours(client, statsWorkHours, startTime, endTime)
+		if err != nil {
+			output.Fatal("CONFIG_ERROR", err)
+		}
+
+		var meetings []stats.Meeting
+		for _, e := range events {
+			if e.StartTime == nil || e.StartTime.Date != "" || e.FreeBusyStatus == "free" || e.Status == "cancelled" {
+				continue
+			}
+
+			if client != nil {
+				attendees, err := client.ListEventAttendees(calendarID, e.EventID)
+				if err == nil {
+					e.Attendees = attendees
+				}
+			}
+			rsvp := api.ExtractUserRsvpStatus(e, selfID, calendarID, client)
+			if rsvp == "decline" {
+				continue
+			}
+
+			meetings = append(meetings, statsMeeting(e, selfID, calendarID, rsvp, loc))
+		}
+
+		report := stats.Compute(meetings, stats.Options{
+			From:         startTime,
+			To:           endTime,
+			WorkingHours: wh,
+			FocusMin:     time.Duration(statsFocusHours * float64(time.Hour)),
+			TopN:         statsTop,
+		})
+
+		result := struct {
+			stats.Report
+			Markdown string           `json:"markdown,omitempty"`
+			Cache    *api.OutputCache `json:"cache,omitempty"`
+		}{Report: report, Cache: cacheUsed}
+		if statsMarkdown {
+			result.Markdown = stats.Markdown(report)
+		}
+		output.JSON(result)
+	},
+}
+
+func init() {
+	statsCmd.Flags().StringVar(&statsFrom, "from", "", "Start date (default: start of this week)")
+	statsCmd.Flags().StringVar(&statsTo, "to", "", "End date (default: end of this week)")
+	statsCmd.Flags().BoolVar(&statsWeek, "week", false, "Report on this week")
+	statsCmd.Flags().Float64Var(&statsFocusHours, "focus-hours", 2, "Shortest meeting-free stretch counted as a focus block, in hours")
+	statsCmd.Flags().StringVar(&statsWorkHours, "work-hours", "", "Working hours used for focus blocks, HH:MM-HH:MM (default: working_hours from config)")
+	statsCmd.Flags().IntVar(&statsTop, "top", 10, "Number of top co-attendees to list")
+	statsCmd.Flags().BoolVar(&statsMarkdown, "markdown", false, "Include a markdown summary in the output")
+	statsCmd.Flags().BoolVar(&statsOffline, "offline", false, "Read events from the local cache instead of the API")
+}
+
+// statsMeeting converts an event for the stats report
+func statsMeeting(e api.Event, selfID, calendarID, rsvp string, loc *time.Location) stats.Meeting {
+	m := stats.Meeting{
+		ID:         e.EventID,
+		Summary:    e.Summary,
+		Start:      eventTime(e.StartTime, loc),
+		End:        eventTime(e.EndTime, loc),
+		Recurring:  e.Recurrence != "" || e.RecurringEventID != "",
+		Organizer:  e.OrganizerCalendarID == calendarID || len(e.Attendees) == 0,
+		RsvpStatus: rsvp,
+	}
+
+	for _, att := range e.Attendees {
+		if att.IsOrganizer && att.UserID == selfID {
+			m.Organizer = true
+		}
+		if att.RsvpStatus == "decline" || att.UserID == selfID {
+			continue
+		}
+		switch {
+		case att.Type == "user" && att.UserID != "":
+			m.Attendees = append(m.Attendees, stats.Person{ID: att.UserID, Name: att.DisplayName})
+		case att.Type == "third_party" && att.ThirdPartyEmail != "":
+			m.Attendees = append(m.Attendees, stats.Person{ID: att.ThirdPartyEmail, Name: att.DisplayName})
+		}
+	}
+	return m
+}
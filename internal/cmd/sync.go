@@ -0,0 +1,378 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/calendar"
+	"github.com/yjwong/lark-cli/internal/config"
+	"github.com/yjwong/lark-cli/internal/output"
+	timex "github.com/yjwong/lark-cli/internal/time"
+)
+
+// cacheUserKey stores the current user's open_id, for offline RSVP lookups
+const cacheUserKey = "user_open_id"
+
+// --- cal sync ---
+
+var (
+	syncAllCalendars bool
+	syncFull         bool
+	syncPastDays     int
+	syncFutureDays   int
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync events to the local cache",
+	Long: `Fetch events into the local calendar cache, used by --offline and 'lark cal changes'.
+
+The cache holds event instances from --past-days ago to --future-days ahead.
+The first sync fetches the whole window; later syncs use Lark's sync token to
+fetch only events changed since, and record them for 'lark cal changes'.
+
+Examples:
+  lark cal sync
+  lark cal sync --all-calendars
+  lark cal sync --calendar "Team Calendar" --future-days 365
+  lark cal sync --full`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := api.NewClient()
+
+		calendars, err := syncTargetCalendars(client, syncAllCalendars)
+		if err != nil {
+			output.Fatal("CALENDAR_ERROR", err)
+		}
+
+		cache, err := calendar.OpenCache()
+		if err != nil {
+			output.Fatal("SYNC_ERROR", err)
+		}
+		defer cache.Close()
+
+		results, skipped, err := syncCalendars(client, cache, calendars, &calendar.SyncOptions{
+			PastDays:   syncPastDays,
+			FutureDays: syncFutureDays,
+			Full:       syncFull,
+		})
+		if err != nil {
+			output.Fatal("SYNC_ERROR", err)
+		}
+
+		if !syncAllCalendars {
+			output.JSON(results[0])
+			return
+		}
+		output.JSON(map[string]interface{}{
+			"calendars": results,
+			"count":     len(results),
+			"skipped":   skipped,
+		})
+	},
+}
+
+// --- cal changes ---
+
+var (
+	changesSince        string
+	changesAllCalendars bool
+	changesOffline      bool
+)
+
+// changeEntry is one event in the changes report
+type changeEntry struct {
+	Change       string          `json:"change"` // created, updated, cancelled
+	ChangedAt    string          `json:"changed_at"`
+	CalendarID   string          `json:"calendar_id"`
+	CalendarName string          `json:"calendar_name,omitempty"`
+	Event        api.OutputEvent `json:"event"`
+}
+
+var changesCmd = &cobra.Command{
+	Use:   "changes",
+	Short: "Show events created, updated or cancelled since the last sync",
+	Long: `Sync the local cache, then report events created, updated or cancelled.
+
+Without --since, reports what changed since the previous sync. With --since,
+reports every change recorded by syncs from that time on. Changes are
+recorded when 'lark cal sync' (or this command) runs, so they are only as
+fine-grained as your syncs. An event changed several times is listed once
+with its latest state; one created and then edited still counts as created.
+
+Use --offline to report from the cache without syncing first.
+
+Examples:
+  lark cal changes
+  lark cal changes --since yesterday
+  lark cal changes --since "last monday" --all-calendars
+  lark cal changes --offline`,
+	Run: func(cmd *cobra.Command, args []string) {
+		tz := config.GetTimezone()
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			loc = time.Local
+		}
+
+		var since time.Time
+		if changesSince != "" {
+			since, err = timex.Parse(changesSince, loc)
+			if err != nil {
+				output.Fatalf("PARSE_ERROR", "Failed to parse --since: %v", err)
+			}
+			if !containsTimeSpec(changesSince) {
+				since = timex.StartOfDay(since)
+			}
+		}
+
+		cache, err := calendar.OpenCache()
+		if err != nil {
+			output.Fatal("CACHE_ERROR", err)
+		}
+		defer cache.Close()
+
+		if !changesOffline {
+			client := api.NewClient()
+			calendars, err := syncTargetCalendars(client, changesAllCalendars)
+			if err != nil {
+				output.Fatal("CALENDAR_ERROR", err)
+			}
+			if _, _, err := syncCalendars(client, cache, calendars, &calendar.SyncOptions{}); err != nil {
+				output.Fatal("SYNC_ERROR", err)
+			}
+		}
+
+		states, err := offlineCalendars(cache, changesAllCalendars)
+		if err != nil {
+			output.Fatal("CACHE_ERROR", err)
+		}
+
+		entries := []changeEntry{}
+		counts := map[string]int{"created": 0, "updated": 0, "cancelled": 0}
+		for _, s := range states {
+			// By default, only what the latest sync found
+			from := since
+			if from.IsZero() {
+				from = s.LastSync
+			}
+			changes, err := cache.Changes(s.CalendarID, from)
+			if err != nil {
+				output.Fatal("CACHE_ERROR", err)
+			}
+			for _, ch := range latestChanges(changes) {
+				entry := changeEntry{
+					Change:     ch.Type,
+					ChangedAt:  ch.ChangedAt.In(loc).Format(time.RFC3339),
+					CalendarID: s.CalendarID,
+					Event:      api.ConvertToOutputEvent(ch.Event),
+				}
+				if changesAllCalendars {
+					entry.CalendarName = s.Summary
+				}
+				entries = append(entries, entry)
+				counts[ch.Type]++
+			}
+		}
+
+		result := map[string]interface{}{
+			"changes":   entries,
+			"count":     len(entries),
+			"created":   counts["created"],
+			"updated":   counts["updated"],
+			"cancelled": counts["cancelled"],
+			"cache":     cacheInfo(states, loc),
+		}
+		if !since.IsZero() {
+			result["since"] = since.Format(time.RFC3339)
+		}
+		output.JSON(result)
+	},
+}
+
+func init() {
+	syncCmd.Flags().BoolVar(&syncAllCalendars, "all-calendars", false, "Sync all your calendars")
+	syncCmd.Flags().BoolVar(&syncFull, "full", false, "Refetch everything instead of only changes")
+	syncCmd.Flags().IntVar(&syncPastDays, "past-days", calendar.DefaultPastDays, "Days of past events to cache")
+	syncCmd.Flags().IntVar(&syncFutureDays, "future-days", calendar.DefaultFutureDays, "Days of upcoming events to cache")
+
+	changesCmd.Flags().StringVar(&changesSince, "since", "", "Report changes recorded since this time (default: the last sync)")
+	changesCmd.Flags().BoolVar(&changesAllCalendars, "all-calendars", false, "Report changes on all synced calendars")
+	changesCmd.Flags().BoolVar(&changesOffline, "offline", false, "Report from the cache without syncing")
+}
+
+// syncTargetCalendars returns the calendar selected with --calendar (primary
+// by default), or every readable calendar with all
+func syncTargetCalendars(client *api.Client, all bool) ([]api.Calendar, error) {
+	if all {
+		if calCalendar != "" {
+			output.Fatalf("VALIDATION_ERROR", "--calendar and --all-calendars cannot be used together")
+		}
+		return listReadableCalendars(client)
+	}
+	cal, err := resolveCalendar(client)
+	if err != nil {
+		return nil, err
+	}
+	return []api.Calendar{*cal}, nil
+}
+
+// syncCalendars syncs each calendar into the cache, with progress on stderr.
+// With several calendars, ones that fail to sync are skipped and reported.
+func syncCalendars(client *api.Client, cache *calendar.Cache, calendars []api.Calendar, opts *calendar.SyncOptions) ([]*calendar.SyncResult, []map[string]string, error) {
+	tz := config.GetTimezone()
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		loc = time.Local
+	}
+	opts.Location = loc
+	opts.Progress = os.Stderr
+
+	// Remembered so --offline can find your RSVPs without calling the API
+	if user, err := client.GetCurrentUser(); err == nil {
+		if err := cache.SetMeta(cacheUserKey, user.OpenID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	results := []*calendar.SyncResult{}
+	skipped := []map[string]string{}
+	for _, c := range calendars {
+		result, err := calendar.Sync(client, cache, c, opts)
+		if err != nil {
+			if len(calendars) > 1 {
+				skipped = append(skipped, map[string]string{"calendar_id": c.CalendarID, "error": err.Error()})
+				continue
+			}
+			return nil, nil, err
+		}
+		results = append(results, result)
+	}
+	return results, skipped, nil
+}
+
+// offlineCalendars returns the cached calendars to read with --offline: the
+// one selected with --calendar (primary by default), or all with all
+func offlineCalendars(cache *calendar.Cache, all bool) ([]calendar.CalendarState, error) {
+	states, err := cache.ListCalendarStates()
+	if err != nil {
+		return nil, err
+	}
+	if len(states) == 0 {
+		return nil, fmt.Errorf("calendar cache is empty; run 'lark cal sync' first")
+	}
+	if all {
+		if calCalendar != "" {
+			output.Fatalf("VALIDATION_ERROR", "--calendar and --all-calendars cannot be used together")
+		}
+		return states, nil
+	}
+
+	ref := strings.TrimSpace(calCalendar)
+	var matches []calendar.CalendarState
+	for _, s := range states {
+		switch {
+		case ref == "" || strings.EqualFold(ref, "primary"):
+			if s.Type == "primary" {
+				return []calendar.CalendarState{s}, nil
+			}
+		case s.CalendarID == ref:
+			return []calendar.CalendarState{s}, nil
+		case strings.EqualFold(s.Summary, ref):
+			matches = append(matches, s)
+		}
+	}
+	if len(matches) == 1 {
+		return matches, nil
+	}
+	if len(matches) > 1 {
+		return nil, fmt.Errorf("calendar name %q is ambiguous, use an ID", ref)
+	}
+	if ref == "" {
+		ref = "primary"
+	}
+	return nil, fmt.Errorf("calendar %s is not cached; run 'lark cal sync --calendar %q' first", ref, ref)
+}
+
+// cachedCalendarEvents reads events between startTime and endTime from the
+// cache, like fetchCalendarEvents does from the API. The range must lie
+// within each calendar's cached window.
+func cachedCalendarEvents(cache *calendar.Cache, states []calendar.CalendarState, startTime, endTime time.Time, loc *time.Location) ([]api.Event, []api.Calendar, error) {
+	var events []api.Event
+	var eventCalendars []api.Calendar
+	seen := make(map[string]bool)
+	for _, s := range states {
+		if err := checkCachedRange(s, startTime, endTime, loc); err != nil {
+			return nil, nil, err
+		}
+		calEvents, err := cache.Events(s.CalendarID, startTime, endTime)
+		if err != nil {
+			return nil, nil, err
+		}
+		c := api.Calendar{CalendarID: s.CalendarID, Summary: s.Summary, Type: s.Type}
+		for _, e := range calEvents {
+			if seen[e.EventID] {
+				continue
+			}
+			seen[e.EventID] = true
+			events = append(events, e)
+			eventCalendars = append(eventCalendars, c)
+		}
+	}
+	if len(states) > 1 {
+		sortEventsByStart(events, eventCalendars, loc)
+	}
+	return events, eventCalendars, nil
+}
+
+// checkCachedRange fails if a range isn't covered by a calendar's cache
+func checkCachedRange(s calendar.CalendarState, startTime, endTime time.Time, loc *time.Location) error {
+	if startTime.Before(s.WindowStart) || endTime.After(s.WindowEnd) {
+		return fmt.Errorf("%s to %s is outside the cached range for %s (%s to %s); sync with --past-days/--future-days or drop --offline",
+			startTime.In(loc).Format("2006-01-02"), endTime.In(loc).Format("2006-01-02"), s.Summary,
+			s.WindowStart.In(loc).Format("2006-01-02"), s.WindowEnd.In(loc).Format("2006-01-02"))
+	}
+	return nil
+}
+
+// cacheInfo describes the cache behind an offline result, going by the
+// least recently synced calendar
+func cacheInfo(states []calendar.CalendarState, loc *time.Location) *api.OutputCache {
+	if len(states) == 0 {
+		return nil
+	}
+	oldest := states[0].LastSync
+	for _, s := range states[1:] {
+		if s.LastSync.Before(oldest) {
+			oldest = s.LastSync
+		}
+	}
+	return &api.OutputCache{
+		LastSync:  oldest.In(loc).Format(time.RFC3339),
+		Freshness: calendar.Freshness(oldest),
+	}
+}
+
+// latestChanges collapses changes to one per event, keeping the latest
+// state. An event created within the changes stays created unless cancelled.
+func latestChanges(changes []calendar.Change) []calendar.Change {
+	byID := make(map[string]int)
+	var latest []calendar.Change
+	for _, ch := range changes {
+		i, ok := byID[ch.EventID]
+		if !ok {
+			byID[ch.EventID] = len(latest)
+			latest = append(latest, ch)
+			continue
+		}
+		if latest[i].Type == "created" && ch.Type == "updated" {
+			ch.Type = "created"
+		}
+		latest[i] = ch
+	}
+	sort.SliceStable(latest, func(a, b int) bool { return latest[a].ChangedAt.Before(latest[b].ChangedAt) })
+	return latest
+}
@@ -1,13 +1,17 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/yjwong/lark-cli/internal/api"
 	"github.com/yjwong/lark-cli/internal/config"
+	"github.com/yjwong/lark-cli/internal/directory"
 	"github.com/yjwong/lark-cli/internal/output"
 	timex "github.com/yjwong/lark-cli/internal/time"
 )
@@ -22,6 +26,11 @@ var (
 	updateVisibility      string
 	updateAttendeeAbility string
 	updateNoNotify        bool
+	updateScope           string
+	updateVC              string
+	updateAttach          []string
+	updateAddAttendees    []string
+	updateRemoveAttendees []string
 )
 
 var updateCmd = &cobra.Command{
@@ -31,19 +40,38 @@ var updateCmd = &cobra.Command{
 
 Only specified fields will be updated.
 
+For occurrences of a recurring event, --scope selects what is changed:
+  instance   only this occurrence (default)
+  following  this and all following occurrences (splits the series)
+  series     every occurrence in the series
+
 Examples:
   lark cal update abc123 --summary "New title"
   lark cal update abc123 --start "2026-01-03T10:00:00+08:00"
   lark cal update abc123 --location "New location"
   lark cal update abc123 --color "#9CA2A9"
-  lark cal update abc123 --visibility public`,
+  lark cal update abc123 --visibility public
+  lark cal update abc123_1767582000 --start "2026-01-05T11:00:00+08:00" --scope following
+  lark cal update abc123_1767582000 --summary "Renamed" --scope series
+  lark cal update abc123 --vc lark
+  lark cal update abc123 --attach notes.pdf
+  lark cal update abc123 --add-attendee bob@example.com --remove-attendee carol@example.com
+
+--vc, --attach, --add-attendee and --remove-attendee work as on create; the
+join URL is returned as join_url. New attachments are added to the existing
+ones. --remove-attendee takes an attendee ID (see 'lark cal attendee list')
+or an email, name, open_id, user_id, chat:<name>, dept:<name> or alias.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		eventID := args[0]
+		if err := validateRecurrenceScope(updateScope); err != nil {
+			output.Fatal("VALIDATION_ERROR", err)
+		}
+
 		client := api.NewClient()
 
-		// Get primary calendar
-		cal, err := client.GetPrimaryCalendar()
+		// Get the selected calendar (primary unless --calendar is set)
+		cal, err := resolveCalendar(client)
 		if err != nil {
 			output.Fatal("CALENDAR_ERROR", err)
 		}
@@ -55,38 +83,35 @@ Examples:
 			loc = time.Local
 		}
 
-		// Build update request with only provided fields
+		// Build update request with only provided fields; changed records
+		// whether any event field is set, as opposed to only attendees
 		req := &api.UpdateEventRequest{}
+		changed := false
 
 		if updateSummary != "" {
-			req.Summary = updateSummary
+			req.Summary, changed = updateSummary, true
 		}
 		if updateDescription != "" {
-			req.Description = updateDescription
+			req.Description, changed = updateDescription, true
 		}
 
-		// Handle start/end time updates
-		// Per Lark API docs: start_time and end_time must both be provided for time changes to take effect
-		if updateStart != "" || updateEnd != "" {
-			existingEvent, err := client.GetEvent(cal.CalendarID, eventID)
+		// Fetch the existing event when times change, attachments are added or a recurrence scope is involved
+		var existingEvent *api.Event
+		if updateStart != "" || updateEnd != "" || updateScope != "" || len(updateAttach) > 0 {
+			existingEvent, err = client.GetEvent(cal.CalendarID, eventID)
 			if err != nil {
 				output.Fatalf("API_ERROR", "Failed to fetch existing event: %v", err)
 			}
+		}
 
-			var currentStart, currentEnd time.Time
-			if existingEvent.StartTime != nil && existingEvent.StartTime.Timestamp != "" {
-				ts, _ := strconv.ParseInt(existingEvent.StartTime.Timestamp, 10, 64)
-				currentStart = time.Unix(ts, 0).In(loc)
-			}
-			if existingEvent.EndTime != nil && existingEvent.EndTime.Timestamp != "" {
-				ts, _ := strconv.ParseInt(existingEvent.EndTime.Timestamp, 10, 64)
-				currentEnd = time.Unix(ts, 0).In(loc)
-			}
-
+		// Handle start/end time updates
+		// Per Lark API docs: start_time and end_time must both be provided for time changes to take effect
+		var newStart, newEnd time.Time
+		if updateStart != "" || updateEnd != "" {
+			currentStart := eventTime(existingEvent.StartTime, loc)
+			currentEnd := eventTime(existingEvent.EndTime, loc)
 			duration := currentEnd.Sub(currentStart)
 
-			var newStart, newEnd time.Time
-
 			if updateStart != "" {
 				newStart, err = timex.Parse(updateStart, loc)
 				if err != nil {
@@ -118,17 +143,18 @@ Examples:
 				Timestamp: strconv.FormatInt(newEnd.Unix(), 10),
 				Timezone:  tz,
 			}
+			changed = true
 		}
 
 		if updateLocation != "" {
-			req.Location = &api.Location{Name: updateLocation}
+			req.Location, changed = &api.Location{Name: updateLocation}, true
 		}
 		if updateColor != "" {
 			color, err := parseHexColor(updateColor)
 			if err != nil {
 				output.Fatalf("VALIDATION_ERROR", "Invalid color format: %v (use hex like #9CA2A9)", err)
 			}
-			req.Color = &color
+			req.Color, changed = &color, true
 		}
 		if updateNoNotify {
 			noNotify := false
@@ -137,7 +163,7 @@ Examples:
 		if updateVisibility != "" {
 			switch updateVisibility {
 			case "default", "public", "private":
-				req.Visibility = updateVisibility
+				req.Visibility, changed = updateVisibility, true
 			default:
 				output.Fatalf("VALIDATION_ERROR", "Invalid visibility: %s (use default, public, or private)", updateVisibility)
 			}
@@ -145,25 +171,123 @@ Examples:
 		if updateAttendeeAbility != "" {
 			switch updateAttendeeAbility {
 			case "none", "can_see_others", "can_invite_others", "can_modify_event":
-				req.AttendeeAbility = updateAttendeeAbility
+				req.AttendeeAbility, changed = updateAttendeeAbility, true
 			default:
 				output.Fatalf("VALIDATION_ERROR", "Invalid attendee-ability: %s (must be none, can_see_others, can_invite_others, or can_modify_event)", updateAttendeeAbility)
 			}
 		}
 
-		// Update event
-		event, err := client.UpdateEvent(cal.CalendarID, eventID, req)
+		if updateVC != "" {
+			req.Vchat, err = parseVchat(updateVC)
+			if err != nil {
+				output.Fatal("VALIDATION_ERROR", err)
+			}
+			changed = true
+		}
+
+		// Resolve new attendees before changing anything so a bad address fails early
+		var addAttendees []api.Attendee
+		if len(updateAddAttendees) > 0 {
+			addAttendees, err = parseAttendees(client, updateAddAttendees)
+			if err != nil {
+				output.Fatalf("ATTENDEE_ERROR", "Failed to parse attendees: %v", err)
+			}
+		}
+
+		if len(updateAttach) > 0 {
+			uploaded, err := uploadAttachments(client, cal.CalendarID, updateAttach)
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					output.Fatal("FILE_ERROR", err)
+				}
+				output.Fatal("API_ERROR", err)
+			}
+			// The attachment list is replaced as a whole, so keep the existing files
+			for _, a := range existingEvent.Attachments {
+				if !a.IsDeleted {
+					req.Attachments = append(req.Attachments, api.Attachment{FileToken: a.FileToken})
+				}
+			}
+			req.Attachments = append(req.Attachments, uploaded...)
+			changed = true
+		}
+
+		targetID := eventID
+		switch updateScope {
+		case scopeFollowing:
+			if existingEvent.RecurringEventID == "" {
+				output.Fatalf("VALIDATION_ERROR", "--scope following requires an occurrence of a recurring event")
+			}
+			series, err := client.GetEvent(cal.CalendarID, existingEvent.RecurringEventID)
+			if err != nil {
+				output.Fatalf("API_ERROR", "Failed to fetch recurring series: %v", err)
+			}
+
+			splitAt := eventTime(existingEvent.StartTime, loc)
+			if !eventTime(series.StartTime, loc).Before(splitAt) {
+				// First occurrence: "this and following" is the whole series
+				targetID = series.EventID
+				break
+			}
+			// Splitting ends the original series, so don't do it for a no-op
+			if !changed && len(addAttendees) == 0 && len(updateRemoveAttendees) == 0 {
+				output.Fatalf("VALIDATION_ERROR", "Nothing to update: --scope following needs at least one field or attendee change")
+			}
+
+			// The new series starts at this occurrence unless the times were changed
+			createReq := createRequestFromEvent(series)
+			createReq.StartTime = existingEvent.StartTime
+			createReq.EndTime = existingEvent.EndTime
+			applyUpdateRequest(createReq, req)
+
+			created, err := splitSeries(client, cal.CalendarID, series, splitAt, createReq)
+			if err != nil {
+				output.Fatal("API_ERROR", err)
+			}
+			added, removed := reconcileAttendees(client, cal.CalendarID, created, addAttendees, updateRemoveAttendees, !updateNoNotify)
+			ensureMeetingURL(client, cal.CalendarID, created)
+
+			result := updateResult(created, fmt.Sprintf("Series %s ended before %s; following occurrences moved to %s", series.EventID, splitAt.Format(time.RFC3339), created.EventID), added, removed)
+			result["previous_series"] = series.EventID
+			output.JSON(result)
+			return
+		case scopeSeries:
+			targetID = seriesID(existingEvent)
+			if targetID != eventID && req.StartTime != nil {
+				// Times were given relative to this occurrence; shift the series by the same amount
+				series, err := client.GetEvent(cal.CalendarID, targetID)
+				if err != nil {
+					output.Fatalf("API_ERROR", "Failed to fetch recurring series: %v", err)
+				}
+				shift := newStart.Sub(eventTime(existingEvent.StartTime, loc))
+				seriesStart := eventTime(series.StartTime, loc).Add(shift)
+				req.StartTime = &api.TimeInfo{
+					Timestamp: strconv.FormatInt(seriesStart.Unix(), 10),
+					Timezone:  tz,
+				}
+				req.EndTime = &api.TimeInfo{
+					Timestamp: strconv.FormatInt(seriesStart.Add(newEnd.Sub(newStart)).Unix(), 10),
+					Timezone:  tz,
+				}
+			}
+		}
+
+		// Update event, unless only attendees are changing
+		var event *api.Event
+		if !changed && (len(addAttendees) > 0 || len(updateRemoveAttendees) > 0) {
+			event, err = client.GetEvent(cal.CalendarID, targetID)
+		} else {
+			event, err = client.UpdateEvent(cal.CalendarID, targetID, req)
+		}
 		if err != nil {
 			output.Fatal("API_ERROR", err)
 		}
 
+		added, removed := reconcileAttendees(client, cal.CalendarID, event, addAttendees, updateRemoveAttendees, !updateNoNotify)
+		ensureMeetingURL(client, cal.CalendarID, event)
+
 		// Output updated event
-		outputEvent := api.ConvertToOutputEvent(*event)
-		output.JSON(map[string]interface{}{
-			"success": true,
-			"message": fmt.Sprintf("Event updated: %s", event.EventID),
-			"event":   outputEvent,
-		})
+		output.JSON(updateResult(event, fmt.Sprintf("Event updated: %s", event.EventID), added, removed))
 	},
 }
 
@@ -177,4 +301,124 @@ func init() {
 	updateCmd.Flags().StringVar(&updateVisibility, "visibility", "", "Event visibility (default, public, or private)")
 	updateCmd.Flags().StringVar(&updateAttendeeAbility, "attendee-ability", "", "Guest permissions (none, can_see_others, can_invite_others, can_modify_event)")
 	updateCmd.Flags().BoolVar(&updateNoNotify, "no-notify", false, "Don't send notifications")
+	updateCmd.Flags().StringVar(&updateScope, "scope", "", "For recurring events: instance, following, or series")
+	updateCmd.Flags().StringVar(&updateVC, "vc", "", "Video meeting: lark, none, or url:<link>")
+	updateCmd.Flags().StringSliceVar(&updateAttach, "attach", []string{}, "Upload and attach a file (repeatable)")
+	updateCmd.Flags().StringSliceVar(&updateAddAttendees, "add-attendee", []string{}, "Add attendee: email, name, open_id, chat:<name>, dept:<name> or alias (repeatable)")
+	updateCmd.Flags().StringSliceVar(&updateRemoveAttendees, "remove-attendee", []string{}, "Remove attendee: attendee ID, email, name, open_id, chat:<name>, dept:<name> or alias (repeatable)")
+}
+
+// reconcileAttendees adds and removes attendees on an updated event, then
+// refreshes the event's attendee list. It returns how many were added and removed.
+func reconcileAttendees(client *api.Client, calendarID string, event *api.Event, add []api.Attendee, remove []string, notify bool) (int, int) {
+	if len(add) == 0 && len(remove) == 0 {
+		return 0, 0
+	}
+
+	var removeIDs []string
+	if len(remove) > 0 {
+		current, err := client.ListEventAttendees(calendarID, event.EventID)
+		if err != nil {
+			output.Fatalf("ATTENDEE_ERROR", "Event updated, but failed to list attendees: %v", err)
+		}
+		removeIDs, err = matchAttendees(client, current, remove)
+		if err != nil {
+			output.Fatalf("ATTENDEE_ERROR", "Event updated, but %v", err)
+		}
+	}
+
+	if len(add) > 0 {
+		if _, err := client.CreateEventAttendees(calendarID, event.EventID, add, notify); err != nil {
+			output.Fatalf("ATTENDEE_ERROR", "Event updated, but failed to add attendees: %v", err)
+		}
+	}
+	if len(removeIDs) > 0 {
+		if err := client.DeleteEventAttendees(calendarID, event.EventID, removeIDs, notify); err != nil {
+			output.Fatalf("ATTENDEE_ERROR", "Event updated, but failed to remove attendees: %v", err)
+		}
+	}
+
+	if attendees, err := client.ListEventAttendees(calendarID, event.EventID); err == nil {
+		event.Attendees = attendees
+	}
+	return len(add), len(removeIDs)
+}
+
+// matchAttendees finds the attendee IDs for --remove-attendee values, given
+// the event's current attendees. A value is an attendee ID or a reference to
+// people or chats (email, name, open_id, user_id, chat:<name>, dept:<name> or
+// alias); a department matches those of its members who attend.
+func matchAttendees(client *api.Client, attendees []api.Attendee, refs []string) ([]string, error) {
+	var ids []string
+	seen := make(map[string]bool)
+	add := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	for _, ref := range refs {
+		ref = strings.TrimSpace(ref)
+		if ref == "" {
+			continue
+		}
+		found := false
+		for _, att := range attendees {
+			if att.AttendeeID == ref {
+				add(att.AttendeeID)
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+
+		people, err := resolveAttendeeRefs(client, []string{strings.TrimPrefix(ref, "email:")})
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range people {
+			for _, att := range attendees {
+				if attendeeIs(att, p) {
+					add(att.AttendeeID)
+					found = true
+				}
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%s is not an attendee of this event", ref)
+		}
+	}
+	return ids, nil
+}
+
+// attendeeIs reports whether an attendee is a resolved user, chat or email
+func attendeeIs(att api.Attendee, id directory.Identity) bool {
+	switch {
+	case id.Kind == directory.KindUser && att.UserID != "" && att.UserID == id.ID:
+		return true
+	case id.Kind == directory.KindChat && att.ChatID != "" && att.ChatID == id.ID:
+		return true
+	}
+	return id.Email != "" && att.ThirdPartyEmail != "" && strings.EqualFold(att.ThirdPartyEmail, id.Email)
+}
+
+// updateResult builds the update command's output
+func updateResult(event *api.Event, message string, added, removed int) map[string]interface{} {
+	outputEvent := api.ConvertToOutputEvent(*event)
+	result := map[string]interface{}{
+		"success": true,
+		"message": message,
+		"event":   outputEvent,
+	}
+	if outputEvent.MeetingURL != "" {
+		result["join_url"] = outputEvent.MeetingURL
+	}
+	if added > 0 || removed > 0 {
+		result["attendees_added"] = added
+		result["attendees_removed"] = removed
+	}
+	return result
 }
@@ -0,0 +1,317 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/config"
+	"github.com/yjwong/lark-cli/internal/output"
+	timex "github.com/yjwong/lark-cli/internal/time"
+)
+
+var (
+	watchBefore    []int
+	watchInterval  string
+	watchLookahead string
+	watchHook      string
+	watchOnce      bool
+)
+
+// watchNotice is one line of watch output
+type watchNotice struct {
+	Type          string             `json:"type"` // watching, reminder, added, moved, cancelled, error
+	At            string             `json:"at"`
+	MinutesBefore int                `json:"minutes_before,omitempty"`
+	Event         *api.OutputEvent   `json:"event,omitempty"`
+	Previous      *watchEventTimes   `json:"previous,omitempty"` // Old times for moved events
+	Calendar      string             `json:"calendar,omitempty"`
+	Message       string             `json:"message,omitempty"`
+	Watch         *watchNoticeConfig `json:"watch,omitempty"`
+}
+
+type watchEventTimes struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type watchNoticeConfig struct {
+	BeforeMinutes []int  `json:"before_minutes"`
+	Interval      string `json:"interval"`
+	Lookahead     string `json:"lookahead"`
+	Hook          string `json:"hook,omitempty"`
+}
+
+// watchedEvent is an event as seen in one poll
+type watchedEvent struct {
+	event api.Event
+	start time.Time
+	end   time.Time
+}
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Stream reminders and changes for upcoming events",
+	Long: `Poll your calendar and print a line of JSON (NDJSON) for each notice:
+
+  reminder   --before minutes before an event starts, with the meeting URL,
+             location and attendees
+  added      a new event appeared in the watched window
+  moved      an event's start or end time changed
+  cancelled  an upcoming event was cancelled or deleted
+
+The first line has type "watching"; polling errors are reported with type
+"error" and polling continues. Stop with Ctrl-C.
+
+With --hook (or watch.hook in config.yaml), the command is also run through
+the shell for every notice, with the notice as JSON on stdin and
+LARK_WATCH_TYPE, LARK_EVENT_ID, LARK_EVENT_SUMMARY, LARK_EVENT_START and
+LARK_MEETING_URL set in its environment.
+
+Examples:
+  lark cal watch
+  lark cal watch --before 10,1 --interval 30s
+  lark cal watch --hook 'notify-send "$LARK_EVENT_SUMMARY" "$LARK_MEETING_URL"'
+  lark cal watch --once --before 60`,
+	Run: func(cmd *cobra.Command, args []string) {
+		interval, err := timex.ParseDuration(watchInterval)
+		if err != nil {
+			output.Fatalf("PARSE_ERROR", "Failed to parse --interval: %v", err)
+		}
+		if interval < 10*time.Second {
+			output.Fatalf("VALIDATION_ERROR", "--interval must be at least 10s")
+		}
+		lookahead, err := timex.ParseDuration(watchLookahead)
+		if err != nil {
+			output.Fatalf("PARSE_ERROR", "Failed to parse --lookahead: %v", err)
+		}
+
+		before := watchBefore
+		if !cmd.Flags().Changed("before") {
+			before = []int{config.Get().Watch.BeforeMinutes}
+		}
+		for _, m := range before {
+			if m < 0 || time.Duration(m)*time.Minute > lookahead {
+				output.Fatalf("VALIDATION_ERROR", "--before must be between 0 and the lookahead (%s)", watchLookahead)
+			}
+		}
+		hook := watchHook
+		if hook == "" {
+			hook = config.Get().Watch.Hook
+		}
+
+		// Parse timezone
+		tz := config.GetTimezone()
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			loc = time.Local
+		}
+
+		client := api.NewClient()
+
+		// Get the selected calendar (primary unless --calendar is set)
+		cal, err := resolveCalendar(client)
+		if err != nil {
+			output.Fatal("CALENDAR_ERROR", err)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		emit := func(n watchNotice) {
+			n.At = time.Now().In(loc).Format(time.RFC3339)
+			output.Line(n)
+			if hook != "" && n.Type != "watching" {
+				if err := runWatchHook(hook, n); err != nil {
+					output.Line(watchNotice{Type: "error", At: n.At, Message: fmt.Sprintf("hook failed: %v", err)})
+				}
+			}
+		}
+
+		emit(watchNotice{
+			Type:     "watching",
+			Calendar: cal.CalendarID,
+			Watch: &watchNoticeConfig{
+				BeforeMinutes: before,
+				Interval:      interval.String(),
+				Lookahead:     lookahead.String(),
+				Hook:          hook,
+			},
+		})
+
+		var prev map[string]watchedEvent
+		var prevWindowEnd time.Time
+		reminded := make(map[string]time.Time) // Reminder key -> event start
+
+		for {
+			now := time.Now().In(loc)
+			windowEnd := now.Add(lookahead)
+
+			curr, err := watchSnapshot(client, cal.CalendarID, now, windowEnd, loc)
+			if err != nil {
+				emit(watchNotice{Type: "error", Message: err.Error()})
+			} else {
+				if prev != nil {
+					for _, n := range diffWatchSnapshots(prev, curr, prevWindowEnd, now) {
+						emit(n)
+					}
+				}
+
+				for _, w := range sortedWatched(curr) {
+					for _, m := range before {
+						lead := w.start.Sub(now)
+						key := fmt.Sprintf("%s@%d/%d", w.event.EventID, w.start.Unix(), m)
+						if _, done := reminded[key]; done || lead <= 0 || lead > time.Duration(m)*time.Minute {
+							continue
+						}
+						reminded[key] = w.start
+
+						// Attendees are only fetched for events about to start
+						e := w.event
+						if attendees, err := client.ListEventAttendees(cal.CalendarID, e.EventID); err == nil {
+							e.Attendees = attendees
+						}
+						out := api.ConvertToOutputEvent(e)
+						emit(watchNotice{Type: "reminder", MinutesBefore: m, Event: &out})
+					}
+				}
+
+				for key, start := range reminded {
+					if start.Before(now) {
+						delete(reminded, key)
+					}
+				}
+				prev, prevWindowEnd = curr, windowEnd
+			}
+
+			if watchOnce {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	},
+}
+
+func init() {
+	watchCmd.Flags().IntSliceVar(&watchBefore, "before", nil, "Minutes before each event to remind (default: watch.before_minutes, 5)")
+	watchCmd.Flags().StringVar(&watchInterval, "interval", "1m", "How often to poll")
+	watchCmd.Flags().StringVar(&watchLookahead, "lookahead", "24h", "How far ahead to watch for changes")
+	watchCmd.Flags().StringVar(&watchHook, "hook", "", "Shell command to run for each notice (default: watch.hook)")
+	watchCmd.Flags().BoolVar(&watchOnce, "once", false, "Poll once and exit")
+}
+
+// watchSnapshot lists timed, non-cancelled events between from and to
+func watchSnapshot(client *api.Client, calendarID string, from, to time.Time, loc *time.Location) (map[string]watchedEvent, error) {
+	events, err := client.ListEvents(api.ListEventsOptions{
+		CalendarID: calendarID,
+		StartTime:  from,
+		EndTime:    to,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := make(map[string]watchedEvent, len(events))
+	for _, e := range events {
+		if e.Status == "cancelled" || e.StartTime == nil || e.StartTime.Date != "" {
+			continue
+		}
+		snapshot[e.EventID] = watchedEvent{
+			event: e,
+			start: eventTime(e.StartTime, loc),
+			end:   eventTime(e.EndTime, loc),
+		}
+	}
+	return snapshot, nil
+}
+
+// diffWatchSnapshots compares two polls. Events only count as added if they
+// fall inside the previous window (rather than having scrolled into view),
+// and as cancelled if they vanish before they have ended.
+func diffWatchSnapshots(prev, curr map[string]watchedEvent, prevWindowEnd, now time.Time) []watchNotice {
+	var notices []watchNotice
+
+	for _, w := range sortedWatched(curr) {
+		old, existed := prev[w.event.EventID]
+		switch {
+		case !existed && w.start.Before(prevWindowEnd):
+			out := api.ConvertToOutputEvent(w.event)
+			notices = append(notices, watchNotice{Type: "added", Event: &out})
+		case existed && (!old.start.Equal(w.start) || !old.end.Equal(w.end)):
+			out := api.ConvertToOutputEvent(w.event)
+			oldOut := api.ConvertToOutputEvent(old.event)
+			notices = append(notices, watchNotice{
+				Type:     "moved",
+				Event:    &out,
+				Previous: &watchEventTimes{Start: oldOut.Start, End: oldOut.End},
+			})
+		}
+	}
+
+	for _, old := range sortedWatched(prev) {
+		if _, ok := curr[old.event.EventID]; ok || !old.end.After(now) {
+			continue
+		}
+		out := api.ConvertToOutputEvent(old.event)
+		notices = append(notices, watchNotice{Type: "cancelled", Event: &out})
+	}
+	return notices
+}
+
+// sortedWatched returns a snapshot's events in start order
+func sortedWatched(snapshot map[string]watchedEvent) []watchedEvent {
+	list := make([]watchedEvent, 0, len(snapshot))
+	for _, w := range snapshot {
+		list = append(list, w)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].start.Equal(list[j].start) {
+			return list[i].start.Before(list[j].start)
+		}
+		return list[i].event.EventID < list[j].event.EventID
+	})
+	return list
+}
+
+// runWatchHook runs the hook command through the shell with the notice on stdin
+func runWatchHook(hook string, n watchNotice) error {
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.Command("cmd", "/c", hook)
+	} else {
+		c = exec.Command("sh", "-c", hook)
+	}
+
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	c.Stdin = bytes.NewReader(payload)
+	c.Stdout = os.Stderr // Keep stdout clean for NDJSON
+	c.Stderr = os.Stderr
+
+	c.Env = append(os.Environ(), "LARK_WATCH_TYPE="+n.Type)
+	if n.Event != nil {
+		c.Env = append(c.Env,
+			"LARK_EVENT_ID="+n.Event.ID,
+			"LARK_EVENT_SUMMARY="+n.Event.Summary,
+			"LARK_EVENT_START="+n.Event.Start,
+			"LARK_MEETING_URL="+n.Event.MeetingURL,
+		)
+	}
+	return c.Run()
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/config"
+	timex "github.com/yjwong/lark-cli/internal/time"
+)
+
+// loadWorkingHours builds the user's working week from the working_hours and
+// holidays config. hours overrides working_hours.hours when set. Holidays are
+// the configured dates plus all-day events on the holiday calendar between
+// from and to; the calendar is skipped when client is nil (offline).
+func loadWorkingHours(client *api.Client, hours string, from, to time.Time) (timex.WorkingHours, error) {
+	cfg := config.GetWorkingHours()
+
+	tz := cfg.Timezone
+	if tz == "" {
+		tz = config.GetTimezone()
+	}
+	loc, err := timex.LoadLocation(tz)
+	if err != nil {
+		return timex.WorkingHours{}, fmt.Errorf("working_hours.timezone: %w", err)
+	}
+	wh := timex.DefaultWorkingHours(loc)
+
+	if hours == "" {
+		hours = cfg.Hours
+	}
+	if hours != "" {
+		if wh.Start, wh.End, err = timex.ParseClockRange(hours); err != nil {
+			return timex.WorkingHours{}, err
+		}
+	}
+	if cfg.Days != "" {
+		if wh.Days, err = timex.ParseWeekdays(cfg.Days); err != nil {
+			return timex.WorkingHours{}, fmt.Errorf("working_hours.days: %w", err)
+		}
+	}
+
+	holidays := config.GetHolidays()
+	for _, date := range holidays.Dates {
+		d, err := time.ParseInLocation("2006-01-02", date, loc)
+		if err != nil {
+			return timex.WorkingHours{}, fmt.Errorf("holidays.dates: invalid date %q (use YYYY-MM-DD)", date)
+		}
+		wh.AddHoliday(d, d.AddDate(0, 0, 1))
+	}
+
+	if holidays.Calendar != "" && client != nil {
+		cal, err := findCalendar(client, holidays.Calendar)
+		if err != nil {
+			return timex.WorkingHours{}, fmt.Errorf("holidays.calendar: %w", err)
+		}
+		events, _, err := fetchCalendarEvents(client, []api.Calendar{*cal}, from, to, loc)
+		if err != nil {
+			return timex.WorkingHours{}, fmt.Errorf("failed to list holidays: %w", err)
+		}
+		for _, e := range events {
+			if e.StartTime == nil || e.StartTime.Date == "" || e.Status == "cancelled" {
+				continue // Only all-day events are days off
+			}
+			wh.AddHoliday(eventTime(e.StartTime, loc), eventTime(e.EndTime, loc))
+		}
+	}
+
+	return wh, nil
+}
+
+// withinHolidayDays is how far ahead holidays are read for a --within range,
+// the longest range any command accepts
+const withinHolidayDays = 90
+
+// parseWithin parses a --within range, counting "next N working days" with
+// the configured working week and holidays
+func parseWithin(client *api.Client, within string, now time.Time) (time.Time, time.Time, error) {
+	wh, err := loadWorkingHours(client, "", now, now.AddDate(0, 0, withinHolidayDays))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return timex.ParseRange(within, now, wh)
+}
@@ -20,7 +20,30 @@ type Config struct {
 	OAuth struct {
 		RedirectPort int `mapstructure:"redirect_port"`
 	} `mapstructure:"oauth"`
+	Watch struct {
+		Hook          string `mapstructure:"hook"`
+		BeforeMinutes int    `mapstructure:"before_minutes"`
+	} `mapstructure:"watch"`
+	Directory struct {
+		TTL string `mapstructure:"ttl"`
+	} `mapstructure:"directory"`
+	WorkingHours WorkingHours      `mapstructure:"working_hours"`
+	Holidays     Holidays          `mapstructure:"holidays"`
 	CustomEmojis map[string]string `mapstructure:"custom_emojis"`
+	Aliases      map[string]string `mapstructure:"aliases"`
+}
+
+// WorkingHours is the user's working week
+type WorkingHours struct {
+	Hours    string `mapstructure:"hours"`    // e.g. 09:00-18:00
+	Days     string `mapstructure:"days"`     // e.g. mon-fri, sun-thu, mon,tue,thu
+	Timezone string `mapstructure:"timezone"` // IANA zone; defaults.timezone when empty
+}
+
+// Holidays are the user's days off
+type Holidays struct {
+	Calendar string   `mapstructure:"calendar"` // Calendar ID or name whose all-day events are days off
+	Dates    []string `mapstructure:"dates"`    // Extra days off, YYYY-MM-DD
 }
 
 var (
@@ -66,6 +89,10 @@ func Init() error {
 	viper.SetDefault("defaults.timezone", "Asia/Singapore")
 	viper.SetDefault("defaults.reminder_minutes", 15)
 	viper.SetDefault("oauth.redirect_port", 9999)
+	viper.SetDefault("watch.before_minutes", 5)
+	viper.SetDefault("working_hours.hours", "09:00-18:00")
+	viper.SetDefault("working_hours.days", "mon-fri")
+	viper.SetDefault("directory.ttl", "168h")
 
 	// Environment variable bindings
 	viper.SetEnvPrefix("LARK")
@@ -124,6 +151,28 @@ func GetTimezone() string {
 	return viper.GetString("defaults.timezone")
 }
 
+// GetWorkingHours returns the working_hours section
+func GetWorkingHours() WorkingHours {
+	return WorkingHours{
+		Hours:    viper.GetString("working_hours.hours"),
+		Days:     viper.GetString("working_hours.days"),
+		Timezone: viper.GetString("working_hours.timezone"),
+	}
+}
+
+// GetHolidays returns the holidays section
+func GetHolidays() Holidays {
+	return Holidays{
+		Calendar: viper.GetString("holidays.calendar"),
+		Dates:    viper.GetStringSlice("holidays.dates"),
+	}
+}
+
+// GetDirectoryTTL returns how long cached directory entries stay fresh, e.g. 168h
+func GetDirectoryTTL() string {
+	return viper.GetString("directory.ttl")
+}
+
 // GetRedirectPort returns the OAuth redirect port
 func GetRedirectPort() int {
 	return viper.GetInt("oauth.redirect_port")
@@ -143,3 +192,9 @@ func TenantTokensFilePath() string {
 func GetCustomEmojis() map[string]string {
 	return viper.GetStringMapString("custom_emojis")
 }
+
+// GetAliases returns the user-defined names for people, chats and
+// departments, keyed by lowercased alias
+func GetAliases() map[string]string {
+	return viper.GetStringMapString("aliases")
+}
@@ -9,10 +9,34 @@ import (
 
 // EventTimeSlot represents parsed time boundaries for an event
 type EventTimeSlot struct {
-	ID     string
-	Start  time.Time
-	End    time.Time
-	AllDay bool
+	ID         string
+	Start      time.Time
+	End        time.Time
+	AllDay     bool
+	RsvpStatus string // User's RSVP; empty is treated as accepted
+	Free       bool   // Shown as free, so it never blocks other events
+}
+
+// rsvpWeights is how firmly each RSVP status commits the user's time.
+// Declined events carry no weight and never conflict.
+var rsvpWeights = map[string]float64{
+	"":             1,
+	"accept":       1,
+	"needs_action": 0.75,
+	"tentative":    0.5,
+	"decline":      0,
+}
+
+// Weight returns how firmly the slot occupies the user's time, from 0 to 1
+func (s EventTimeSlot) Weight() float64 {
+	if s.Free {
+		return 0
+	}
+	w, ok := rsvpWeights[s.RsvpStatus]
+	if !ok {
+		return 1
+	}
+	return w
 }
 
 // Options configures conflict detection
@@ -33,8 +57,10 @@ func ParseEventTimes(events []api.OutputEvent, loc *time.Location) ([]EventTimeS
 
 	for _, e := range events {
 		slot := EventTimeSlot{
-			ID:     e.ID,
-			AllDay: e.AllDay,
+			ID:         e.ID,
+			AllDay:     e.AllDay,
+			RsvpStatus: e.RsvpStatus,
+			Free:       e.FreeBusy == "free",
 		}
 
 		if e.AllDay {
@@ -70,44 +96,52 @@ func ParseEventTimes(events []api.OutputEvent, loc *time.Location) ([]EventTimeS
 	return slots, nil
 }
 
-// Detect finds all conflicts in the given events
+// Detect finds all conflicts in the given events. Free and declined events
+// are ignored; the severity of each conflict reflects the RSVP status of both
+// events. Slots are swept in start order, so each event is only compared with
+// the events still running (plus buffer) when it starts.
 func Detect(slots []EventTimeSlot, opts Options) Result {
 	result := Result{
 		ConflictMap: make(map[string][]string),
 		Conflicts:   []api.Conflict{},
 	}
 
-	if len(slots) < 2 {
+	var blocking []EventTimeSlot
+	for _, s := range slots {
+		if s.Weight() > 0 {
+			blocking = append(blocking, s)
+		}
+	}
+	if len(blocking) < 2 {
 		return result
 	}
 
 	// Sort by start time
-	sort.Slice(slots, func(i, j int) bool {
-		if slots[i].Start.Equal(slots[j].Start) {
-			return slots[i].End.Before(slots[j].End)
+	sort.Slice(blocking, func(i, j int) bool {
+		if blocking[i].Start.Equal(blocking[j].Start) {
+			return blocking[i].End.Before(blocking[j].End)
 		}
-		return slots[i].Start.Before(slots[j].Start)
+		return blocking[i].Start.Before(blocking[j].Start)
 	})
 
-	// Compare each pair
-	for i := 0; i < len(slots); i++ {
-		for j := i + 1; j < len(slots); j++ {
-			a, b := slots[i], slots[j]
-
-			// Optimization: if b starts after a ends + buffer, no more conflicts for a
-			bufferDuration := time.Duration(opts.BufferMinutes) * time.Minute
-			if b.Start.After(a.End.Add(bufferDuration)) || b.Start.Equal(a.End.Add(bufferDuration)) {
-				break
+	bufferDuration := time.Duration(opts.BufferMinutes) * time.Minute
+	var active []EventTimeSlot
+	for _, b := range blocking {
+		// Drop events that ended (with buffer) before this one starts
+		kept := active[:0]
+		for _, a := range active {
+			if a.End.Add(bufferDuration).After(b.Start) {
+				kept = append(kept, a)
 			}
+		}
+		active = kept
 
+		for _, a := range active {
 			// Check for overlap: a.Start < b.End AND b.Start < a.End
 			// Note: events ending exactly when another starts are NOT conflicts
 			if a.Start.Before(b.End) && b.Start.Before(a.End) {
 				// Calculate overlap duration
-				overlapStart := a.Start
-				if b.Start.After(a.Start) {
-					overlapStart = b.Start
-				}
+				overlapStart := b.Start
 				overlapEnd := a.End
 				if b.End.Before(a.End) {
 					overlapEnd = b.End
@@ -117,6 +151,7 @@ func Detect(slots []EventTimeSlot, opts Options) Result {
 				result.Conflicts = append(result.Conflicts, api.Conflict{
 					Type:           "overlap",
 					EventIDs:       []string{a.ID, b.ID},
+					Severity:       severity(a.Weight() * b.Weight()),
 					OverlapMinutes: overlapMinutes,
 				})
 				addToConflictMap(result.ConflictMap, a.ID, b.ID)
@@ -127,6 +162,7 @@ func Detect(slots []EventTimeSlot, opts Options) Result {
 					result.Conflicts = append(result.Conflicts, api.Conflict{
 						Type:                  "insufficient_buffer",
 						EventIDs:              []string{a.ID, b.ID},
+						Severity:              "low",
 						GapMinutes:            int(gap.Minutes()),
 						RequiredBufferMinutes: opts.BufferMinutes,
 					})
@@ -134,12 +170,27 @@ func Detect(slots []EventTimeSlot, opts Options) Result {
 				}
 			}
 		}
+
+		active = append(active, b)
 	}
 
 	result.HasConflicts = len(result.Conflicts) > 0
 	return result
 }
 
+// severity grades an overlap by the combined weight of both events: two
+// accepted events are high, anything tentative is lower
+func severity(weight float64) string {
+	switch {
+	case weight >= 0.75:
+		return "high"
+	case weight >= 0.375:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
 // ApplyToEvents adds conflict information to OutputEvents
 func ApplyToEvents(events []api.OutputEvent, result Result) {
 	for i := range events {
@@ -0,0 +1,142 @@
+package conflicts
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yjwong/lark-cli/internal/api"
+)
+
+// Interval is a period of time
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// FreeSlotFinder returns the free periods between from and to in which the
+// given event could be held, e.g. the common free time of its attendees
+type FreeSlotFinder func(eventID string, from, to time.Time) ([]Interval, error)
+
+// SuggestOptions configures resolution suggestions
+type SuggestOptions struct {
+	FindFree FreeSlotFinder // Optional; without it only declines are suggested
+	Window   time.Duration  // How far either side of the event to look for a new time
+	Now      time.Time      // New times are never proposed before this
+}
+
+// Suggest attaches resolution suggestions to each conflict in result. The
+// event with the weaker RSVP (or the later one, on a tie) is the one to move
+// or decline; moves go to the nearest free period that fits the event.
+func Suggest(result *Result, slots []EventTimeSlot, opts SuggestOptions) {
+	byID := make(map[string]EventTimeSlot, len(slots))
+	for _, s := range slots {
+		byID[s.ID] = s
+	}
+
+	// Free periods are looked up once per event
+	freeCache := make(map[string][]Interval)
+
+	for i := range result.Conflicts {
+		c := &result.Conflicts[i]
+		a, okA := byID[c.EventIDs[0]]
+		b, okB := byID[c.EventIDs[1]]
+		if !okA || !okB {
+			continue
+		}
+
+		target, other := b, a
+		if a.Weight() < b.Weight() {
+			target, other = a, b
+		}
+		if target.AllDay && !other.AllDay {
+			target, other = other, target
+		}
+
+		if target.RsvpStatus == "tentative" || target.RsvpStatus == "needs_action" {
+			c.Suggestions = append(c.Suggestions, api.ConflictSuggestion{
+				Action:  "decline",
+				EventID: target.ID,
+				Reason:  fmt.Sprintf("not yet accepted (%s); declining frees the time", target.RsvpStatus),
+			})
+		}
+
+		if opts.FindFree == nil || target.AllDay {
+			continue
+		}
+
+		free, ok := freeCache[target.ID]
+		if !ok {
+			from := target.Start.Add(-opts.Window)
+			if from.Before(opts.Now) {
+				from = opts.Now
+			}
+			free, _ = opts.FindFree(target.ID, from, target.End.Add(opts.Window))
+			freeCache[target.ID] = free
+		}
+
+		if start, found := nearestFit(free, target, slots, opts.Now); found {
+			duration := target.End.Sub(target.Start)
+			c.Suggestions = append(c.Suggestions, api.ConflictSuggestion{
+				Action:  "move",
+				EventID: target.ID,
+				Start:   start.Format(time.RFC3339),
+				End:     start.Add(duration).Format(time.RFC3339),
+				Reason:  "nearest time when all attendees are free",
+			})
+		}
+	}
+}
+
+// nearestFit finds the start time closest to the event's current start at
+// which it fits inside a free period without overlapping other events
+func nearestFit(free []Interval, event EventTimeSlot, slots []EventTimeSlot, now time.Time) (time.Time, bool) {
+	duration := event.End.Sub(event.Start)
+
+	var best time.Time
+	var bestDistance time.Duration
+	found := false
+	for _, iv := range free {
+		earliest := iv.Start
+		if earliest.Before(now) {
+			earliest = now
+		}
+		latest := iv.End.Add(-duration)
+		if latest.Before(earliest) {
+			continue
+		}
+
+		// Closest position to the original start within this period
+		start := event.Start
+		if start.Before(earliest) {
+			start = earliest
+		}
+		if start.After(latest) {
+			start = latest
+		}
+		if start.Equal(event.Start) || collides(slots, event.ID, start, start.Add(duration)) {
+			continue
+		}
+
+		distance := start.Sub(event.Start)
+		if distance < 0 {
+			distance = -distance
+		}
+		if !found || distance < bestDistance {
+			best, bestDistance, found = start, distance, true
+		}
+	}
+	return best, found
+}
+
+// collides reports whether start-end overlaps any blocking slot other than id
+func collides(slots []EventTimeSlot, id string, start, end time.Time) bool {
+	for _, s := range slots {
+		if s.ID == id || s.Weight() == 0 {
+			continue
+		}
+		if s.Start.Before(end) && start.Before(s.End) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,414 @@
+// Package directory keeps a local copy of the company directory and resolves
+// names, emails and open_ids against it.
+package directory
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/config"
+	_ "modernc.org/sqlite"
+)
+
+// CacheFilePath returns the path to the directory cache database
+func CacheFilePath() string {
+	return filepath.Join(config.GetConfigDir(), "directory_cache.db")
+}
+
+// Cache provides SQLite-based directory caching
+type Cache struct {
+	db *sql.DB
+}
+
+// OpenCache opens or creates the cache database
+func OpenCache() (*Cache, error) {
+	path := CacheFilePath()
+
+	// Wait for other processes' writes instead of failing with SQLITE_BUSY,
+	// and share one connection so goroutines queue rather than contend
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("opening cache database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	cache := &Cache{db: db}
+	if err := cache.init(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return cache, nil
+}
+
+// Close closes the cache database
+func (c *Cache) Close() error {
+	if c.db != nil {
+		return c.db.Close()
+	}
+	return nil
+}
+
+func (c *Cache) init() error {
+	schema := `
+		CREATE TABLE IF NOT EXISTS users (
+			open_id TEXT PRIMARY KEY,
+			name TEXT NOT NULL DEFAULT '',
+			email TEXT NOT NULL DEFAULT '',
+			partial INTEGER NOT NULL DEFAULT 0,
+			fetched_at INTEGER NOT NULL,
+			data TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS departments (
+			department_id TEXT PRIMARY KEY,
+			parent_id TEXT NOT NULL DEFAULT '',
+			name TEXT NOT NULL DEFAULT '',
+			fetched_at INTEGER NOT NULL,
+			data TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS identities (
+			ref TEXT PRIMARY KEY,
+			fetched_at INTEGER NOT NULL,
+			data TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS misses (
+			open_id TEXT PRIMARY KEY,
+			fetched_at INTEGER NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS meta (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
+		CREATE INDEX IF NOT EXISTS idx_departments_parent ON departments(parent_id);
+	`
+
+	_, err := c.db.Exec(schema)
+	if err != nil {
+		return fmt.Errorf("initializing cache schema: %w", err)
+	}
+
+	return nil
+}
+
+// User is a cached directory entry
+type User struct {
+	api.ContactUser
+	Partial   bool // Only the name and departments are known (from user search)
+	FetchedAt time.Time
+}
+
+// Department is a cached department
+type Department struct {
+	api.Department
+	FetchedAt time.Time
+}
+
+// PutUsers stores full user records
+func (c *Cache) PutUsers(users []api.ContactUser, at time.Time) error {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, u := range users {
+		if u.OpenID == "" {
+			continue
+		}
+		data, err := json.Marshal(u)
+		if err != nil {
+			return fmt.Errorf("encoding user %s: %w", u.OpenID, err)
+		}
+		email := u.Email
+		if email == "" {
+			email = u.EnterpriseEmail
+		}
+		_, err = tx.Exec(
+			`INSERT OR REPLACE INTO users (open_id, name, email, partial, fetched_at, data) VALUES (?, ?, ?, 0, ?, ?)`,
+			u.OpenID, u.Name, email, at.Unix(), string(data),
+		)
+		if err != nil {
+			return fmt.Errorf("storing user %s: %w", u.OpenID, err)
+		}
+		if _, err := tx.Exec(`DELETE FROM misses WHERE open_id = ?`, u.OpenID); err != nil {
+			return fmt.Errorf("storing user %s: %w", u.OpenID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// PutSearchResults stores users found by user search. Search results only
+// carry a name and departments, so they never replace a full record.
+func (c *Cache) PutSearchResults(results []api.SearchUserResult, at time.Time) error {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, r := range results {
+		if r.OpenID == "" {
+			continue
+		}
+		data, err := json.Marshal(api.ContactUser{
+			OpenID:        r.OpenID,
+			UserID:        r.UserID,
+			Name:          r.Name,
+			DepartmentIDs: r.DepartmentIDs,
+		})
+		if err != nil {
+			return fmt.Errorf("encoding user %s: %w", r.OpenID, err)
+		}
+		_, err = tx.Exec(
+			`INSERT INTO users (open_id, name, partial, fetched_at, data) VALUES (?, ?, 1, ?, ?)
+			 ON CONFLICT(open_id) DO UPDATE SET name = excluded.name, fetched_at = excluded.fetched_at, data = excluded.data
+			 WHERE users.partial = 1`,
+			r.OpenID, r.Name, at.Unix(), string(data),
+		)
+		if err != nil {
+			return fmt.Errorf("storing user %s: %w", r.OpenID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetUser returns a cached user, or nil if the user isn't cached
+func (c *Cache) GetUser(openID string) (*User, error) {
+	users, err := c.queryUsers(`WHERE open_id = ?`, openID)
+	if err != nil || len(users) == 0 {
+		return nil, err
+	}
+	return &users[0], nil
+}
+
+// FindUserByEmail returns the cached user with an email address, or nil
+func (c *Cache) FindUserByEmail(email string) (*User, error) {
+	users, err := c.queryUsers(`WHERE email = ? COLLATE NOCASE`, email)
+	if err != nil || len(users) == 0 {
+		return nil, err
+	}
+	return &users[0], nil
+}
+
+// ListUsers returns every cached user, by name
+func (c *Cache) ListUsers() ([]User, error) {
+	return c.queryUsers(`ORDER BY name`)
+}
+
+func (c *Cache) queryUsers(clause string, args ...any) ([]User, error) {
+	rows, err := c.db.Query(`SELECT partial, fetched_at, data FROM users `+clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		var u User
+		var fetchedAt int64
+		var data string
+		if err := rows.Scan(&u.Partial, &fetchedAt, &data); err != nil {
+			return nil, fmt.Errorf("scanning user: %w", err)
+		}
+		if err := json.Unmarshal([]byte(data), &u.ContactUser); err != nil {
+			return nil, fmt.Errorf("decoding user: %w", err)
+		}
+		u.FetchedAt = time.Unix(fetchedAt, 0)
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+// PutMisses records open_ids the API returned no user for, such as external
+// users and people who have left
+func (c *Cache) PutMisses(openIDs []string, at time.Time) error {
+	if len(openIDs) == 0 {
+		return nil
+	}
+	tx, err := c.db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, id := range openIDs {
+		if _, err := tx.Exec(`INSERT OR REPLACE INTO misses (open_id, fetched_at) VALUES (?, ?)`, id, at.Unix()); err != nil {
+			return fmt.Errorf("storing miss %s: %w", id, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// MissedAt returns when an open_id was last found not to exist (zero if it
+// never was)
+func (c *Cache) MissedAt(openID string) (time.Time, error) {
+	var ts int64
+	err := c.db.QueryRow(`SELECT fetched_at FROM misses WHERE open_id = ?`, openID).Scan(&ts)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("reading miss: %w", err)
+	}
+	return time.Unix(ts, 0), nil
+}
+
+// PruneUsers drops full user records last fetched before a time, such as
+// people who have left since the previous full sync
+func (c *Cache) PruneUsers(before time.Time) (int, error) {
+	res, err := c.db.Exec(`DELETE FROM users WHERE partial = 0 AND fetched_at < ?`, before.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("pruning users: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
+
+// PutDepartments stores departments
+func (c *Cache) PutDepartments(depts []api.Department, at time.Time) error {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, d := range depts {
+		if d.OpenDepartmentID == "" {
+			continue
+		}
+		data, err := json.Marshal(d)
+		if err != nil {
+			return fmt.Errorf("encoding department %s: %w", d.OpenDepartmentID, err)
+		}
+		_, err = tx.Exec(
+			`INSERT OR REPLACE INTO departments (department_id, parent_id, name, fetched_at, data) VALUES (?, ?, ?, ?, ?)`,
+			d.OpenDepartmentID, d.ParentDepartmentID, d.Name, at.Unix(), string(data),
+		)
+		if err != nil {
+			return fmt.Errorf("storing department %s: %w", d.OpenDepartmentID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetDepartment returns a cached department, or nil if it isn't cached
+func (c *Cache) GetDepartment(id string) (*Department, error) {
+	depts, err := c.queryDepartments(`WHERE department_id = ?`, id)
+	if err != nil || len(depts) == 0 {
+		return nil, err
+	}
+	return &depts[0], nil
+}
+
+// FindDepartmentsByName returns the cached departments with a name,
+// ignoring case
+func (c *Cache) FindDepartmentsByName(name string) ([]Department, error) {
+	return c.queryDepartments(`WHERE name = ? COLLATE NOCASE`, name)
+}
+
+// ListDepartments returns every cached department, by name
+func (c *Cache) ListDepartments() ([]Department, error) {
+	return c.queryDepartments(`ORDER BY name`)
+}
+
+func (c *Cache) queryDepartments(clause string, args ...any) ([]Department, error) {
+	rows, err := c.db.Query(`SELECT fetched_at, data FROM departments `+clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying departments: %w", err)
+	}
+	defer rows.Close()
+
+	var depts []Department
+	for rows.Next() {
+		var d Department
+		var fetchedAt int64
+		var data string
+		if err := rows.Scan(&fetchedAt, &data); err != nil {
+			return nil, fmt.Errorf("scanning department: %w", err)
+		}
+		if err := json.Unmarshal([]byte(data), &d.Department); err != nil {
+			return nil, fmt.Errorf("decoding department: %w", err)
+		}
+		d.FetchedAt = time.Unix(fetchedAt, 0)
+		depts = append(depts, d)
+	}
+	return depts, rows.Err()
+}
+
+// GetIdentity returns a cached resolution of a reference such as
+// "chat:team standup", or nil if there is none
+func (c *Cache) GetIdentity(ref string) (*Identity, error) {
+	var fetchedAt int64
+	var data string
+	err := c.db.QueryRow(`SELECT fetched_at, data FROM identities WHERE ref = ?`, ref).Scan(&fetchedAt, &data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading identity: %w", err)
+	}
+	var id Identity
+	if err := json.Unmarshal([]byte(data), &id); err != nil {
+		return nil, fmt.Errorf("decoding identity: %w", err)
+	}
+	id.FetchedAt = time.Unix(fetchedAt, 0)
+	return &id, nil
+}
+
+// PutIdentity caches the resolution of a reference
+func (c *Cache) PutIdentity(ref string, id Identity, at time.Time) error {
+	data, err := json.Marshal(id)
+	if err != nil {
+		return fmt.Errorf("encoding identity: %w", err)
+	}
+	_, err = c.db.Exec(`INSERT OR REPLACE INTO identities (ref, fetched_at, data) VALUES (?, ?, ?)`, ref, at.Unix(), string(data))
+	if err != nil {
+		return fmt.Errorf("storing identity: %w", err)
+	}
+	return nil
+}
+
+// LastSync returns when the directory was last fully synced (zero if never)
+func (c *Cache) LastSync() (time.Time, error) {
+	var value string
+	err := c.db.QueryRow(`SELECT value FROM meta WHERE key = 'last_sync'`).Scan(&value)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("reading last sync: %w", err)
+	}
+	var ts int64
+	fmt.Sscan(value, &ts)
+	return time.Unix(ts, 0), nil
+}
+
+// SetLastSync records when the directory was last fully synced
+func (c *Cache) SetLastSync(t time.Time) error {
+	_, err := c.db.Exec(`INSERT OR REPLACE INTO meta (key, value) VALUES ('last_sync', ?)`, fmt.Sprint(t.Unix()))
+	if err != nil {
+		return fmt.Errorf("recording last sync: %w", err)
+	}
+	return nil
+}
+
+// Counts returns the number of cached users and departments
+func (c *Cache) Counts() (users, departments int, err error) {
+	if err = c.db.QueryRow(`SELECT COUNT(*) FROM users`).Scan(&users); err != nil {
+		return 0, 0, fmt.Errorf("counting users: %w", err)
+	}
+	if err = c.db.QueryRow(`SELECT COUNT(*) FROM departments`).Scan(&departments); err != nil {
+		return 0, 0, fmt.Errorf("counting departments: %w", err)
+	}
+	return users, departments, nil
+}
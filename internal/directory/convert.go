@@ -0,0 +1,165 @@
+package directory
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/parallel"
+)
+
+// IDTypes are the kinds of user ID Convert maps between
+var IDTypes = []string{"open_id", "union_id", "user_id", "email", "mobile"}
+
+// convertWorkers bounds concurrent batches in Convert
+const convertWorkers = 5
+
+// Convert maps user IDs of one type to another, in batches of 50 sent
+// concurrently. IDs that match no user, or a user without the target field,
+// are left out. Users fetched along the way are cached.
+func Convert(client *api.Client, cache *Cache, ids []string, from, to string) (map[string]string, error) {
+	if !isIDType(from) || !isIDType(to) {
+		return nil, fmt.Errorf("ID types must be one of %s", strings.Join(IDTypes, ", "))
+	}
+	if from == to {
+		return nil, fmt.Errorf("cannot convert %s to itself", from)
+	}
+
+	if from != "email" && from != "mobile" {
+		return convertByID(client, cache, ids, from, to)
+	}
+
+	// Emails and mobiles only map to an ID; get the user for anything else
+	lookupType := to
+	if to == "email" || to == "mobile" {
+		lookupType = "open_id"
+	}
+	found, err := lookupContacts(client, ids, from, lookupType)
+	if err != nil || lookupType == to {
+		return found, err
+	}
+
+	openIDs := make([]string, 0, len(found))
+	for _, id := range found {
+		openIDs = append(openIDs, id)
+	}
+	byOpenID, err := convertByID(client, cache, openIDs, "open_id", to)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string)
+	for id, openID := range found {
+		if v := byOpenID[openID]; v != "" {
+			result[id] = v
+		}
+	}
+	return result, nil
+}
+
+// convertByID fetches users by open_id, union_id or user_id
+func convertByID(client *api.Client, cache *Cache, ids []string, from, to string) (map[string]string, error) {
+	result := make(map[string]string)
+	var fetched []api.ContactUser
+	var mu sync.Mutex
+	err := forEachBatch(ids, func(batch []string) error {
+		users, err := client.BatchGetUsers(batch, from)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		fetched = append(fetched, users...)
+		for i := range users {
+			key, value := userField(&users[i], from), userField(&users[i], to)
+			if key != "" && value != "" {
+				result[key] = value
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// One write once every batch is in, rather than one per batch
+	if err := cache.PutUsers(fetched, time.Now()); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// lookupContacts maps emails or mobiles to IDs of idType. Emails are matched
+// ignoring case.
+func lookupContacts(client *api.Client, values []string, kind, idType string) (map[string]string, error) {
+	original := make(map[string]string, len(values))
+	for _, v := range values {
+		original[strings.ToLower(v)] = v
+	}
+
+	result := make(map[string]string)
+	var mu sync.Mutex
+	err := forEachBatch(values, func(batch []string) error {
+		opts := api.UserLookupOptions{IDType: idType}
+		if kind == "email" {
+			opts.Emails = batch
+		} else {
+			opts.Mobiles = batch
+		}
+		found, err := client.LookupUsers(opts)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, u := range found {
+			key := u.Email
+			if kind == "mobile" {
+				key = u.Mobile
+			}
+			if v, ok := original[strings.ToLower(key)]; ok && u.UserID != "" {
+				result[v] = u.UserID
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// forEachBatch calls fn with ids split into batches, convertWorkers at a
+// time, and returns the first error
+func forEachBatch(ids []string, fn func(batch []string) error) error {
+	batches := splitBatches(ids, batchSize)
+	return parallel.Do(len(batches), convertWorkers, func(i int) error {
+		return fn(batches[i])
+	})
+}
+
+// userField returns a user's ID, email or mobile by ID type
+func userField(u *api.ContactUser, idType string) string {
+	switch idType {
+	case "open_id":
+		return u.OpenID
+	case "union_id":
+		return u.UnionID
+	case "user_id":
+		return u.UserID
+	case "email":
+		if u.Email != "" {
+			return u.Email
+		}
+		return u.EnterpriseEmail
+	case "mobile":
+		return u.Mobile
+	}
+	return ""
+}
+
+func isIDType(t string) bool {
+	for _, v := range IDTypes {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
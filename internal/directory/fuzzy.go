@@ -0,0 +1,164 @@
+package directory
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+)
+
+// MinScore is the lowest score a fuzzy match is reported at
+const MinScore = 50
+
+// ConfidentScore is the lowest score a name is resolved at without asking:
+// an exact match or a prefix of a name or word. Initials and typo-tolerant
+// matches score below it.
+const ConfidentScore = 80
+
+// matchKey is one string a user can be found by
+type matchKey struct {
+	Value string // Normalized
+	Kind  string // name, en_name, nickname, email, pinyin or initials
+}
+
+// userKeys returns the strings a user can be matched on
+func userKeys(u *User) []matchKey {
+	var keys []matchKey
+	add := func(value, kind string) {
+		if v := normalize(value); v != "" {
+			keys = append(keys, matchKey{Value: v, Kind: kind})
+		}
+	}
+
+	add(u.Name, "name")
+	add(u.EnName, "en_name")
+	add(u.Nickname, "nickname")
+	for _, email := range []string{u.Email, u.EnterpriseEmail} {
+		if email == "" {
+			continue
+		}
+		add(email, "email")
+		if at := strings.IndexByte(email, '@'); at > 0 {
+			add(email[:at], "email")
+		}
+	}
+
+	// Chinese names are also matched by pinyin: 张三 by "zhang san",
+	// "zhangsan" and the initials "zs"
+	for _, name := range []string{u.Name, u.Nickname} {
+		if !hasHan(name) {
+			continue
+		}
+		syllables := pinyin.LazyPinyin(name, pinyin.NewArgs())
+		if len(syllables) == 0 {
+			continue
+		}
+		var initials strings.Builder
+		for _, s := range syllables {
+			initials.WriteByte(s[0])
+		}
+		add(strings.Join(syllables, " "), "pinyin")
+		add(strings.Join(syllables, ""), "pinyin")
+		add(initials.String(), "initials")
+	}
+	return keys
+}
+
+// score rates how well a normalized query matches a user, returning the best
+// score and the kind of key it matched on. Zero means no match.
+func score(query string, keys []matchKey) (int, string) {
+	compact := strings.ReplaceAll(query, " ", "")
+	best, on := 0, ""
+	for _, k := range keys {
+		var s int
+		if k.Kind == "initials" {
+			// Initials are too short to match loosely
+			if compact == k.Value && len(compact) >= 2 {
+				s = 75
+			}
+		} else {
+			s = scoreKey(query, k.Value)
+			if k.Kind == "pinyin" && compact != query {
+				s = max(s, scoreKey(compact, k.Value))
+			}
+		}
+		if s > best {
+			best, on = s, k.Kind
+		}
+	}
+	if best < MinScore {
+		return 0, ""
+	}
+	return best, on
+}
+
+func scoreKey(query, key string) int {
+	switch {
+	case key == query:
+		return 100
+	case strings.HasPrefix(key, query):
+		return 90
+	}
+	words := strings.FieldsFunc(key, func(r rune) bool {
+		return r == ' ' || r == '.' || r == '_' || r == '-' || r == '@'
+	})
+	for _, w := range words {
+		if strings.HasPrefix(w, query) {
+			return 80
+		}
+	}
+	if strings.Contains(key, query) {
+		return 70
+	}
+
+	// Typos: allow one edit per four characters of the query
+	n := len([]rune(query))
+	if n < 3 {
+		return 0
+	}
+	limit := max(1, n/4)
+	best := levenshtein(query, key)
+	for _, w := range words {
+		best = min(best, levenshtein(query, w))
+	}
+	if best > limit {
+		return 0
+	}
+	return 60 - 5*best
+}
+
+// normalize lowercases and collapses whitespace
+func normalize(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+func hasHan(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Han, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// levenshtein returns the edit distance between two strings, by rune
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,423 @@
+package directory
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yjwong/lark-cli/internal/api"
+)
+
+// Identity kinds
+const (
+	KindUser       = "user"
+	KindChat       = "chat"
+	KindDepartment = "department"
+	KindEmail      = "email" // An address that isn't a Lark user
+)
+
+// maxAliasDepth bounds alias chains, so a loop is reported instead of hanging
+const maxAliasDepth = 5
+
+// Identity is what a reference to a person, chat or department resolved to
+type Identity struct {
+	Ref   string `json:"ref"`
+	Kind  string `json:"kind"`
+	ID    string `json:"id,omitempty"` // open_id, chat_id or open_department_id
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+
+	FetchedAt time.Time `json:"-"`
+}
+
+// AmbiguousError reports a name that matches more than one candidate
+type AmbiguousError struct {
+	Ref        string
+	Candidates []Identity
+}
+
+func (e *AmbiguousError) Error() string {
+	names := make([]string, len(e.Candidates))
+	for i, c := range e.Candidates {
+		names[i] = c.Name
+		if c.Email != "" {
+			names[i] += " <" + c.Email + ">"
+		}
+		names[i] += " (" + c.ID + ")"
+	}
+	return fmt.Sprintf("%q is ambiguous: %s; use an email or ID instead", e.Ref, strings.Join(names, ", "))
+}
+
+// NoMatchError reports a name no user is confidently called. Suggestions are
+// the closer, typo-tolerant matches, if any.
+type NoMatchError struct {
+	Ref         string
+	Suggestions []Identity
+}
+
+func (e *NoMatchError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return fmt.Sprintf("no user found for %q", e.Ref)
+	}
+	names := make([]string, len(e.Suggestions))
+	for i, c := range e.Suggestions {
+		names[i] = c.Name + " (" + c.ID + ")"
+	}
+	return fmt.Sprintf("no user named %q; did you mean %s? Use an email or ID", e.Ref, strings.Join(names, ", "))
+}
+
+// Resolve resolves one reference. See ResolveAll for the accepted forms.
+func (r *Resolver) Resolve(ref string) (*Identity, error) {
+	ids, err := r.ResolveAll([]string{ref})
+	if err != nil {
+		return nil, err
+	}
+	return &ids[0], nil
+}
+
+// ResolveAll resolves references to people, chats and departments:
+//
+//	alice@corp.com      a user by email (kind "email" if not a Lark user)
+//	@alice, Alice Wong  a user by name, nickname or email name
+//	ou_xxx              a user by open_id
+//	oc_xxx, chat:Name   a chat by ID or name
+//	od_xxx, dept:Name   a department by ID or name
+//	<alias>             an entry in the aliases config
+//
+// Results keep the order of refs. Emails are looked up in one batch.
+func (r *Resolver) ResolveAll(refs []string) ([]Identity, error) {
+	out := make([]Identity, len(refs))
+	var lookup []string
+	lookupAt := make(map[string][]int)
+
+	for i, ref := range refs {
+		target, err := r.expandAlias(strings.TrimSpace(ref))
+		if err != nil {
+			return nil, err
+		}
+		if isEmail(target) {
+			id, err := r.cachedEmail(target)
+			if err != nil {
+				return nil, err
+			}
+			if id == nil {
+				key := strings.ToLower(target)
+				if lookupAt[key] == nil {
+					lookup = append(lookup, target)
+				}
+				lookupAt[key] = append(lookupAt[key], i)
+				continue
+			}
+			out[i] = *id
+		} else {
+			id, err := r.resolveOne(target)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = *id
+		}
+	}
+
+	if len(lookup) > 0 {
+		found, err := r.lookupEmails(lookup)
+		if err != nil {
+			return nil, err
+		}
+		for _, email := range lookup {
+			key := strings.ToLower(email)
+			id, ok := found[key]
+			if !ok {
+				id = Identity{Kind: KindEmail, Email: email}
+			}
+			for _, i := range lookupAt[key] {
+				out[i] = id
+			}
+		}
+	}
+
+	for i := range out {
+		out[i].Ref = refs[i]
+	}
+	return out, nil
+}
+
+// expandAlias follows config aliases; "@name" matches an alias "name" too
+func (r *Resolver) expandAlias(ref string) (string, error) {
+	if ref == "" {
+		return "", fmt.Errorf("empty reference")
+	}
+	for depth := 0; ; depth++ {
+		key := strings.ToLower(strings.TrimPrefix(ref, "@"))
+		target, ok := r.Aliases[key]
+		if !ok || target == "" {
+			return ref, nil
+		}
+		if depth == maxAliasDepth {
+			return "", fmt.Errorf("alias %q is part of a loop", key)
+		}
+		ref = strings.TrimSpace(target)
+	}
+}
+
+func (r *Resolver) resolveOne(ref string) (*Identity, error) {
+	switch {
+	case strings.HasPrefix(ref, "ou_"):
+		id := &Identity{Kind: KindUser, ID: ref}
+		if u, err := r.Cache.GetUser(ref); err == nil && u != nil {
+			id.Name, id.Email = u.Name, userEmail(u)
+		}
+		return id, nil
+	case strings.HasPrefix(ref, "oc_"):
+		return &Identity{Kind: KindChat, ID: ref}, nil
+	case strings.HasPrefix(ref, "od_"):
+		return &Identity{Kind: KindDepartment, ID: ref, Name: r.DepartmentName(ref)}, nil
+	case strings.HasPrefix(ref, "chat:"):
+		return r.resolveChat(strings.TrimSpace(strings.TrimPrefix(ref, "chat:")))
+	case strings.HasPrefix(ref, "dept:"):
+		return r.resolveDepartment(strings.TrimSpace(strings.TrimPrefix(ref, "dept:")))
+	default:
+		return r.resolveName(strings.TrimPrefix(ref, "@"))
+	}
+}
+
+// resolveName finds the one user a name refers to: the only confident match,
+// or the only exact match among several. References are used to send
+// messages and invites, so typo-tolerant matches are never picked; they are
+// only offered as suggestions. Lark's user search is always asked when
+// online, so an uncached user with the name isn't missed.
+func (r *Resolver) resolveName(name string) (*Identity, error) {
+	matches, err := r.Search(name, 10, r.Client != nil)
+	if err != nil {
+		return nil, err
+	}
+	var confident, exact []Match
+	for _, m := range matches {
+		if m.Score >= ConfidentScore {
+			confident = append(confident, m)
+		}
+		if m.Score == 100 {
+			exact = append(exact, m)
+		}
+	}
+	switch {
+	case len(confident) == 0:
+		suggestions := make([]Identity, len(matches))
+		for i := range matches {
+			suggestions[i] = *userIdentity(&matches[i].User)
+		}
+		return nil, &NoMatchError{Ref: name, Suggestions: suggestions}
+	case len(confident) == 1:
+		return userIdentity(&confident[0].User), nil
+	case len(exact) == 1:
+		return userIdentity(&exact[0].User), nil
+	}
+	if len(exact) > 1 {
+		confident = exact
+	}
+	candidates := make([]Identity, len(confident))
+	for i := range confident {
+		candidates[i] = *userIdentity(&confident[i].User)
+	}
+	return nil, &AmbiguousError{Ref: name, Candidates: candidates}
+}
+
+func (r *Resolver) resolveChat(name string) (*Identity, error) {
+	if name == "" {
+		return nil, fmt.Errorf("chat: needs a chat name or ID")
+	}
+	if strings.HasPrefix(name, "oc_") {
+		return &Identity{Kind: KindChat, ID: name}, nil
+	}
+	key := "chat:" + strings.ToLower(name)
+	if id, err := r.cachedIdentity(key); err != nil || id != nil {
+		return id, err
+	}
+	if r.Client == nil {
+		return nil, fmt.Errorf("chat %q is not cached; resolve it online first", name)
+	}
+
+	chats, _, _, err := r.Client.SearchChats(&api.SearchChatsOptions{Query: name, PageSize: 20})
+	if err != nil {
+		return nil, fmt.Errorf("searching chats: %w", err)
+	}
+	var candidates []Identity
+	for _, c := range chats {
+		candidates = append(candidates, Identity{Kind: KindChat, ID: c.ChatID, Name: c.Name})
+	}
+	id, err := pickByName(name, candidates)
+	if err != nil {
+		return nil, err
+	}
+	r.Cache.PutIdentity(key, *id, time.Now())
+	return id, nil
+}
+
+func (r *Resolver) resolveDepartment(name string) (*Identity, error) {
+	if name == "" {
+		return nil, fmt.Errorf("dept: needs a department name or ID")
+	}
+	if strings.HasPrefix(name, "od_") || name == "0" {
+		return &Identity{Kind: KindDepartment, ID: name, Name: r.DepartmentName(name)}, nil
+	}
+
+	depts, err := r.Cache.FindDepartmentsByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(depts) == 0 && r.Client != nil {
+		found, _, _, err := r.Client.SearchDepartments(name, 50, "")
+		if err != nil {
+			return nil, fmt.Errorf("searching departments: %w", err)
+		}
+		if err := r.Cache.PutDepartments(found, time.Now()); err != nil {
+			return nil, err
+		}
+		for _, d := range found {
+			depts = append(depts, Department{Department: d})
+		}
+	}
+	var candidates []Identity
+	for _, d := range depts {
+		candidates = append(candidates, Identity{Kind: KindDepartment, ID: d.OpenDepartmentID, Name: d.Name})
+	}
+	return pickByName(name, candidates)
+}
+
+// pickByName returns the only candidate, or the only one named exactly name
+func pickByName(name string, candidates []Identity) (*Identity, error) {
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("nothing found for %q", name)
+	}
+	if len(candidates) == 1 {
+		return &candidates[0], nil
+	}
+	var exact []Identity
+	for _, c := range candidates {
+		if strings.EqualFold(c.Name, name) {
+			exact = append(exact, c)
+		}
+	}
+	if len(exact) == 1 {
+		return &exact[0], nil
+	}
+	if len(exact) > 1 {
+		candidates = exact
+	}
+	return nil, &AmbiguousError{Ref: name, Candidates: candidates}
+}
+
+// cachedEmail resolves an email from the directory or an earlier lookup
+func (r *Resolver) cachedEmail(email string) (*Identity, error) {
+	u, err := r.Cache.FindUserByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if u != nil {
+		return userIdentity(u), nil
+	}
+	return r.cachedIdentity("email:" + strings.ToLower(email))
+}
+
+// cachedIdentity returns a cached resolution that is still fresh
+func (r *Resolver) cachedIdentity(key string) (*Identity, error) {
+	id, err := r.Cache.GetIdentity(key)
+	if err != nil || id == nil {
+		return nil, err
+	}
+	if r.Client != nil && r.stale(id.FetchedAt) {
+		return nil, nil
+	}
+	return id, nil
+}
+
+// lookupEmails resolves emails to Lark users in batches, keyed by lowercased
+// email. Offline, or if the lookup fails, the rest aren't found.
+func (r *Resolver) lookupEmails(emails []string) (map[string]Identity, error) {
+	found := make(map[string]Identity)
+	if r.Client == nil {
+		return found, nil
+	}
+	now := time.Now()
+	for start := 0; start < len(emails); start += 50 {
+		batch := emails[start:min(start+50, len(emails))]
+		users, err := r.Client.LookupUsers(api.UserLookupOptions{Emails: batch})
+		if err != nil {
+			// Treat the rest as external addresses, as attendee lookups do
+			break
+		}
+		for _, u := range users {
+			if u.UserID == "" || u.Email == "" {
+				continue
+			}
+			id := Identity{Kind: KindUser, ID: u.UserID, Email: u.Email}
+			if cached, err := r.Cache.GetUser(u.UserID); err == nil && cached != nil {
+				id.Name = cached.Name
+			}
+			key := strings.ToLower(u.Email)
+			found[key] = id
+			r.Cache.PutIdentity("email:"+key, id, now)
+		}
+	}
+	return found, nil
+}
+
+func userIdentity(u *User) *Identity {
+	return &Identity{Kind: KindUser, ID: u.OpenID, Name: u.Name, Email: userEmail(u)}
+}
+
+func userEmail(u *User) string {
+	if u.Email != "" {
+		return u.Email
+	}
+	return u.EnterpriseEmail
+}
+
+// isEmail reports whether a reference is an email address rather than @name
+func isEmail(ref string) bool {
+	at := strings.IndexByte(ref, '@')
+	return at > 0 && at < len(ref)-1 && !strings.Contains(ref, " ") && !strings.Contains(ref, ":")
+}
+
+// Members expands a chat or department to the users in it; a department's
+// members are the users directly in it. Users and emails are returned as is.
+func (r *Resolver) Members(id Identity) ([]Identity, error) {
+	switch id.Kind {
+	case KindChat:
+		if r.Client == nil {
+			return nil, fmt.Errorf("listing members of %s needs the API", id.ID)
+		}
+		members, err := r.Client.ListChatMembers(id.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list members of %s: %w", id.ID, err)
+		}
+		var out []Identity
+		for _, m := range members {
+			if m.MemberID != "" {
+				out = append(out, Identity{Ref: id.Ref, Kind: KindUser, ID: m.MemberID, Name: m.Name})
+			}
+		}
+		return out, nil
+	case KindDepartment:
+		if r.Client == nil {
+			return nil, fmt.Errorf("listing members of %s needs the API", id.ID)
+		}
+		var out []Identity
+		var pageToken string
+		for {
+			users, more, next, err := r.Client.ListUsersByDepartment(id.ID, 50, pageToken)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list department %s: %w", id.ID, err)
+			}
+			for _, u := range users {
+				out = append(out, Identity{Ref: id.Ref, Kind: KindUser, ID: u.OpenID, Name: u.Name, Email: u.Email})
+			}
+			if !more {
+				break
+			}
+			pageToken = next
+		}
+		return out, nil
+	default:
+		return []Identity{id}, nil
+	}
+}
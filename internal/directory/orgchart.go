@@ -0,0 +1,182 @@
+package directory
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/parallel"
+)
+
+// treeWorkers bounds the concurrent department listings while walking the
+// org chart
+const treeWorkers = 5
+
+// maxChainLength bounds management chains, in case leaders form a loop
+const maxChainLength = 50
+
+// DeptNode is a department in the org chart
+type DeptNode struct {
+	DepartmentID string      `json:"department_id"`
+	Name         string      `json:"name"`
+	LeaderID     string      `json:"leader_id,omitempty"`
+	HeadCount    int         `json:"head_count"` // Members including sub-departments
+	Children     []*DeptNode `json:"children,omitempty"`
+}
+
+// Person is a user in a reporting line
+type Person struct {
+	OpenID   string    `json:"open_id"`
+	Name     string    `json:"name"`
+	JobTitle string    `json:"job_title,omitempty"`
+	Email    string    `json:"email,omitempty"`
+	Reports  []*Person `json:"reports,omitempty"`
+}
+
+// NewPerson converts a cached user for output
+func NewPerson(u *User) *Person {
+	return &Person{OpenID: u.OpenID, Name: u.Name, JobTitle: u.JobTitle, Email: userEmail(u)}
+}
+
+// Tree walks the sub-departments of root down to depth levels (0 for all),
+// listing each level's departments concurrently. Departments found are cached.
+func Tree(client *api.Client, cache *Cache, root string, depth int) (*DeptNode, error) {
+	if root == "" {
+		root = "0"
+	}
+	node := &DeptNode{DepartmentID: root}
+	// Some tenants don't expose the root department itself, so only a named
+	// department has to exist
+	dept, err := client.GetDepartment(root)
+	if err != nil && root != "0" {
+		return nil, fmt.Errorf("getting department %s: %w", root, err)
+	}
+	if err == nil && dept != nil {
+		node.Name = dept.Name
+		node.LeaderID = dept.LeaderUserID
+		node.HeadCount = dept.MemberCount
+	}
+
+	// Walk one level at a time, listing the level's departments concurrently
+	var found []api.Department
+	level := []*DeptNode{node}
+	for walked := 0; len(level) > 0 && (depth <= 0 || walked < depth); walked++ {
+		children := make([][]api.Department, len(level))
+		err := parallel.Do(len(level), treeWorkers, func(i int) error {
+			var pageToken string
+			hasMore := true
+			for hasMore {
+				page, more, nextToken, err := client.ListChildDepartments(level[i].DepartmentID, false, 50, pageToken)
+				if err != nil {
+					return fmt.Errorf("listing departments in %s: %w", level[i].DepartmentID, err)
+				}
+				children[i] = append(children[i], page...)
+				hasMore = more
+				pageToken = nextToken
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var next []*DeptNode
+		for i, n := range level {
+			found = append(found, children[i]...)
+			for _, d := range children[i] {
+				child := &DeptNode{
+					DepartmentID: d.OpenDepartmentID,
+					Name:         d.Name,
+					LeaderID:     d.LeaderUserID,
+					HeadCount:    d.MemberCount,
+				}
+				n.Children = append(n.Children, child)
+				next = append(next, child)
+			}
+		}
+		level = next
+	}
+
+	if node.HeadCount == 0 {
+		for _, c := range node.Children {
+			node.HeadCount += c.HeadCount
+		}
+	}
+	if err := cache.PutDepartments(found, time.Now()); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// ManagerChain returns a user's managers by following leader_user_id, from
+// the direct manager up. Unless all is set only the direct manager is
+// returned. The chain stops at the top or at a loop.
+func (r *Resolver) ManagerChain(openID string, all bool) (*User, []User, error) {
+	user, err := r.User(openID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var chain []User
+	seen := map[string]bool{user.OpenID: true}
+	next := user.LeaderUserID
+	for next != "" && !seen[next] && len(chain) < maxChainLength {
+		manager, err := r.User(next)
+		if err != nil {
+			return nil, nil, fmt.Errorf("getting manager %s: %w", next, err)
+		}
+		chain = append(chain, *manager)
+		if !all {
+			break
+		}
+		seen[next] = true
+		next = manager.LeaderUserID
+	}
+	return user, chain, nil
+}
+
+// Reports returns a user with the people whose leader_user_id points at them,
+// and with recursive set, everyone below those too. Reporting lines come from
+// the cached directory, so it should be synced first.
+func (r *Resolver) Reports(openID string, recursive bool) (*Person, int, error) {
+	user, err := r.User(openID)
+	if err != nil {
+		return nil, 0, err
+	}
+	users, err := r.Cache.ListUsers()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	byLeader := make(map[string][]*User)
+	for i := range users {
+		if lead := users[i].LeaderUserID; lead != "" && lead != users[i].OpenID {
+			byLeader[lead] = append(byLeader[lead], &users[i])
+		}
+	}
+	for _, reports := range byLeader {
+		sort.Slice(reports, func(i, j int) bool { return reports[i].Name < reports[j].Name })
+	}
+
+	root := NewPerson(user)
+	count := 0
+	seen := map[string]bool{user.OpenID: true}
+	var add func(p *Person)
+	add = func(p *Person) {
+		for _, u := range byLeader[p.OpenID] {
+			if seen[u.OpenID] {
+				continue
+			}
+			seen[u.OpenID] = true
+			report := NewPerson(u)
+			p.Reports = append(p.Reports, report)
+			count++
+			if recursive {
+				add(report)
+			}
+		}
+	}
+	add(root)
+	return root, count, nil
+}
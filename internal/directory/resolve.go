@@ -0,0 +1,311 @@
+package directory
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/parallel"
+)
+
+// DefaultTTL is how long a cached user is trusted before it is refetched
+const DefaultTTL = 7 * 24 * time.Hour
+
+// MissTTL is how long an open_id the API had no user for (an external user
+// or someone who has left) is remembered before it is asked for again
+const MissTTL = 24 * time.Hour
+
+// fetchWorkers bounds concurrent user lookups when refreshing entries
+const fetchWorkers = 5
+
+// batchSize is the most IDs the batch user APIs take per request
+const batchSize = 50
+
+// Resolver looks people up in the directory cache, refreshing stale or
+// missing entries from the API when it has a client
+type Resolver struct {
+	Cache   *Cache
+	Client  *api.Client       // nil keeps the resolver offline
+	TTL     time.Duration     // Zero means DefaultTTL
+	Aliases map[string]string // Lowercased alias -> reference, from config
+}
+
+// Match is a user found by Search
+type Match struct {
+	User
+	Score     int    // 0-100
+	MatchedOn string // name, en_name, nickname, email, pinyin, initials or search
+}
+
+func (r *Resolver) stale(fetchedAt time.Time) bool {
+	ttl := r.TTL
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return time.Since(fetchedAt) > ttl
+}
+
+// User returns a user by open_id. A missing, stale or partial entry is
+// refetched; if that fails a stale entry is still returned.
+func (r *Resolver) User(openID string) (*User, error) {
+	cached, err := r.Cache.GetUser(openID)
+	if err != nil {
+		return nil, err
+	}
+	if cached != nil && !cached.Partial && !r.stale(cached.FetchedAt) {
+		return cached, nil
+	}
+	if r.Client == nil {
+		if cached == nil {
+			return nil, fmt.Errorf("user %s is not in the directory cache", openID)
+		}
+		return cached, nil
+	}
+
+	u, err := r.fetch(openID)
+	if err != nil {
+		if cached != nil {
+			return cached, nil
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+func (r *Resolver) fetch(openID string) (*User, error) {
+	contact, err := r.Client.GetUser(openID, "open_id")
+	if err != nil {
+		return nil, err
+	}
+	if contact == nil {
+		return nil, fmt.Errorf("user not found: %s", openID)
+	}
+	now := time.Now()
+	if err := r.Cache.PutUsers([]api.ContactUser{*contact}, now); err != nil {
+		return nil, err
+	}
+	return &User{ContactUser: *contact, FetchedAt: now}, nil
+}
+
+// Users returns users by open_id. Cached entries are used as they are;
+// stale and missing ones are fetched in batches and cached in one write.
+// IDs that can't be found, now or within MissTTL, are returned separately.
+// On an API error the users found so far are still returned.
+func (r *Resolver) Users(openIDs []string) (map[string]User, []string, error) {
+	users := make(map[string]User)
+	var refresh, missing []string
+	seen := make(map[string]bool)
+	for _, id := range openIDs {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		u, err := r.Cache.GetUser(id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if u != nil {
+			users[id] = *u
+			if !r.stale(u.FetchedAt) {
+				continue
+			}
+		} else if r.Client == nil || r.missed(id) {
+			missing = append(missing, id)
+			continue
+		}
+		refresh = append(refresh, id)
+	}
+	if r.Client == nil || len(refresh) == 0 {
+		return users, missing, nil
+	}
+
+	fetched, err := r.fetchUsers(refresh)
+	now := time.Now()
+	for _, u := range fetched {
+		users[u.OpenID] = User{ContactUser: u, FetchedAt: now}
+	}
+	for _, id := range refresh {
+		if _, ok := users[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	return users, missing, err
+}
+
+// fetchUsers gets users by open_id in concurrent batches and caches them.
+// IDs a batch didn't return are cached as misses.
+func (r *Resolver) fetchUsers(openIDs []string) ([]api.ContactUser, error) {
+	batches := splitBatches(openIDs, batchSize)
+	results := make([][]api.ContactUser, len(batches))
+	errs := make([]error, len(batches))
+	parallel.Run(len(batches), fetchWorkers, func(i int) {
+		results[i], errs[i] = r.Client.BatchGetUsers(batches[i], "open_id")
+	})
+
+	var firstErr error
+	var fetched []api.ContactUser
+	var misses []string
+	for i, batch := range batches {
+		// A failed batch says nothing about whether its users exist
+		if errs[i] != nil {
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+			continue
+		}
+		found := make(map[string]bool)
+		for _, u := range results[i] {
+			found[u.OpenID] = true
+			fetched = append(fetched, u)
+		}
+		for _, id := range batch {
+			if !found[id] {
+				misses = append(misses, id)
+			}
+		}
+	}
+
+	now := time.Now()
+	if err := r.Cache.PutUsers(fetched, now); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	if err := r.Cache.PutMisses(misses, now); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return fetched, firstErr
+}
+
+// missed reports whether an open_id was recently found not to exist
+func (r *Resolver) missed(openID string) bool {
+	at, err := r.Cache.MissedAt(openID)
+	return err == nil && !at.IsZero() && time.Since(at) < MissTTL
+}
+
+// Names maps open_ids to display names, see Users. IDs that can't be
+// resolved are left out.
+func (r *Resolver) Names(openIDs []string) map[string]string {
+	users, _, _ := r.Users(openIDs)
+	names := make(map[string]string, len(users))
+	for id, u := range users {
+		if u.Name != "" {
+			names[id] = u.Name
+		}
+	}
+	return names
+}
+
+// splitBatches splits ids into slices of at most size
+func splitBatches(ids []string, size int) [][]string {
+	var batches [][]string
+	for start := 0; start < len(ids); start += size {
+		batches = append(batches, ids[start:min(start+size, len(ids))])
+	}
+	return batches
+}
+
+// DepartmentName returns a department's cached name, or "" if unknown
+func (r *Resolver) DepartmentName(id string) string {
+	d, err := r.Cache.GetDepartment(id)
+	if err != nil || d == nil {
+		return ""
+	}
+	return d.Name
+}
+
+// DepartmentPath returns the names of a department and its parents, from the
+// top down. Departments missing from the cache are fetched when the resolver
+// has a client; the path stops at one that can't be found.
+func (r *Resolver) DepartmentPath(id string) []string {
+	var path []string
+	seen := make(map[string]bool)
+	for id != "" && id != "0" && !seen[id] {
+		seen[id] = true
+		d, err := r.Cache.GetDepartment(id)
+		if err != nil {
+			break
+		}
+		if d == nil {
+			if r.Client == nil {
+				break
+			}
+			dept, err := r.Client.GetDepartment(id)
+			if err != nil || dept == nil {
+				break
+			}
+			if dept.OpenDepartmentID == "" {
+				dept.OpenDepartmentID = id
+			}
+			r.Cache.PutDepartments([]api.Department{*dept}, time.Now())
+			d = &Department{Department: *dept}
+		}
+		path = append([]string{d.Name}, path...)
+		id = d.ParentDepartmentID
+	}
+	return path
+}
+
+// Search finds users by name, English name, nickname, email or the pinyin of
+// a Chinese name, tolerating small typos. The cache is searched first; when
+// it has no match, or always is set, the API's user search is asked as well
+// and its results are cached.
+func (r *Resolver) Search(query string, limit int, always bool) ([]Match, error) {
+	q := normalize(query)
+	if q == "" {
+		return nil, fmt.Errorf("empty query")
+	}
+
+	users, err := r.Cache.ListUsers()
+	if err != nil {
+		return nil, err
+	}
+	var matches []Match
+	found := make(map[string]bool)
+	for i := range users {
+		if s, on := score(q, userKeys(&users[i])); s > 0 {
+			matches = append(matches, Match{User: users[i], Score: s, MatchedOn: on})
+			found[users[i].OpenID] = true
+		}
+	}
+
+	if r.Client != nil && (always || len(matches) == 0) {
+		results, _, _, err := r.Client.SearchUsers(query, 50, "")
+		if err != nil {
+			if len(matches) == 0 {
+				return nil, err
+			}
+		} else {
+			now := time.Now()
+			if err := r.Cache.PutSearchResults(results, now); err != nil {
+				return nil, err
+			}
+			for _, res := range results {
+				if found[res.OpenID] {
+					continue
+				}
+				u, err := r.Cache.GetUser(res.OpenID)
+				if err != nil || u == nil {
+					continue
+				}
+				// The API matched it even if the local keys don't
+				s, on := score(q, userKeys(u))
+				if s == 0 {
+					s, on = MinScore, "search"
+				}
+				matches = append(matches, Match{User: *u, Score: s, MatchedOn: on})
+				found[res.OpenID] = true
+			}
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Name < matches[j].Name
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
@@ -0,0 +1,149 @@
+package directory
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/yjwong/lark-cli/internal/api"
+	"github.com/yjwong/lark-cli/internal/parallel"
+)
+
+// syncWorkers bounds the concurrent department listings during a sync
+const syncWorkers = 5
+
+// SyncOptions configures the sync operation
+type SyncOptions struct {
+	Root     string    // Department to sync from; "0" or empty is the whole company
+	Progress io.Writer // If set, progress is written here
+}
+
+// SyncResult contains the result of a sync operation
+type SyncResult struct {
+	Root        string `json:"root"`
+	Departments int    `json:"departments"`
+	Users       int    `json:"users"`
+	Removed     int    `json:"removed"`
+	SyncedAt    string `json:"synced_at"`
+	Message     string `json:"message"`
+}
+
+// Sync fetches every department under the root and the users directly in
+// each, and stores them. A sync of the whole company also drops users who
+// are no longer in the directory.
+func Sync(client *api.Client, cache *Cache, opts *SyncOptions) (*SyncResult, error) {
+	if opts == nil {
+		opts = &SyncOptions{}
+	}
+	root := opts.Root
+	if root == "" {
+		root = "0"
+	}
+	now := time.Now()
+
+	depts, users, err := FetchDepartment(client, root, true, opts.Progress)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cache.PutDepartments(depts, now); err != nil {
+		return nil, err
+	}
+	if err := cache.PutUsers(users, now); err != nil {
+		return nil, err
+	}
+
+	result := &SyncResult{
+		Root:        root,
+		Departments: len(depts),
+		Users:       len(users),
+		SyncedAt:    now.Format(time.RFC3339),
+	}
+	if root == "0" {
+		removed, err := cache.PruneUsers(now)
+		if err != nil {
+			return nil, err
+		}
+		result.Removed = removed
+		if err := cache.SetLastSync(now); err != nil {
+			return nil, err
+		}
+	}
+	result.Message = fmt.Sprintf("Synced %d users in %d departments", result.Users, result.Departments)
+	return result, nil
+}
+
+// FetchDepartment lists the users directly in a department, or with recursive
+// set in it and all its sub-departments, paging through every result. The
+// sub-departments found are returned too.
+func FetchDepartment(client *api.Client, root string, recursive bool, progressTo io.Writer) ([]api.Department, []api.ContactUser, error) {
+	progress := func(format string, args ...interface{}) {
+		if progressTo != nil {
+			fmt.Fprintf(progressTo, format+"\n", args...)
+		}
+	}
+
+	var depts []api.Department
+	if recursive {
+		var pageToken string
+		hasMore := true
+		for hasMore {
+			page, more, nextToken, err := client.ListChildDepartments(root, true, 50, pageToken)
+			if err != nil {
+				return nil, nil, fmt.Errorf("listing departments: %w", err)
+			}
+			depts = append(depts, page...)
+			hasMore = more
+			pageToken = nextToken
+		}
+		progress("Found %d departments", len(depts))
+	}
+
+	deptIDs := []string{root}
+	for _, d := range depts {
+		deptIDs = append(deptIDs, d.OpenDepartmentID)
+	}
+
+	found := make([][]api.ContactUser, len(deptIDs))
+	var mu sync.Mutex
+	done, listed := 0, 0
+	err := parallel.Do(len(deptIDs), syncWorkers, func(i int) error {
+		var pageToken string
+		hasMore := true
+		for hasMore {
+			page, more, nextToken, err := client.ListUsersByDepartment(deptIDs[i], 50, pageToken)
+			if err != nil {
+				return fmt.Errorf("listing users in %s: %w", deptIDs[i], err)
+			}
+			found[i] = append(found[i], page...)
+			hasMore = more
+			pageToken = nextToken
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		listed += len(found[i])
+		if done%20 == 0 {
+			progress("Listed %d/%d departments, %d users", done, len(deptIDs), listed)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seen := make(map[string]bool)
+	var users []api.ContactUser
+	for _, page := range found {
+		for _, u := range page {
+			if !seen[u.OpenID] {
+				seen[u.OpenID] = true
+				users = append(users, u)
+			}
+		}
+	}
+
+	return depts, users, nil
+}
@@ -0,0 +1,356 @@
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	timex "github.com/yjwong/lark-cli/internal/time"
+)
+
+// property is a parsed content line
+type property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+func (p property) param(name string) string {
+	return p.Params[name]
+}
+
+// component is a parsed BEGIN/END block
+type component struct {
+	Name       string
+	Props      []property
+	Components []*component
+}
+
+func (c *component) first(name string) (property, bool) {
+	for _, p := range c.Props {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return property{}, false
+}
+
+func (c *component) value(name string) string {
+	p, _ := c.first(name)
+	return p.Value
+}
+
+func (c *component) all(name string) []property {
+	var out []property
+	for _, p := range c.Props {
+		if p.Name == name {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Decode parses all VEVENTs from an iCalendar stream. Floating times and
+// unknown TZIDs are interpreted in defaultLoc.
+func Decode(r io.Reader, defaultLoc *time.Location) ([]Event, error) {
+	if defaultLoc == nil {
+		defaultLoc = time.Local
+	}
+
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &component{Name: "ROOT"}
+	stack := []*component{root}
+	for n, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		prop, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+
+		cur := stack[len(stack)-1]
+		switch prop.Name {
+		case "BEGIN":
+			child := &component{Name: strings.ToUpper(prop.Value)}
+			cur.Components = append(cur.Components, child)
+			stack = append(stack, child)
+		case "END":
+			if len(stack) == 1 || cur.Name != strings.ToUpper(prop.Value) {
+				return nil, fmt.Errorf("line %d: unexpected END:%s", n+1, prop.Value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			cur.Props = append(cur.Props, prop)
+		}
+	}
+	if len(stack) != 1 {
+		return nil, fmt.Errorf("unterminated component: %s", stack[len(stack)-1].Name)
+	}
+
+	var events []Event
+	for _, cal := range root.Components {
+		if cal.Name != "VCALENDAR" {
+			continue
+		}
+		zones := calendarZones(cal)
+		for _, c := range cal.Components {
+			if c.Name != "VEVENT" {
+				continue
+			}
+			e, err := decodeEvent(c, zones, defaultLoc)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+// unfold reads content lines, joining folded continuation lines
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// parseLine splits a content line into name, parameters and value
+func parseLine(line string) (property, error) {
+	p := property{Params: make(map[string]string)}
+
+	// Name ends at the first ';' or ':'
+	i := strings.IndexAny(line, ";:")
+	if i <= 0 {
+		return p, fmt.Errorf("malformed content line: %q", line)
+	}
+	p.Name = strings.ToUpper(line[:i])
+	rest := line[i:]
+
+	for strings.HasPrefix(rest, ";") {
+		rest = rest[1:]
+		eq := strings.Index(rest, "=")
+		if eq < 0 {
+			return p, fmt.Errorf("malformed parameter in %q", line)
+		}
+		key := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+
+		var val string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				return p, fmt.Errorf("unterminated quoted parameter in %q", line)
+			}
+			val = rest[1 : end+1]
+			rest = rest[end+2:]
+		} else {
+			end := strings.IndexAny(rest, ";:")
+			if end < 0 {
+				return p, fmt.Errorf("malformed parameter in %q", line)
+			}
+			val = rest[:end]
+			rest = rest[end:]
+		}
+		p.Params[key] = val
+	}
+
+	if !strings.HasPrefix(rest, ":") {
+		return p, fmt.Errorf("missing value in %q", line)
+	}
+	p.Value = rest[1:]
+	return p, nil
+}
+
+// calendarZones maps VTIMEZONE TZIDs that Go doesn't know to a fixed offset
+// taken from their STANDARD observance
+func calendarZones(cal *component) map[string]*time.Location {
+	zones := make(map[string]*time.Location)
+	for _, c := range cal.Components {
+		if c.Name != "VTIMEZONE" {
+			continue
+		}
+		tzid := c.value("TZID")
+		if tzid == "" {
+			continue
+		}
+		if loc, err := time.LoadLocation(tzid); err == nil {
+			zones[tzid] = loc
+			continue
+		}
+		for _, obs := range c.Components {
+			if obs.Name != "STANDARD" && obs.Name != "DAYLIGHT" {
+				continue
+			}
+			if offset, err := parseOffset(obs.value("TZOFFSETTO")); err == nil {
+				zones[tzid] = time.FixedZone(tzid, offset)
+				if obs.Name == "STANDARD" {
+					break
+				}
+			}
+		}
+	}
+	return zones
+}
+
+func decodeEvent(c *component, zones map[string]*time.Location, defaultLoc *time.Location) (Event, error) {
+	e := Event{
+		UID:          c.value("UID"),
+		Summary:      unescapeText(c.value("SUMMARY")),
+		Description:  unescapeText(c.value("DESCRIPTION")),
+		Location:     unescapeText(c.value("LOCATION")),
+		URL:          c.value("URL"),
+		RRule:        c.value("RRULE"),
+		RecurrenceID: c.value("RECURRENCE-ID"),
+		Status:       strings.ToUpper(c.value("STATUS")),
+		Transparent:  strings.EqualFold(c.value("TRANSP"), "TRANSPARENT"),
+		Class:        strings.ToUpper(c.value("CLASS")),
+	}
+	e.Sequence, _ = strconv.Atoi(c.value("SEQUENCE"))
+
+	dtstart, ok := c.first("DTSTART")
+	if !ok {
+		return e, fmt.Errorf("event %q has no DTSTART", e.UID)
+	}
+	start, allDay, err := parseTimeProp(dtstart, zones, defaultLoc)
+	if err != nil {
+		return e, fmt.Errorf("event %q: DTSTART: %w", e.UID, err)
+	}
+	e.Start = start
+	e.AllDay = allDay
+
+	// The rule is checked here so a bad one is reported with its event
+	// rather than by whoever the event is handed to
+	if e.RRule != "" {
+		if _, err := timex.ParseRRule(e.RRule, start.Location()); err != nil {
+			return e, fmt.Errorf("event %q: %w", e.UID, err)
+		}
+	}
+
+	if dtend, ok := c.first("DTEND"); ok {
+		e.End, _, err = parseTimeProp(dtend, zones, defaultLoc)
+		if err != nil {
+			return e, fmt.Errorf("event %q: DTEND: %w", e.UID, err)
+		}
+	} else if dur := c.value("DURATION"); dur != "" {
+		d, err := ParseDuration(dur)
+		if err != nil {
+			return e, fmt.Errorf("event %q: %w", e.UID, err)
+		}
+		if allDay {
+			e.End = e.Start.AddDate(0, 0, int(d/(24*time.Hour)))
+		} else {
+			e.End = e.Start.Add(d)
+		}
+	} else if allDay {
+		// RFC 5545: a DATE start without an end lasts one day
+		e.End = e.Start.AddDate(0, 0, 1)
+	} else {
+		e.End = e.Start
+	}
+
+	for _, p := range c.all("EXDATE") {
+		e.ExDates = append(e.ExDates, strings.Split(p.Value, ",")...)
+	}
+
+	if t, err := parseUTC(c.value("CREATED")); err == nil {
+		e.Created = t
+	}
+	if t, err := parseUTC(c.value("LAST-MODIFIED")); err == nil {
+		e.LastModified = t
+	}
+
+	if p, ok := c.first("ORGANIZER"); ok {
+		org := decodeAttendee(p)
+		e.Organizer = &org
+	}
+	for _, p := range c.all("ATTENDEE") {
+		e.Attendees = append(e.Attendees, decodeAttendee(p))
+	}
+
+	for _, alarm := range c.Components {
+		if alarm.Name != "VALARM" {
+			continue
+		}
+		trigger, ok := alarm.first("TRIGGER")
+		if !ok || strings.EqualFold(trigger.param("VALUE"), "DATE-TIME") {
+			continue
+		}
+		if rel := trigger.param("RELATED"); rel != "" && !strings.EqualFold(rel, "START") {
+			continue
+		}
+		d, err := ParseDuration(trigger.Value)
+		if err != nil || d > 0 {
+			continue
+		}
+		e.Alarms = append(e.Alarms, int(-d/time.Minute))
+	}
+
+	return e, nil
+}
+
+func decodeAttendee(p property) Attendee {
+	a := Attendee{
+		Name:     p.param("CN"),
+		URI:      p.Value,
+		PartStat: strings.ToUpper(p.param("PARTSTAT")),
+		Optional: strings.EqualFold(p.param("ROLE"), "OPT-PARTICIPANT"),
+		CUType:   strings.ToUpper(p.param("CUTYPE")),
+	}
+	if len(p.Value) > 7 && strings.EqualFold(p.Value[:7], "mailto:") {
+		a.Email = p.Value[7:]
+	} else if email := p.param("EMAIL"); email != "" {
+		a.Email = email
+	}
+	return a
+}
+
+// parseTimeProp parses a DATE or DATE-TIME property value
+func parseTimeProp(p property, zones map[string]*time.Location, defaultLoc *time.Location) (time.Time, bool, error) {
+	value := strings.TrimSpace(p.Value)
+
+	if strings.EqualFold(p.param("VALUE"), "DATE") || len(value) == len(dateFormat) {
+		t, err := time.ParseInLocation(dateFormat, value, defaultLoc)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(utcFormat, value)
+		return t, false, err
+	}
+
+	loc := defaultLoc
+	if tzid := p.param("TZID"); tzid != "" {
+		if z, ok := zones[tzid]; ok {
+			loc = z
+		} else if z, err := time.LoadLocation(strings.TrimPrefix(tzid, "/")); err == nil {
+			loc = z
+		}
+	}
+	t, err := time.ParseInLocation(dateTimeFormat, value, loc)
+	return t, false, err
+}
+
+func parseUTC(value string) (time.Time, error) {
+	return time.Parse(utcFormat, value)
+}
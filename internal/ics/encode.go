@@ -0,0 +1,294 @@
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ProdID identifies this program in generated calendars
+const ProdID = "-//lark-cli//Lark Calendar//EN"
+
+// maxLineOctets is the folding limit from RFC 5545 section 3.1
+const maxLineOctets = 75
+
+// lineWriter writes folded content lines terminated by CRLF
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (lw *lineWriter) line(s string) {
+	if lw.err != nil {
+		return
+	}
+	first := true
+	for len(s) > 0 {
+		limit := maxLineOctets
+		if !first {
+			limit-- // Continuation lines start with a space
+		}
+		cut := len(s)
+		if cut > limit {
+			cut = limit
+			// Never split a multi-byte character
+			for cut > 0 && !utf8.RuneStart(s[cut]) {
+				cut--
+			}
+		}
+		if !first {
+			lw.w.WriteString(" ")
+		}
+		_, lw.err = lw.w.WriteString(s[:cut] + "\r\n")
+		s = s[cut:]
+		first = false
+	}
+}
+
+func (lw *lineWriter) prop(name, value string) {
+	if value != "" {
+		lw.line(name + ":" + value)
+	}
+}
+
+// paramValue quotes a parameter value when it contains special characters
+func paramValue(s string) string {
+	s = strings.ReplaceAll(s, `"`, "'")
+	if strings.ContainsAny(s, ":;,") {
+		return `"` + s + `"`
+	}
+	return s
+}
+
+// Encode writes events as an RFC 5545 VCALENDAR. Timed events are written in
+// their own time zone with a matching VTIMEZONE; all-day events use DATE values.
+func Encode(w io.Writer, events []Event) error {
+	return encode(w, events, "PUBLISH")
+}
+
+// EncodeObject writes a single event as a CalDAV calendar object resource,
+// which unlike Encode has no METHOD property (RFC 4791 section 4.1)
+func EncodeObject(w io.Writer, e Event) error {
+	return encode(w, []Event{e}, "")
+}
+
+func encode(w io.Writer, events []Event, method string) error {
+	lw := &lineWriter{w: bufio.NewWriter(w)}
+
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:" + ProdID)
+	lw.line("CALSCALE:GREGORIAN")
+	lw.prop("METHOD", method)
+
+	for _, tz := range collectZones(events) {
+		writeTimezone(lw, tz.loc, tz.from, tz.to)
+	}
+
+	stamp := time.Now().UTC().Format(utcFormat)
+	for _, e := range events {
+		writeEvent(lw, e, stamp)
+	}
+
+	lw.line("END:VCALENDAR")
+	if lw.err != nil {
+		return lw.err
+	}
+	return lw.w.Flush()
+}
+
+func writeEvent(lw *lineWriter, e Event, stamp string) {
+	lw.line("BEGIN:VEVENT")
+	lw.prop("UID", e.UID)
+	lw.line("DTSTAMP:" + stamp)
+
+	if e.AllDay {
+		lw.line("DTSTART;VALUE=DATE:" + e.Start.Format(dateFormat))
+		if !e.End.IsZero() {
+			lw.line("DTEND;VALUE=DATE:" + e.End.Format(dateFormat))
+		}
+	} else {
+		lw.line("DTSTART" + timeValue(e.Start))
+		if !e.End.IsZero() {
+			lw.line("DTEND" + timeValue(e.End))
+		}
+	}
+	if e.RecurrenceID != "" {
+		lw.line("RECURRENCE-ID:" + e.RecurrenceID)
+	}
+	lw.prop("RRULE", e.RRule)
+
+	lw.prop("SUMMARY", escapeText(e.Summary))
+	lw.prop("DESCRIPTION", escapeText(e.Description))
+	lw.prop("LOCATION", escapeText(e.Location))
+	lw.prop("URL", e.URL)
+	lw.prop("STATUS", e.Status)
+	lw.prop("CLASS", e.Class)
+	if e.Transparent {
+		lw.line("TRANSP:TRANSPARENT")
+	} else {
+		lw.line("TRANSP:OPAQUE")
+	}
+	if !e.Created.IsZero() {
+		lw.line("CREATED:" + e.Created.UTC().Format(utcFormat))
+	}
+	if !e.LastModified.IsZero() {
+		lw.line("LAST-MODIFIED:" + e.LastModified.UTC().Format(utcFormat))
+	}
+	if e.Sequence > 0 {
+		lw.line(fmt.Sprintf("SEQUENCE:%d", e.Sequence))
+	}
+
+	if e.Organizer != nil {
+		lw.line("ORGANIZER" + attendeeParams(*e.Organizer, false) + ":" + attendeeURI(*e.Organizer))
+	}
+	for _, a := range e.Attendees {
+		lw.line("ATTENDEE" + attendeeParams(a, true) + ":" + attendeeURI(a))
+	}
+
+	for _, minutes := range e.Alarms {
+		lw.line("BEGIN:VALARM")
+		lw.line("ACTION:DISPLAY")
+		lw.line("DESCRIPTION:" + escapeText(e.Summary))
+		lw.line("TRIGGER:" + FormatDuration(-time.Duration(minutes)*time.Minute))
+		lw.line("END:VALARM")
+	}
+
+	lw.line("END:VEVENT")
+}
+
+// timeValue renders the parameter and value part of a DATE-TIME property
+func timeValue(t time.Time) string {
+	if t.Location() == time.UTC {
+		return ":" + t.Format(utcFormat)
+	}
+	return ";TZID=" + paramValue(t.Location().String()) + ":" + t.Format(dateTimeFormat)
+}
+
+func attendeeURI(a Attendee) string {
+	if a.URI != "" {
+		return a.URI
+	}
+	return "mailto:" + a.Email
+}
+
+func attendeeParams(a Attendee, full bool) string {
+	var b strings.Builder
+	if a.Name != "" {
+		b.WriteString(";CN=" + paramValue(a.Name))
+	}
+	if !full {
+		return b.String()
+	}
+	if a.CUType != "" {
+		b.WriteString(";CUTYPE=" + a.CUType)
+	}
+	if a.Optional {
+		b.WriteString(";ROLE=OPT-PARTICIPANT")
+	} else {
+		b.WriteString(";ROLE=REQ-PARTICIPANT")
+	}
+	if a.PartStat != "" {
+		b.WriteString(";PARTSTAT=" + a.PartStat)
+	}
+	return b.String()
+}
+
+// zoneRange is a time zone used by exported events and the span it must cover
+type zoneRange struct {
+	loc      *time.Location
+	from, to time.Time
+}
+
+func collectZones(events []Event) []zoneRange {
+	byName := make(map[string]*zoneRange)
+	for _, e := range events {
+		if e.AllDay {
+			continue
+		}
+		for _, t := range []time.Time{e.Start, e.End} {
+			if t.IsZero() || t.Location() == time.UTC {
+				continue
+			}
+			name := t.Location().String()
+			z, ok := byName[name]
+			if !ok {
+				byName[name] = &zoneRange{loc: t.Location(), from: t, to: t}
+				continue
+			}
+			if t.Before(z.from) {
+				z.from = t
+			}
+			if t.After(z.to) {
+				z.to = t
+			}
+		}
+	}
+
+	zones := make([]zoneRange, 0, len(byName))
+	for _, z := range byName {
+		zones = append(zones, *z)
+	}
+	sort.Slice(zones, func(i, j int) bool {
+		return zones[i].loc.String() < zones[j].loc.String()
+	})
+	return zones
+}
+
+// writeTimezone emits a VTIMEZONE with the explicit offset transitions of loc
+// around the given span. Each transition is listed as its own STANDARD or
+// DAYLIGHT observance, which is exact for the exported events.
+func writeTimezone(lw *lineWriter, loc *time.Location, from, to time.Time) {
+	from = from.AddDate(-1, 0, 0)
+	to = to.AddDate(1, 0, 0)
+
+	lw.line("BEGIN:VTIMEZONE")
+	lw.line("TZID:" + loc.String())
+
+	t := from.In(loc)
+	name, offset := t.Zone()
+	start, end := t.ZoneBounds()
+	prevOffset := offset
+	if !start.IsZero() {
+		// Offset in effect before the first observance we emit
+		_, prevOffset = start.Add(-time.Second).In(loc).Zone()
+	}
+	onset := start
+	if onset.IsZero() {
+		onset = time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+		prevOffset = offset
+	}
+	writeObservance(lw, t.IsDST(), name, onset, prevOffset, offset)
+
+	for !end.IsZero() && end.Before(to) {
+		t = end.In(loc)
+		prevOffset = offset
+		name, offset = t.Zone()
+		writeObservance(lw, t.IsDST(), name, end, prevOffset, offset)
+		_, end = t.ZoneBounds()
+	}
+
+	lw.line("END:VTIMEZONE")
+}
+
+func writeObservance(lw *lineWriter, dst bool, name string, onset time.Time, fromOffset, toOffset int) {
+	kind := "STANDARD"
+	if dst {
+		kind = "DAYLIGHT"
+	}
+	// DTSTART is the local time of the onset, expressed in the prior offset
+	local := onset.In(time.FixedZone("", fromOffset))
+
+	lw.line("BEGIN:" + kind)
+	lw.line("DTSTART:" + local.Format(dateTimeFormat))
+	lw.line("TZOFFSETFROM:" + formatOffset(fromOffset))
+	lw.line("TZOFFSETTO:" + formatOffset(toOffset))
+	if name != "" && !strings.HasPrefix(name, "+") && !strings.HasPrefix(name, "-") {
+		lw.line("TZNAME:" + name)
+	}
+	lw.line("END:" + kind)
+}
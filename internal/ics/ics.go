@@ -0,0 +1,210 @@
+// Package ics reads and writes RFC 5545 iCalendar data, and writes vCards,
+// which share its content line format.
+package ics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is a calendar event in iCalendar terms
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	URL          string
+	Start        time.Time // In the event's time zone; midnight for all-day events
+	End          time.Time // Exclusive
+	AllDay       bool
+	RRule        string   // RRULE value without the "RRULE:" prefix
+	ExDates      []string // Raw EXDATE values (kept for reporting)
+	RecurrenceID string   // Set on overridden occurrences
+	Status       string   // CONFIRMED, TENTATIVE, CANCELLED
+	Transparent  bool     // TRANSP:TRANSPARENT (does not block time)
+	Class        string   // PUBLIC, PRIVATE, CONFIDENTIAL
+	Organizer    *Attendee
+	Attendees    []Attendee
+	Alarms       []int // Minutes before start
+	Created      time.Time
+	LastModified time.Time
+	Sequence     int
+}
+
+// Attendee is an ATTENDEE or ORGANIZER property
+type Attendee struct {
+	Name     string
+	Email    string // Set when the address is a mailto: URI
+	URI      string // Full calendar address
+	PartStat string // NEEDS-ACTION, ACCEPTED, DECLINED, TENTATIVE
+	Optional bool   // ROLE=OPT-PARTICIPANT
+	CUType   string // INDIVIDUAL, GROUP, RESOURCE, ROOM
+}
+
+const (
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405"
+	utcFormat      = "20060102T150405Z"
+)
+
+// escapeText escapes a TEXT value
+func escapeText(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// unescapeText reverses escapeText
+func unescapeText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n', 'N':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// formatOffset formats a UTC offset in seconds as ±HHMM[SS]
+func formatOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	h, m, s := offset/3600, (offset%3600)/60, offset%60
+	if s != 0 {
+		return fmt.Sprintf("%s%02d%02d%02d", sign, h, m, s)
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, h, m)
+}
+
+// parseOffset parses a ±HHMM[SS] UTC offset into seconds
+func parseOffset(s string) (int, error) {
+	if len(s) != 5 && len(s) != 7 {
+		return 0, fmt.Errorf("invalid UTC offset: %s", s)
+	}
+	sign := 1
+	switch s[0] {
+	case '+':
+	case '-':
+		sign = -1
+	default:
+		return 0, fmt.Errorf("invalid UTC offset: %s", s)
+	}
+	h, err1 := strconv.Atoi(s[1:3])
+	m, err2 := strconv.Atoi(s[3:5])
+	sec := 0
+	var err3 error
+	if len(s) == 7 {
+		sec, err3 = strconv.Atoi(s[5:7])
+	}
+	if err1 != nil || err2 != nil || err3 != nil {
+		return 0, fmt.Errorf("invalid UTC offset: %s", s)
+	}
+	return sign * (h*3600 + m*60 + sec), nil
+}
+
+// FormatDuration formats a duration as an RFC 5545 dur-value (e.g. -PT15M)
+func FormatDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	if d == 0 {
+		return "PT0S"
+	}
+
+	var b strings.Builder
+	b.WriteString(sign + "P")
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	if days > 0 {
+		if days%7 == 0 && d == 0 {
+			return fmt.Sprintf("%sP%dW", sign, days/7)
+		}
+		fmt.Fprintf(&b, "%dD", days)
+	}
+	if d > 0 {
+		b.WriteString("T")
+		h := d / time.Hour
+		d -= h * time.Hour
+		m := d / time.Minute
+		d -= m * time.Minute
+		s := d / time.Second
+		if h > 0 {
+			fmt.Fprintf(&b, "%dH", h)
+		}
+		if m > 0 {
+			fmt.Fprintf(&b, "%dM", m)
+		}
+		if s > 0 {
+			fmt.Fprintf(&b, "%dS", s)
+		}
+	}
+	return b.String()
+}
+
+// ParseDuration parses an RFC 5545 dur-value such as "PT1H30M", "-P1D" or "P2W"
+func ParseDuration(s string) (time.Duration, error) {
+	orig := s
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign = -1
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") {
+		return 0, fmt.Errorf("invalid duration: %s", orig)
+	}
+	s = s[1:]
+
+	var d time.Duration
+	inTime := false
+	num := ""
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			num += string(c)
+			continue
+		case c == 'T':
+			inTime = true
+			continue
+		}
+		if num == "" {
+			return 0, fmt.Errorf("invalid duration: %s", orig)
+		}
+		n, _ := strconv.Atoi(num)
+		num = ""
+		switch {
+		case c == 'W' && !inTime:
+			d += time.Duration(n) * 7 * 24 * time.Hour
+		case c == 'D' && !inTime:
+			d += time.Duration(n) * 24 * time.Hour
+		case c == 'H' && inTime:
+			d += time.Duration(n) * time.Hour
+		case c == 'M' && inTime:
+			d += time.Duration(n) * time.Minute
+		case c == 'S' && inTime:
+			d += time.Duration(n) * time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration: %s", orig)
+		}
+	}
+	if num != "" {
+		return 0, fmt.Errorf("invalid duration: %s", orig)
+	}
+	return sign * d, nil
+}
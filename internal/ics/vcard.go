@@ -0,0 +1,55 @@
+package ics
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// Card is a contact in vCard terms
+type Card struct {
+	UID    string
+	Name   string
+	Email  string
+	Mobile string
+	Title  string
+	Org    []string // Organization, then units from the top down
+	City   string
+	Note   string
+}
+
+// EncodeCards writes contacts as vCard 3.0 (RFC 2426), the version phones
+// import most reliably
+func EncodeCards(w io.Writer, cards []Card) error {
+	lw := &lineWriter{w: bufio.NewWriter(w)}
+
+	for _, c := range cards {
+		lw.line("BEGIN:VCARD")
+		lw.line("VERSION:3.0")
+		// Names aren't split into given and family names; phones show the
+		// whole name either way
+		lw.line("N:" + escapeText(c.Name) + ";;;;")
+		lw.line("FN:" + escapeText(c.Name))
+		lw.prop("EMAIL;TYPE=INTERNET,WORK", escapeText(c.Email))
+		lw.prop("TEL;TYPE=CELL", escapeText(c.Mobile))
+		lw.prop("TITLE", escapeText(c.Title))
+		if len(c.Org) > 0 {
+			units := make([]string, len(c.Org))
+			for i, u := range c.Org {
+				units[i] = escapeText(u)
+			}
+			lw.line("ORG:" + strings.Join(units, ";"))
+		}
+		if c.City != "" {
+			lw.line("ADR;TYPE=WORK:;;;" + escapeText(c.City) + ";;;")
+		}
+		lw.prop("NOTE", escapeText(c.Note))
+		lw.prop("UID", escapeText(c.UID))
+		lw.line("END:VCARD")
+	}
+
+	if lw.err != nil {
+		return lw.err
+	}
+	return lw.w.Flush()
+}
@@ -1,16 +1,56 @@
 package output
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
+	"regexp"
+	"time"
 )
 
+// displayLocation, when set, is the zone timestamps are re-rendered in
+var displayLocation *time.Location
+
+// timestampRe matches a JSON string holding only an RFC 3339 timestamp. The
+// leading group keeps escaped quotes inside longer strings from matching.
+var timestampRe = regexp.MustCompile(`(^|[^\\])"(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:\d{2}))"`)
+
+// SetTimezone makes JSON and Line render every RFC 3339 timestamp in loc
+func SetTimezone(loc *time.Location) {
+	displayLocation = loc
+}
+
 // JSON outputs data as JSON to stdout
 func JSON(v interface{}) {
-	enc := json.NewEncoder(os.Stdout)
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
 	enc.SetIndent("", "  ")
 	enc.Encode(v)
+	os.Stdout.Write(localize(enrich(buf.Bytes(), true)))
+}
+
+// Line outputs data as a single line of JSON, for NDJSON streams
+func Line(v interface{}) {
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(v)
+	os.Stdout.Write(localize(enrich(buf.Bytes(), false)))
+}
+
+// localize re-renders timestamps in encoded JSON in the display zone
+func localize(data []byte) []byte {
+	if displayLocation == nil {
+		return data
+	}
+	return timestampRe.ReplaceAllFunc(data, func(m []byte) []byte {
+		sub := timestampRe.FindSubmatch(m)
+		t, err := time.Parse(time.RFC3339Nano, string(sub[2]))
+		if err != nil {
+			return m
+		}
+		out := append([]byte{}, sub[1]...)
+		return append(out, `"`+t.In(displayLocation).Format(time.RFC3339Nano)+`"`...)
+	})
 }
 
 // Error outputs an error in JSON format
@@ -0,0 +1,245 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// NameResolver maps open_ids to display names, leaving out unknown IDs
+type NameResolver func(openIDs []string) map[string]string
+
+// nameResolver, when set, adds names next to open_ids in JSON output
+var nameResolver NameResolver
+
+// openIDRe matches a bare open_id
+var openIDRe = regexp.MustCompile(`^ou_[0-9a-zA-Z]+$`)
+
+// SetNameResolver makes JSON and Line add a name beside every open_id: a
+// "name" for open_id, user_id and id keys, "<x>_name" for "<x>_id" and
+// "<x>_open_id" keys, and "<x>_names" for "<x>_ids" arrays. Existing keys
+// are never overwritten.
+func SetNameResolver(r NameResolver) {
+	nameResolver = r
+}
+
+// object is a JSON object that keeps its key order
+type object struct {
+	keys []string
+	vals []interface{}
+}
+
+// enrich adds names to encoded JSON, re-encoding it the way JSON (indent)
+// or Line would
+func enrich(data []byte, indent bool) []byte {
+	if nameResolver == nil || !bytes.Contains(data, []byte(`"ou_`)) {
+		return data
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	v, err := decodeValue(dec)
+	if err != nil {
+		return data
+	}
+
+	var ids []string
+	collectOpenIDs(v, &ids)
+	if len(ids) == 0 {
+		return data
+	}
+	names := nameResolver(ids)
+	if len(names) == 0 {
+		return data
+	}
+	addNames(v, names)
+
+	var compact bytes.Buffer
+	if err := encodeValue(&compact, v); err != nil {
+		return data
+	}
+	if !indent {
+		compact.WriteByte('\n')
+		return compact.Bytes()
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, compact.Bytes(), "", "  "); err != nil {
+		return data
+	}
+	out.WriteByte('\n')
+	return out.Bytes()
+}
+
+func decodeValue(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			obj := &object{}
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				val, err := decodeValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				obj.keys = append(obj.keys, key.(string))
+				obj.vals = append(obj.vals, val)
+			}
+			_, err := dec.Token() // }
+			return obj, err
+		case '[':
+			arr := []interface{}{}
+			for dec.More() {
+				val, err := decodeValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, val)
+			}
+			_, err := dec.Token() // ]
+			return arr, err
+		}
+	}
+	return tok, nil
+}
+
+func encodeValue(buf *bytes.Buffer, v interface{}) error {
+	switch t := v.(type) {
+	case *object:
+		buf.WriteByte('{')
+		for i, k := range t.keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(k)
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := encodeValue(buf, t.vals[i]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, e := range t {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeValue(buf, e); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case json.Number:
+		buf.WriteString(t.String())
+	default:
+		b, err := json.Marshal(t)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+	}
+	return nil
+}
+
+// nameKey returns the key a name for key's value goes under, or ""
+func nameKey(key string, plural bool) string {
+	if plural {
+		if base, ok := strings.CutSuffix(key, "_ids"); ok && base != "" {
+			return base + "_names"
+		}
+		return ""
+	}
+	switch key {
+	case "open_id", "user_id", "id":
+		return "name"
+	}
+	for _, suffix := range []string{"_open_id", "_user_id", "_id"} {
+		if base, ok := strings.CutSuffix(key, suffix); ok && base != "" {
+			return base + "_name"
+		}
+	}
+	return ""
+}
+
+func isOpenID(v interface{}) bool {
+	s, ok := v.(string)
+	return ok && openIDRe.MatchString(s)
+}
+
+func collectOpenIDs(v interface{}, ids *[]string) {
+	switch t := v.(type) {
+	case *object:
+		for _, val := range t.vals {
+			collectOpenIDs(val, ids)
+		}
+	case []interface{}:
+		for _, e := range t {
+			collectOpenIDs(e, ids)
+		}
+	case string:
+		if openIDRe.MatchString(t) {
+			*ids = append(*ids, t)
+		}
+	}
+}
+
+func addNames(v interface{}, names map[string]string) {
+	switch t := v.(type) {
+	case *object:
+		for _, val := range t.vals {
+			addNames(val, names)
+		}
+		for i := 0; i < len(t.keys); i++ {
+			var name interface{}
+			var key string
+			switch val := t.vals[i].(type) {
+			case string:
+				if n, ok := names[val]; ok && isOpenID(val) {
+					key, name = nameKey(t.keys[i], false), n
+				}
+			case []interface{}:
+				if len(val) == 0 || !isOpenID(val[0]) {
+					continue
+				}
+				list := make([]interface{}, len(val))
+				for j, e := range val {
+					s, _ := e.(string)
+					list[j] = names[s]
+				}
+				key, name = nameKey(t.keys[i], true), list
+			}
+			if key == "" || t.has(key) {
+				continue
+			}
+			t.insert(i+1, key, name)
+			i++
+		}
+	case []interface{}:
+		for _, e := range t {
+			addNames(e, names)
+		}
+	}
+}
+
+func (o *object) has(key string) bool {
+	for _, k := range o.keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *object) insert(at int, key string, val interface{}) {
+	o.keys = append(o.keys[:at], append([]string{key}, o.keys[at:]...)...)
+	o.vals = append(o.vals[:at], append([]interface{}{val}, o.vals[at:]...)...)
+}
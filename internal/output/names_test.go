@@ -0,0 +1,40 @@
+package output
+
+import "testing"
+
+func TestEnrich(t *testing.T) {
+	defer SetNameResolver(nil)
+
+	data := []byte(`{"organizer_id":"ou_a1","attendee_ids":["ou_a1","ou_b2"],"count":2}`)
+
+	tests := []struct {
+		name     string
+		resolver NameResolver
+		want     string
+	}{
+		{
+			name:     "no resolver",
+			resolver: nil,
+			want:     string(data),
+		},
+		{
+			name:     "nothing cached",
+			resolver: func([]string) map[string]string { return nil },
+			want:     string(data),
+		},
+		{
+			name:     "cached names are added",
+			resolver: func([]string) map[string]string { return map[string]string{"ou_a1": "Alice"} },
+			want:     `{"organizer_id":"ou_a1","organizer_name":"Alice","attendee_ids":["ou_a1","ou_b2"],"attendee_names":["Alice",""],"count":2}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetNameResolver(tt.resolver)
+			if got := string(enrich(data, false)); got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+// Package parallel runs bounded pools of concurrent API calls.
+package parallel
+
+import "sync"
+
+// Run calls fn for 0..n-1 with at most workers calls in flight
+func Run(n, workers int, fn func(i int)) {
+	if workers < 1 {
+		workers = 1
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, workers)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// Do is Run for calls that can fail. Every call still runs; the first error
+// (by index) is returned.
+func Do(n, workers int, fn func(i int) error) error {
+	errs := make([]error, n)
+	Run(n, workers, func(i int) {
+		errs[i] = fn(i)
+	})
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package scheduler
+
+import "time"
+
+// Load is how many participants can't make one slot
+type Load struct {
+	Start    time.Time
+	End      time.Time
+	Busy     int // Participants with a busy period in the slot
+	OffHours int // Free, but outside their working hours
+}
+
+// SlotLoad counts the participants who are busy, or free but outside working
+// hours, between start and end. Working hours are ignored unless they end
+// after they start.
+func SlotLoad(participants []Participant, start, end time.Time, opts Options) Load {
+	load := Load{Start: start, End: end}
+	for _, p := range participants {
+		switch {
+		case overlapsAny(p.Busy, start, end):
+			load.Busy++
+		case !withinWorkingHours(start, end, p, opts):
+			load.OffHours++
+		}
+	}
+	return load
+}
@@ -0,0 +1,147 @@
+package scheduler
+
+import (
+	"sort"
+	"time"
+
+	timex "github.com/yjwong/lark-cli/internal/time"
+)
+
+// FocusOptions configures focus block planning
+type FocusOptions struct {
+	Target       time.Duration // Total focus time wanted
+	MinBlock     time.Duration
+	MaxBlock     time.Duration
+	KeepFree     time.Duration // Free time left open each day for flexible meetings
+	Buffer       time.Duration // Gap kept around existing meetings
+	WorkingHours timex.WorkingHours
+	Now          time.Time // Blocks are never placed before this
+}
+
+// PlanFocus picks focus blocks in free working time on working days between from
+// and to until opts.Target is reached. Blocks are spread across days, one per
+// day per round, using the longest gap each time. Days are taken in the
+// working hours' zone.
+func PlanFocus(busy []Interval, from, to time.Time, opts FocusOptions) []Interval {
+	if opts.MaxBlock <= 0 {
+		opts.MaxBlock = opts.Target
+	}
+	if opts.Target < opts.MinBlock || opts.MinBlock <= 0 {
+		return nil
+	}
+
+	sorted := append([]Interval(nil), busy...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	earliest := from
+	if earliest.Before(opts.Now) {
+		earliest = opts.Now
+	}
+
+	type day struct {
+		gaps   []Interval
+		budget time.Duration // Focus time this day may still take
+	}
+	wh := opts.WorkingHours
+	if wh.Location == nil {
+		wh.Location = from.Location()
+	}
+	local := from.In(wh.Location)
+	var days []*day
+	for d := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, wh.Location); d.Before(to); d = d.AddDate(0, 0, 1) {
+		start, end, ok := wh.Window(d)
+		if !ok {
+			continue
+		}
+		if start.Before(earliest) {
+			start = earliest
+		}
+		if end.After(to) {
+			end = to
+		}
+		if !end.After(start) {
+			continue
+		}
+
+		gaps := freeGaps(sorted, start, end, opts.Buffer)
+		var free time.Duration
+		for _, g := range gaps {
+			free += g.End.Sub(g.Start)
+		}
+		days = append(days, &day{gaps: gaps, budget: free - opts.KeepFree})
+	}
+
+	var blocks []Interval
+	remaining := opts.Target
+	for progress := true; progress && remaining >= opts.MinBlock; {
+		progress = false
+		for _, d := range days {
+			if remaining < opts.MinBlock {
+				break
+			}
+
+			// Longest gap left in the day
+			best := -1
+			for i, g := range d.gaps {
+				if best < 0 || g.End.Sub(g.Start) > d.gaps[best].End.Sub(d.gaps[best].Start) {
+					best = i
+				}
+			}
+			if best < 0 {
+				continue
+			}
+			gap := d.gaps[best]
+
+			length := gap.End.Sub(gap.Start)
+			for _, limit := range []time.Duration{opts.MaxBlock, remaining, d.budget} {
+				if limit < length {
+					length = limit
+				}
+			}
+			if length < opts.MinBlock {
+				continue
+			}
+
+			block := Interval{Start: gap.Start, End: gap.Start.Add(length)}
+			blocks = append(blocks, block)
+			remaining -= length
+			d.budget -= length
+			progress = true
+
+			// What's left of the gap, kept apart from the new block
+			rest := Interval{Start: block.End.Add(opts.Buffer), End: gap.End}
+			if rest.End.After(rest.Start) {
+				d.gaps[best] = rest
+			} else {
+				d.gaps = append(d.gaps[:best], d.gaps[best+1:]...)
+			}
+		}
+	}
+
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].Start.Before(blocks[j].Start) })
+	return blocks
+}
+
+// freeGaps returns the periods between start and end not covered by busy
+// (sorted by start), keeping buffer clear around each busy period
+func freeGaps(busy []Interval, start, end time.Time, buffer time.Duration) []Interval {
+	var gaps []Interval
+	cursor := start
+	for _, b := range busy {
+		bStart, bEnd := b.Start.Add(-buffer), b.End.Add(buffer)
+		if !bEnd.After(cursor) {
+			continue
+		}
+		if !bStart.Before(end) {
+			break
+		}
+		if bStart.After(cursor) {
+			gaps = append(gaps, Interval{Start: cursor, End: bStart})
+		}
+		cursor = bEnd
+	}
+	if end.After(cursor) {
+		gaps = append(gaps, Interval{Start: cursor, End: end})
+	}
+	return gaps
+}
@@ -0,0 +1,218 @@
+// Package scheduler proposes meeting slots from the busy times of attendees.
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yjwong/lark-cli/internal/conflicts"
+	timex "github.com/yjwong/lark-cli/internal/time"
+)
+
+// Score adjustments applied to each candidate slot
+const (
+	baseScore               = 100
+	optionalBusyPenalty     = 15
+	optionalOffHoursPenalty = 10
+	requiredBufferPenalty   = 5
+	optionalBufferPenalty   = 2
+	perDayPenalty           = 2
+)
+
+// Interval is a busy period
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Participant is an attendee whose availability is known
+type Participant struct {
+	ID       string
+	Name     string
+	Optional bool
+	Location *time.Location // Used for working hours
+	Busy     []Interval
+	DaysOff  map[string]bool // Holidays as YYYY-MM-DD in Location
+}
+
+// Options configures slot search
+type Options struct {
+	Duration      time.Duration
+	Step          time.Duration      // Spacing between candidate start times
+	BufferMinutes int                // Desired gap to neighbouring meetings
+	WorkingHours  timex.WorkingHours // Hours and days in each participant's zone
+	Limit         int
+	Now           time.Time // Candidates before this are skipped
+}
+
+// Slot is a ranked candidate meeting time
+type Slot struct {
+	Start   time.Time
+	End     time.Time
+	Score   int
+	Reasons []string
+}
+
+// Find returns up to opts.Limit non-overlapping slots between from and to
+// where every required participant is free and within working hours, ranked
+// best first. Optional participants and buffers only affect the score.
+func Find(participants []Participant, from, to time.Time, opts Options) []Slot {
+	if opts.Step <= 0 {
+		opts.Step = 30 * time.Minute
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = 5
+	}
+
+	start := from
+	if start.Before(opts.Now) {
+		start = opts.Now
+	}
+	// Align candidates to the step within the organiser's day
+	dayStart := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	if rem := start.Sub(dayStart) % opts.Step; rem != 0 {
+		start = start.Add(opts.Step - rem)
+	}
+
+	var candidates []Slot
+	for t := start; !t.Add(opts.Duration).After(to); t = t.Add(opts.Step) {
+		if slot, ok := evaluate(participants, t, t.Add(opts.Duration), from, opts); ok {
+			candidates = append(candidates, slot)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].Start.Before(candidates[j].Start)
+	})
+
+	// Pick the best slots that don't overlap each other
+	var picked []Slot
+	for _, c := range candidates {
+		overlaps := false
+		for _, p := range picked {
+			if c.Start.Before(p.End) && p.Start.Before(c.End) {
+				overlaps = true
+				break
+			}
+		}
+		if overlaps {
+			continue
+		}
+		picked = append(picked, c)
+		if len(picked) == opts.Limit {
+			break
+		}
+	}
+	return picked
+}
+
+// evaluate scores a single candidate. It reports false when a required
+// participant is busy or outside working hours.
+func evaluate(participants []Participant, start, end, from time.Time, opts Options) (Slot, bool) {
+	slot := Slot{Start: start, End: end, Score: baseScore}
+
+	var required int
+	var optionalBusy, optionalOffHours, backToBack []string
+	for _, p := range participants {
+		busy := overlapsAny(p.Busy, start, end)
+		inHours := withinWorkingHours(start, end, p, opts)
+
+		if !p.Optional {
+			required++
+			if busy || !inHours {
+				return Slot{}, false
+			}
+		} else if busy {
+			optionalBusy = append(optionalBusy, p.Name)
+			slot.Score -= optionalBusyPenalty
+			continue
+		} else if !inHours {
+			optionalOffHours = append(optionalOffHours, p.Name)
+			slot.Score -= optionalOffHoursPenalty
+		}
+
+		if opts.BufferMinutes > 0 && insufficientBuffer(p.Busy, start, end, opts.BufferMinutes) {
+			backToBack = append(backToBack, p.Name)
+			if p.Optional {
+				slot.Score -= optionalBufferPenalty
+			} else {
+				slot.Score -= requiredBufferPenalty
+			}
+		}
+	}
+
+	// Prefer sooner slots
+	days := int(start.Sub(from).Hours() / 24)
+	slot.Score -= days * perDayPenalty
+
+	if required > 0 {
+		slot.Reasons = append(slot.Reasons, fmt.Sprintf("all %d required attendee(s) free within working hours", required))
+	}
+	if len(optionalBusy) > 0 {
+		slot.Reasons = append(slot.Reasons, "optional attendee(s) busy: "+strings.Join(optionalBusy, ", "))
+	} else if len(participants) > required {
+		slot.Reasons = append(slot.Reasons, "all optional attendees free")
+	}
+	if len(optionalOffHours) > 0 {
+		slot.Reasons = append(slot.Reasons, "outside working hours for optional attendee(s): "+strings.Join(optionalOffHours, ", "))
+	}
+	if opts.BufferMinutes > 0 {
+		if len(backToBack) > 0 {
+			slot.Reasons = append(slot.Reasons, fmt.Sprintf("less than %dm buffer for: %s", opts.BufferMinutes, strings.Join(backToBack, ", ")))
+		} else {
+			slot.Reasons = append(slot.Reasons, fmt.Sprintf("at least %dm buffer around other meetings", opts.BufferMinutes))
+		}
+	}
+	if days == 0 {
+		slot.Reasons = append(slot.Reasons, "earliest day in range")
+	}
+
+	return slot, true
+}
+
+func overlapsAny(busy []Interval, start, end time.Time) bool {
+	for _, b := range busy {
+		if b.Start.Before(end) && start.Before(b.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// insufficientBuffer uses the conflict detector to check whether the slot
+// sits closer than bufferMinutes to any busy period
+func insufficientBuffer(busy []Interval, start, end time.Time, bufferMinutes int) bool {
+	const candidateID = "candidate"
+
+	slots := []conflicts.EventTimeSlot{{ID: candidateID, Start: start, End: end}}
+	for i, b := range busy {
+		slots = append(slots, conflicts.EventTimeSlot{ID: fmt.Sprintf("busy-%d", i), Start: b.Start, End: b.End})
+	}
+
+	result := conflicts.Detect(slots, conflicts.Options{BufferMinutes: bufferMinutes})
+	for _, c := range result.Conflicts {
+		if c.Type == "insufficient_buffer" && (c.EventIDs[0] == candidateID || c.EventIDs[1] == candidateID) {
+			return true
+		}
+	}
+	return false
+}
+
+// withinWorkingHours reports whether the slot falls on one of the
+// participant's working days, inside working hours in their zone
+func withinWorkingHours(start, end time.Time, p Participant, opts Options) bool {
+	wh := opts.WorkingHours
+	if wh.End <= wh.Start {
+		return true
+	}
+	wh.Location, wh.Holidays = p.Location, p.DaysOff
+	if wh.Location == nil {
+		wh.Location = start.Location()
+	}
+	return wh.Contains(start, end)
+}
@@ -0,0 +1,379 @@
+// Package stats computes meeting-load statistics for a set of events.
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	timex "github.com/yjwong/lark-cli/internal/time"
+)
+
+// Person is another attendee of a meeting
+type Person struct {
+	ID   string
+	Name string
+}
+
+// Meeting is a timed event on the user's calendar
+type Meeting struct {
+	ID         string
+	Summary    string
+	Start      time.Time
+	End        time.Time
+	Recurring  bool
+	Organizer  bool   // The user organizes it
+	RsvpStatus string // The user's RSVP; empty for events without invitations
+	Attendees  []Person
+}
+
+// Options configures the report
+type Options struct {
+	From         time.Time
+	To           time.Time
+	WorkingHours timex.WorkingHours // Days are bucketed in its zone
+	FocusMin     time.Duration      // Shortest gap counted as a focus block
+	TopN         int                // Number of co-attendees to list
+}
+
+// Report is the meeting-load report
+type Report struct {
+	From              string             `json:"from"`
+	To                string             `json:"to"`
+	Meetings          int                `json:"meetings"`
+	MeetingHours      float64            `json:"meeting_hours"`
+	BusyHours         float64            `json:"busy_hours"` // Overlapping meetings counted once
+	HoursPerDay       []DayHours         `json:"hours_per_day"`
+	HoursPerWeekday   map[string]float64 `json:"hours_per_weekday"`
+	FocusBlocks       []FocusBlock       `json:"focus_blocks"`
+	FocusHours        float64            `json:"focus_hours"`
+	TopCoAttendees    []CoAttendee       `json:"top_co_attendees"`
+	Recurring         Share              `json:"recurring"`
+	AdHoc             Share              `json:"ad_hoc"`
+	Organized         Share              `json:"organized"`
+	Attended          Share              `json:"attended"`
+	PendingRsvps      []PendingRsvp      `json:"pending_rsvps"`
+	PendingRsvpsCount int                `json:"pending_rsvps_count"`
+}
+
+// DayHours is the meeting time on one day
+type DayHours struct {
+	Date     string  `json:"date"`
+	Weekday  string  `json:"weekday"`
+	Meetings int     `json:"meetings"`
+	Hours    float64 `json:"hours"`
+}
+
+// FocusBlock is a meeting-free stretch of working hours
+type FocusBlock struct {
+	Start string  `json:"start"`
+	End   string  `json:"end"`
+	Hours float64 `json:"hours"`
+}
+
+// CoAttendee is someone the user meets with
+type CoAttendee struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name,omitempty"`
+	Meetings int     `json:"meetings"`
+	Hours    float64 `json:"hours"`
+}
+
+// Share is a subset of meetings with its share of the total
+type Share struct {
+	Meetings int     `json:"meetings"`
+	Hours    float64 `json:"hours"`
+	Percent  float64 `json:"percent"` // Of meeting hours
+}
+
+// PendingRsvp is an invitation awaiting a response
+type PendingRsvp struct {
+	ID      string `json:"id"`
+	Summary string `json:"summary"`
+	Start   string `json:"start"`
+}
+
+// Compute builds the report. Meetings are clipped to the report range.
+func Compute(meetings []Meeting, opts Options) Report {
+	loc := location(opts)
+	report := Report{
+		From:            opts.From.Format(time.RFC3339),
+		To:              opts.To.Format(time.RFC3339),
+		HoursPerWeekday: make(map[string]float64),
+		FocusBlocks:     []FocusBlock{},
+		TopCoAttendees:  []CoAttendee{},
+		PendingRsvps:    []PendingRsvp{},
+	}
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		report.HoursPerWeekday[wd.String()] = 0
+	}
+
+	sort.SliceStable(meetings, func(i, j int) bool {
+		return meetings[i].Start.Before(meetings[j].Start)
+	})
+
+	days := make(map[string]*DayHours)
+	people := make(map[string]*CoAttendee)
+	var busy []interval
+	var recurring, adHoc, organized, attended Share
+
+	for _, m := range meetings {
+		start, end := clip(m.Start, m.End, opts.From, opts.To)
+		if !end.After(start) {
+			continue
+		}
+		hours := end.Sub(start).Hours()
+
+		report.Meetings++
+		report.MeetingHours += hours
+		busy = append(busy, interval{start, end})
+
+		// Split across days so overnight meetings count on both
+		for dayStart := startOfDay(start.In(loc)); dayStart.Before(end); dayStart = dayStart.AddDate(0, 0, 1) {
+			s, e := clip(start, end, dayStart, dayStart.AddDate(0, 0, 1))
+			if !e.After(s) {
+				continue
+			}
+			key := dayStart.Format("2006-01-02")
+			d, ok := days[key]
+			if !ok {
+				d = &DayHours{Date: key, Weekday: dayStart.Weekday().String()}
+				days[key] = d
+			}
+			if !s.After(start) {
+				d.Meetings++
+			}
+			d.Hours += e.Sub(s).Hours()
+			report.HoursPerWeekday[dayStart.Weekday().String()] += e.Sub(s).Hours()
+		}
+
+		if m.Recurring {
+			addShare(&recurring, hours)
+		} else {
+			addShare(&adHoc, hours)
+		}
+		if m.Organizer {
+			addShare(&organized, hours)
+		} else {
+			addShare(&attended, hours)
+		}
+
+		for _, p := range m.Attendees {
+			c, ok := people[p.ID]
+			if !ok {
+				c = &CoAttendee{ID: p.ID, Name: p.Name}
+				people[p.ID] = c
+			}
+			if c.Name == "" {
+				c.Name = p.Name
+			}
+			c.Meetings++
+			c.Hours += hours
+		}
+
+		if m.RsvpStatus == "needs_action" {
+			report.PendingRsvps = append(report.PendingRsvps, PendingRsvp{
+				ID:      m.ID,
+				Summary: m.Summary,
+				Start:   m.Start.In(loc).Format(time.RFC3339),
+			})
+		}
+	}
+
+	merged := merge(busy)
+	for _, b := range merged {
+		report.BusyHours += b.end.Sub(b.start).Hours()
+	}
+
+	for _, d := range days {
+		d.Hours = round(d.Hours)
+		report.HoursPerDay = append(report.HoursPerDay, *d)
+	}
+	sort.Slice(report.HoursPerDay, func(i, j int) bool {
+		return report.HoursPerDay[i].Date < report.HoursPerDay[j].Date
+	})
+	if report.HoursPerDay == nil {
+		report.HoursPerDay = []DayHours{}
+	}
+	for k, v := range report.HoursPerWeekday {
+		report.HoursPerWeekday[k] = round(v)
+	}
+
+	report.FocusBlocks = focusBlocks(merged, opts)
+	for _, f := range report.FocusBlocks {
+		report.FocusHours += f.Hours
+	}
+
+	for _, c := range people {
+		c.Hours = round(c.Hours)
+		report.TopCoAttendees = append(report.TopCoAttendees, *c)
+	}
+	sort.Slice(report.TopCoAttendees, func(i, j int) bool {
+		a, b := report.TopCoAttendees[i], report.TopCoAttendees[j]
+		if a.Meetings != b.Meetings {
+			return a.Meetings > b.Meetings
+		}
+		if a.Hours != b.Hours {
+			return a.Hours > b.Hours
+		}
+		return a.ID < b.ID
+	})
+	if opts.TopN > 0 && len(report.TopCoAttendees) > opts.TopN {
+		report.TopCoAttendees = report.TopCoAttendees[:opts.TopN]
+	}
+
+	report.Recurring = finishShare(recurring, report.MeetingHours)
+	report.AdHoc = finishShare(adHoc, report.MeetingHours)
+	report.Organized = finishShare(organized, report.MeetingHours)
+	report.Attended = finishShare(attended, report.MeetingHours)
+	report.PendingRsvpsCount = len(report.PendingRsvps)
+
+	report.MeetingHours = round(report.MeetingHours)
+	report.BusyHours = round(report.BusyHours)
+	report.FocusHours = round(report.FocusHours)
+	return report
+}
+
+// Markdown renders the report as a short summary
+func Markdown(r Report) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Meeting load: %s to %s\n\n", r.From[:10], r.To[:10])
+	fmt.Fprintf(&b, "- **Meetings:** %d (%.1f h, %.1f h busy)\n", r.Meetings, r.MeetingHours, r.BusyHours)
+	fmt.Fprintf(&b, "- **Focus time:** %.1f h in %d block(s)\n", r.FocusHours, len(r.FocusBlocks))
+	fmt.Fprintf(&b, "- **Recurring / ad-hoc:** %.0f%% / %.0f%%\n", r.Recurring.Percent, r.AdHoc.Percent)
+	fmt.Fprintf(&b, "- **Organized / attended:** %d / %d\n", r.Organized.Meetings, r.Attended.Meetings)
+	fmt.Fprintf(&b, "- **Pending RSVPs:** %d\n", r.PendingRsvpsCount)
+
+	if len(r.HoursPerDay) > 0 {
+		b.WriteString("\n## Hours per day\n\n| Date | Day | Meetings | Hours |\n|---|---|---|---|\n")
+		for _, d := range r.HoursPerDay {
+			fmt.Fprintf(&b, "| %s | %s | %d | %.1f |\n", d.Date, d.Weekday[:3], d.Meetings, d.Hours)
+		}
+	}
+
+	if len(r.TopCoAttendees) > 0 {
+		b.WriteString("\n## Top co-attendees\n\n| Name | Meetings | Hours |\n|---|---|---|\n")
+		for _, c := range r.TopCoAttendees {
+			name := c.Name
+			if name == "" {
+				name = c.ID
+			}
+			fmt.Fprintf(&b, "| %s | %d | %.1f |\n", name, c.Meetings, c.Hours)
+		}
+	}
+
+	if len(r.PendingRsvps) > 0 {
+		b.WriteString("\n## Pending RSVPs\n\n")
+		for _, p := range r.PendingRsvps {
+			fmt.Fprintf(&b, "- %s (%s)\n", p.Summary, p.Start)
+		}
+	}
+	return b.String()
+}
+
+type interval struct {
+	start time.Time
+	end   time.Time
+}
+
+// merge joins overlapping intervals; input must be sorted by start
+func merge(in []interval) []interval {
+	var out []interval
+	for _, iv := range in {
+		if n := len(out); n > 0 && !iv.start.After(out[n-1].end) {
+			if iv.end.After(out[n-1].end) {
+				out[n-1].end = iv.end
+			}
+			continue
+		}
+		out = append(out, iv)
+	}
+	return out
+}
+
+// focusBlocks finds meeting-free stretches of at least opts.FocusMin within
+// working hours on working days
+func focusBlocks(busy []interval, opts Options) []FocusBlock {
+	blocks := []FocusBlock{}
+	wh := opts.WorkingHours
+	if opts.FocusMin <= 0 || wh.End <= wh.Start {
+		return blocks
+	}
+	loc := location(opts)
+
+	for day := startOfDay(opts.From.In(loc)); day.Before(opts.To); day = day.AddDate(0, 0, 1) {
+		ws, we, ok := wh.Window(day)
+		if !ok {
+			continue
+		}
+		workStart, workEnd := clip(ws, we, opts.From, opts.To)
+		cursor := workStart
+		// A zero-length interval at the end of the day closes the last gap
+		stops := append(append([]interval{}, busy...), interval{workEnd, workEnd})
+		for _, b := range stops {
+			if !b.end.After(cursor) {
+				continue
+			}
+			gapEnd := b.start
+			if gapEnd.After(workEnd) {
+				gapEnd = workEnd
+			}
+			if gapEnd.Sub(cursor) >= opts.FocusMin {
+				blocks = append(blocks, FocusBlock{
+					Start: cursor.In(loc).Format(time.RFC3339),
+					End:   gapEnd.In(loc).Format(time.RFC3339),
+					Hours: round(gapEnd.Sub(cursor).Hours()),
+				})
+			}
+			if !b.end.Before(workEnd) {
+				break
+			}
+			if b.end.After(cursor) {
+				cursor = b.end
+			}
+		}
+	}
+	return blocks
+}
+
+func addShare(s *Share, hours float64) {
+	s.Meetings++
+	s.Hours += hours
+}
+
+func finishShare(s Share, total float64) Share {
+	if total > 0 {
+		s.Percent = round(s.Hours / total * 100)
+	}
+	s.Hours = round(s.Hours)
+	return s
+}
+
+func clip(start, end, from, to time.Time) (time.Time, time.Time) {
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	return start, end
+}
+
+// location is the working hours' zone, or the zone of the range without one
+func location(opts Options) *time.Location {
+	if opts.WorkingHours.Location != nil {
+		return opts.WorkingHours.Location
+	}
+	return opts.From.Location()
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// round keeps two decimal places
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
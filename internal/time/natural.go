@@ -0,0 +1,320 @@
+package timex
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockPattern matches a time of day: "9am", "9:30 pm", "15:00", "noon"
+const clockPattern = `(?:\d{1,2}(?::\d{2})?\s*[ap]\.?m\.?|\d{1,2}:\d{2}|noon|midday|midnight)`
+
+var (
+	trailingClockRe = regexp.MustCompile(`^(.*?)\s*\b(?:at\s+)?(` + clockPattern + `)$`)
+	leadingClockRe  = regexp.MustCompile(`^(?:at\s+)?(` + clockPattern + `)\s+(.+)$`)
+	clockRe         = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?\s*(?:([ap])\.?m\.?)?$`)
+	weekdayRe       = regexp.MustCompile(`^(?:(this|next|last|on)\s+)?([a-z]+)$`)
+	anchorRe        = regexp.MustCompile(`^(start|beginning|end)\s+of\s+(?:the\s+)?(day|today|tomorrow|week|this week|next week|last week|month|this month|next month|last month)$`)
+	offsetUnitRe    = regexp.MustCompile(`^(\d+|an?|one)\s*(minutes?|mins?|m|hours?|hrs?|hr|h|days?|d|weeks?|wks?|wk|w|months?|mo)$`)
+	durationWordRe  = regexp.MustCompile(`(\d+(?:\.\d+)?|an?|one|half\s+an?)\s*(days?|d|hours?|hrs?|hr|h|minutes?|mins?|min|m)\b`)
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// normalize lowercases input and collapses whitespace
+func normalize(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// parseNatural resolves a relative expression against now. hasTime reports
+// whether the result names a time of day rather than a whole day.
+func parseNatural(s string, now time.Time) (t time.Time, hasTime bool, ok bool) {
+	if s == "now" {
+		return now, true, true
+	}
+	if t, hasTime, ok := parseOffset(s, now); ok {
+		return t, hasTime, true
+	}
+	if t, hasTime, ok := parseAnchor(s, now); ok {
+		return t, hasTime, true
+	}
+	if day, ok := parseDay(s, now); ok {
+		return StartOfDay(day), false, true
+	}
+
+	// A day combined with a time of day, in either order
+	var dayPart, clockPart string
+	if m := trailingClockRe.FindStringSubmatch(s); m != nil {
+		dayPart, clockPart = m[1], m[2]
+	} else if m := leadingClockRe.FindStringSubmatch(s); m != nil {
+		clockPart, dayPart = m[1], m[2]
+	} else {
+		return time.Time{}, false, false
+	}
+
+	hour, minute, ok := parseClock(clockPart)
+	if !ok {
+		return time.Time{}, false, false
+	}
+	day := now
+	if dayPart != "" {
+		if day, ok = parseDay(dayPart, now); !ok {
+			return time.Time{}, false, false
+		}
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, now.Location()), true, true
+}
+
+// parseDay resolves an expression naming a single day
+func parseDay(s string, now time.Time) (time.Time, bool) {
+	switch s {
+	case "today":
+		return now, true
+	case "tomorrow", "tmr", "tmrw":
+		return now.AddDate(0, 0, 1), true
+	case "yesterday":
+		return now.AddDate(0, 0, -1), true
+	case "next week":
+		return StartOfWeek(now).AddDate(0, 0, 7), true
+	case "last week":
+		return StartOfWeek(now).AddDate(0, 0, -7), true
+	case "next month":
+		return time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, now.Location()), true
+	}
+
+	if t, err := time.ParseInLocation(dateLayout, s, now.Location()); err == nil {
+		return t, true
+	}
+
+	if m := weekdayRe.FindStringSubmatch(s); m != nil {
+		if wd, ok := weekdays[m[2]]; ok {
+			return resolveWeekday(now, m[1], wd), true
+		}
+	}
+
+	// "in 3 days", "+1w", "2 days ago"
+	if t, hasTime, ok := parseOffset(s, now); ok && !hasTime {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// resolveWeekday finds the day named by a weekday and optional modifier:
+// a bare weekday is the next one on or after today, "next" is strictly after
+// today, "last" strictly before, and "this" is within the current week
+func resolveWeekday(now time.Time, modifier string, wd time.Weekday) time.Time {
+	diff := (int(wd) - int(now.Weekday()) + 7) % 7
+	switch modifier {
+	case "next":
+		if diff == 0 {
+			diff = 7
+		}
+	case "last":
+		diff -= 7
+	case "this":
+		monday := StartOfWeek(now)
+		offset := (int(wd) + 6) % 7 // Days since Monday
+		return monday.AddDate(0, 0, offset)
+	}
+	return now.AddDate(0, 0, diff)
+}
+
+// parseAnchor handles "start of week", "end of month", "eod" and similar
+func parseAnchor(s string, now time.Time) (time.Time, bool, bool) {
+	switch s {
+	case "eod":
+		s = "end of day"
+	case "eow":
+		s = "end of week"
+	case "eom":
+		s = "end of month"
+	}
+
+	m := anchorRe.FindStringSubmatch(s)
+	if m == nil {
+		return time.Time{}, false, false
+	}
+	end := m[1] == "end"
+
+	var first, last time.Time
+	switch m[2] {
+	case "day", "today":
+		first, last = now, now
+	case "tomorrow":
+		first = now.AddDate(0, 0, 1)
+		last = first
+	case "week", "this week":
+		first, last = StartOfWeek(now), EndOfWeek(now)
+	case "next week":
+		first = StartOfWeek(now).AddDate(0, 0, 7)
+		last = EndOfWeek(first)
+	case "last week":
+		first = StartOfWeek(now).AddDate(0, 0, -7)
+		last = EndOfWeek(first)
+	case "month", "this month":
+		first = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		last = first.AddDate(0, 1, -1)
+	case "next month":
+		first = time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, now.Location())
+		last = first.AddDate(0, 1, -1)
+	case "last month":
+		first = time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, now.Location())
+		last = first.AddDate(0, 1, -1)
+	}
+
+	if end {
+		return EndOfDay(last), true, true
+	}
+	return StartOfDay(first), false, true
+}
+
+// parseOffset handles "+2h", "-30m", "in 3 days", "2 hours ago" and
+// "1 week from now". Offsets of whole days keep the current time of day but
+// report hasTime=false so callers can widen them to the full day.
+func parseOffset(s string, now time.Time) (time.Time, bool, bool) {
+	sign := 1
+	var span string
+	switch {
+	case strings.HasPrefix(s, "+"):
+		span = s[1:]
+	case strings.HasPrefix(s, "-"):
+		sign, span = -1, s[1:]
+	case strings.HasPrefix(s, "in "):
+		span = s[3:]
+	case strings.HasSuffix(s, " ago"):
+		sign, span = -1, strings.TrimSuffix(s, " ago")
+	case strings.HasSuffix(s, " from now"):
+		span = strings.TrimSuffix(s, " from now")
+	case strings.HasSuffix(s, " later"):
+		span = strings.TrimSuffix(s, " later")
+	default:
+		return time.Time{}, false, false
+	}
+	span = strings.TrimSpace(span)
+
+	if m := offsetUnitRe.FindStringSubmatch(span); m != nil {
+		n := wordCount(m[1]) * sign
+		unit := m[2]
+		switch {
+		case unit == "mo" || strings.HasPrefix(unit, "month"):
+			return now.AddDate(0, n, 0), false, true
+		case strings.HasPrefix(unit, "w"):
+			return now.AddDate(0, 0, 7*n), false, true
+		case strings.HasPrefix(unit, "d"):
+			return now.AddDate(0, 0, n), false, true
+		case strings.HasPrefix(unit, "h"):
+			return now.Add(time.Duration(n) * time.Hour), true, true
+		default:
+			return now.Add(time.Duration(n) * time.Minute), true, true
+		}
+	}
+
+	if d, err := ParseDuration(span); err == nil && d > 0 {
+		return now.Add(time.Duration(sign) * d), true, true
+	}
+	return time.Time{}, false, false
+}
+
+// parseClock converts a time of day to hour and minute
+func parseClock(s string) (int, int, bool) {
+	switch s {
+	case "noon", "midday":
+		return 12, 0, true
+	case "midnight":
+		return 0, 0, true
+	}
+
+	m := clockRe.FindStringSubmatch(strings.ReplaceAll(s, " ", ""))
+	if m == nil {
+		return 0, 0, false
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	if minute > 59 {
+		return 0, 0, false
+	}
+
+	switch m[3] {
+	case "a", "p":
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		hour %= 12
+		if m[3] == "p" {
+			hour += 12
+		}
+	case "":
+		if m[2] == "" || hour > 23 {
+			return 0, 0, false
+		}
+	}
+	return hour, minute, true
+}
+
+// parseDurationPhrase sums phrases like "1 hour 30 minutes" or "half an hour"
+func parseDurationPhrase(s string) (time.Duration, bool) {
+	matches := durationWordRe.FindAllStringSubmatchIndex(s, -1)
+	if matches == nil {
+		return 0, false
+	}
+
+	var total time.Duration
+	var rest strings.Builder
+	prev := 0
+	for _, m := range matches {
+		rest.WriteString(s[prev:m[0]])
+		prev = m[1]
+
+		amount := s[m[2]:m[3]]
+		var n float64
+		switch {
+		case strings.HasPrefix(amount, "half"):
+			n = 0.5
+		case amount == "a" || amount == "an" || amount == "one":
+			n = 1
+		default:
+			n, _ = strconv.ParseFloat(amount, 64)
+		}
+
+		unit := s[m[4]:m[5]]
+		switch {
+		case strings.HasPrefix(unit, "d"):
+			total += time.Duration(n * float64(24*time.Hour))
+		case strings.HasPrefix(unit, "h"):
+			total += time.Duration(n * float64(time.Hour))
+		default:
+			total += time.Duration(n * float64(time.Minute))
+		}
+	}
+	rest.WriteString(s[prev:])
+
+	// Only separators may remain
+	for _, word := range strings.Fields(strings.NewReplacer(",", " ").Replace(rest.String())) {
+		if word != "and" {
+			return 0, false
+		}
+	}
+	return total, total > 0
+}
+
+// wordCount converts "a", "an", "one" or a number to an integer
+func wordCount(s string) int {
+	switch s {
+	case "a", "an", "one":
+		return 1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,64 @@
+package timex
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAtNatural(t *testing.T) {
+	ny := mustLoad(t, "America/New_York")
+
+	// Wednesday; DST starts on Sunday 2026-03-08 at 02:00
+	wednesday := time.Date(2026, 3, 4, 10, 30, 0, 0, ny)
+	monday := time.Date(2026, 3, 9, 8, 0, 0, 0, ny)
+	saturday := time.Date(2026, 3, 7, 10, 30, 0, 0, ny)
+
+	tests := []struct {
+		name  string
+		input string
+		now   time.Time
+		want  string // RFC 3339, with the offset in effect
+	}{
+		{"day and time", "tomorrow 15:00", wednesday, "2026-03-05T15:00:00-05:00"},
+		{"time before day", "at 9am tomorrow", wednesday, "2026-03-05T09:00:00-05:00"},
+		{"next weekday with time", "next monday 9am", wednesday, "2026-03-09T09:00:00-04:00"},
+		{"bare weekday later this week", "friday", wednesday, "2026-03-06T00:00:00-05:00"},
+		{"bare weekday rolls over to next week", "tuesday", wednesday, "2026-03-10T00:00:00-04:00"},
+		{"bare weekday is today", "monday", monday, "2026-03-09T00:00:00-04:00"},
+		{"next weekday on that weekday is a week out", "next monday", monday, "2026-03-16T00:00:00-04:00"},
+		{"last weekday on that weekday is a week back", "last monday", monday, "2026-03-02T00:00:00-05:00"},
+		{"this weekday stays in the current week", "this monday", wednesday, "2026-03-02T00:00:00-05:00"},
+		{"end of week", "end of week", wednesday, "2026-03-08T23:59:59-04:00"},
+		{"start of next week", "start of next week", wednesday, "2026-03-09T00:00:00-04:00"},
+		{"end of month", "eom", wednesday, "2026-03-31T23:59:59-04:00"},
+		{"days from now keep the time of day", "in 3 days", wednesday, "2026-03-07T10:30:00-05:00"},
+		{"hours", "+2h", wednesday, "2026-03-04T12:30:00-05:00"},
+		{"ago", "90 minutes ago", wednesday, "2026-03-04T09:00:00-05:00"},
+		{"noon", "friday noon", wednesday, "2026-03-06T12:00:00-05:00"},
+		{"tomorrow across DST keeps the wall time", "tomorrow 10:30", saturday, "2026-03-08T10:30:00-04:00"},
+		{"days across DST keep the wall time", "in 1 day", saturday, "2026-03-08T10:30:00-04:00"},
+		{"hours across DST are elapsed time", "+24h", saturday, "2026-03-08T11:30:00-04:00"},
+		{"ISO input ignores now", "2026-01-05T09:00", wednesday, "2026-01-05T09:00:00-05:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAt(tt.input, ny, tt.now)
+			if err != nil {
+				t.Fatalf("ParseAt(%q): %v", tt.input, err)
+			}
+			if s := got.Format(time.RFC3339); s != tt.want {
+				t.Errorf("ParseAt(%q) = %s, want %s", tt.input, s, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAtInvalid(t *testing.T) {
+	now := time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)
+	for _, input := range []string{"", "someday", "next blursday", "25:00", "tomorrow 13pm"} {
+		if got, err := ParseAt(input, time.UTC, now); err == nil {
+			t.Errorf("ParseAt(%q) = %s, want an error", input, got)
+		}
+	}
+}
@@ -10,33 +10,66 @@ import (
 
 var durationRe = regexp.MustCompile(`^(\d+)(h|m|min|hr|hrs|mins|hours?|minutes?)$`)
 
-// Parse attempts to parse a time string in ISO 8601 / RFC3339 formats
+// Now is the clock used to resolve relative expressions such as "tomorrow"
+// or "+2h". Replace it to get deterministic results.
+var Now = time.Now
+
+// isoLayouts are the absolute formats accepted by Parse, most specific first
+var isoLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	dateLayout,
+}
+
+const dateLayout = "2006-01-02"
+
+// Parse parses a time in ISO 8601 / RFC3339 format, or a relative or natural
+// expression such as "now", "+2h", "tomorrow 15:00", "next monday 9am",
+// "end of week" or "in 3 days". Relative expressions are resolved against
+// Now() in tz.
 func Parse(input string, tz *time.Location) (time.Time, error) {
 	if tz == nil {
 		tz = time.Local
 	}
+	return ParseAt(input, tz, Now().In(tz))
+}
+
+// ParseAt is like Parse but resolves relative expressions against now
+func ParseAt(input string, tz *time.Location, now time.Time) (time.Time, error) {
+	if tz == nil {
+		tz = time.Local
+	}
+	t, _, err := parse(input, tz, now.In(tz))
+	return t, err
+}
+
+// HasTime reports whether input names a time of day or an exact instant
+// (e.g. "15:00", "tomorrow 9am", "+2h", "now") rather than just a day
+func HasTime(input string) bool {
+	_, hasTime, err := parse(input, time.UTC, Now().UTC())
+	return err == nil && hasTime
+}
 
+func parse(input string, tz *time.Location, now time.Time) (time.Time, bool, error) {
 	input = strings.TrimSpace(input)
 	if input == "" {
-		return time.Time{}, fmt.Errorf("empty time string")
+		return time.Time{}, false, fmt.Errorf("empty time string")
 	}
 
-	formats := []string{
-		time.RFC3339,
-		"2006-01-02T15:04:05",
-		"2006-01-02T15:04",
-		"2006-01-02 15:04:05",
-		"2006-01-02 15:04",
-		"2006-01-02",
+	for _, layout := range isoLayouts {
+		if t, err := time.ParseInLocation(layout, input, tz); err == nil {
+			return t, layout != dateLayout, nil
+		}
 	}
 
-	for _, format := range formats {
-		if t, err := time.ParseInLocation(format, input, tz); err == nil {
-			return t, nil
-		}
+	if t, hasTime, ok := parseNatural(normalize(input), now); ok {
+		return t, hasTime, nil
 	}
 
-	return time.Time{}, fmt.Errorf("unable to parse time: %s (use ISO 8601 format, e.g. 2006-01-02 or 2006-01-02T15:04:05)", input)
+	return time.Time{}, false, fmt.Errorf("unable to parse time: %s (use ISO 8601 like 2006-01-02 or 2006-01-02T15:04:05, or an expression like tomorrow 15:00, next monday 9am, +2h, in 3 days)", input)
 }
 
 // ParseDuration parses duration strings like "30m", "1h", "1h30m"
@@ -65,6 +98,11 @@ func ParseDuration(input string) (time.Duration, error) {
 		}
 	}
 
+	// Phrases like "1 hour 30 minutes", "90 mins" or "half an hour"
+	if d, ok := parseDurationPhrase(input); ok {
+		return d, nil
+	}
+
 	return 0, fmt.Errorf("unable to parse duration: %s", input)
 }
 
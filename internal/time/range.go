@@ -0,0 +1,152 @@
+package timex
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	nextRangeRe    = regexp.MustCompile(`^next\s+(\d+)\s+(working\s+days?|business\s+days?|weekdays?|days?|weeks?)$`)
+	weekdayRangeRe = regexp.MustCompile(`^([a-z]+)\s*(?:-|to|through|thru|until)\s*([a-z]+)$`)
+	rangeSepRe     = regexp.MustCompile(`^(?:from\s+|between\s+)?(.+?)\s+(?:to|until|till|through|thru|and|-)\s+(.+)$`)
+)
+
+// ParseRange parses a relative range such as "today", "this week",
+// "next 5 working days", "mon-fri" or "tomorrow 9am to friday" into a start
+// and end time. Relative ranges that include today start at now rather than
+// at midnight; an end without a time of day runs to the end of that day.
+// Working days are the days wh counts as workdays, so holidays are skipped.
+func ParseRange(input string, now time.Time, wh WorkingHours) (time.Time, time.Time, error) {
+	input = normalize(input)
+
+	switch input {
+	case "today":
+		return now, EndOfDay(now), nil
+	case "tomorrow":
+		t := now.AddDate(0, 0, 1)
+		return StartOfDay(t), EndOfDay(t), nil
+	case "this week":
+		return now, EndOfWeek(now), nil
+	case "next week":
+		t := StartOfWeek(now).AddDate(0, 0, 7)
+		return t, EndOfWeek(t), nil
+	case "this month":
+		t := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		return now, EndOfDay(t.AddDate(0, 1, -1)), nil
+	case "next month":
+		t := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, now.Location())
+		return t, EndOfDay(t.AddDate(0, 1, -1)), nil
+	}
+
+	if start, end, ok := parseWeekdayRange(input, now); ok {
+		return start, end, nil
+	}
+
+	m := nextRangeRe.FindStringSubmatch(input)
+	if m == nil {
+		return parseExplicitRange(input, now)
+	}
+	n, _ := strconv.Atoi(m[1])
+	if n < 1 {
+		return time.Time{}, time.Time{}, fmt.Errorf("range must cover at least one day: %s", input)
+	}
+
+	unit := m[2]
+	switch {
+	case strings.HasPrefix(unit, "week") && !strings.HasPrefix(unit, "weekday"):
+		return now, EndOfDay(now.AddDate(0, 0, 7*n-1)), nil
+	case strings.HasPrefix(unit, "day"):
+		return now, EndOfDay(now.AddDate(0, 0, n-1)), nil
+	}
+
+	// Working days: today counts if it is a workday
+	if wh.Days == [7]bool{} {
+		return time.Time{}, time.Time{}, fmt.Errorf("no working days configured: %s", input)
+	}
+	start := now
+	day := now
+	for !wh.IsWorkday(day) {
+		day = StartOfDay(day.AddDate(0, 0, 1))
+		start = day
+	}
+	for counted := 1; counted < n; {
+		day = day.AddDate(0, 0, 1)
+		if wh.IsWorkday(day) {
+			counted++
+		}
+	}
+	return start, EndOfDay(day), nil
+}
+
+// parseWeekdayRange handles "mon-fri" and "tuesday to thursday" within the
+// current week, or next week once the range has passed
+func parseWeekdayRange(input string, now time.Time) (time.Time, time.Time, bool) {
+	m := weekdayRangeRe.FindStringSubmatch(input)
+	if m == nil {
+		return time.Time{}, time.Time{}, false
+	}
+	from, ok1 := weekdays[m[1]]
+	to, ok2 := weekdays[m[2]]
+	if !ok1 || !ok2 {
+		return time.Time{}, time.Time{}, false
+	}
+
+	monday := StartOfWeek(now)
+	fromOffset := (int(from) + 6) % 7
+	toOffset := (int(to) + 6) % 7
+	if toOffset < fromOffset {
+		toOffset += 7
+	}
+	start := monday.AddDate(0, 0, fromOffset)
+	end := EndOfDay(monday.AddDate(0, 0, toOffset))
+	if end.Before(now) {
+		start = start.AddDate(0, 0, 7)
+		end = end.AddDate(0, 0, 7)
+	}
+	if start.Before(now) {
+		start = now
+	}
+	return start, end, true
+}
+
+// parseExplicitRange handles "<time> to <time>" and a single day such as
+// "friday" or "2026-01-05"
+func parseExplicitRange(input string, now time.Time) (time.Time, time.Time, error) {
+	rangeErr := fmt.Errorf("unable to parse range: %s (e.g. today, this week, next 5 working days, mon-fri, tomorrow 9am to friday)", input)
+	loc := now.Location()
+
+	if m := rangeSepRe.FindStringSubmatch(input); m != nil {
+		start, startHasTime, err1 := parse(m[1], loc, now)
+		end, endHasTime, err2 := parse(m[2], loc, now)
+		// A bare time of day ends on the start's day: "tomorrow 9am to 5pm"
+		if hour, minute, ok := parseClock(strings.TrimPrefix(m[2], "at ")); ok && err1 == nil {
+			end = time.Date(start.Year(), start.Month(), start.Day(), hour, minute, 0, 0, loc)
+			endHasTime, err2 = true, nil
+		}
+		if err1 == nil && err2 == nil {
+			if !startHasTime {
+				start = StartOfDay(start)
+			}
+			if !endHasTime {
+				end = EndOfDay(end)
+			}
+			// "monday to friday" on a Wednesday means next week's Friday
+			if _, isWeekday := weekdays[m[2]]; isWeekday && !end.After(start) {
+				end = end.AddDate(0, 0, 7)
+			}
+			if !end.After(start) {
+				return time.Time{}, time.Time{}, fmt.Errorf("range end must be after start: %s", input)
+			}
+			return start, end, nil
+		}
+	}
+
+	t, hasTime, err := parse(input, loc, now)
+	if err != nil || hasTime {
+		return time.Time{}, time.Time{}, rangeErr
+	}
+	return StartOfDay(t), EndOfDay(t), nil
+}
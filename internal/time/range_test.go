@@ -0,0 +1,63 @@
+package timex
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRange(t *testing.T) {
+	ny := mustLoad(t, "America/New_York")
+
+	// Thursday; DST starts on Sunday 2026-03-08
+	thursday := time.Date(2026, 3, 5, 10, 0, 0, 0, ny)
+	saturday := time.Date(2026, 3, 7, 10, 0, 0, 0, ny)
+
+	weekdays := DefaultWorkingHours(ny)
+	holiday := DefaultWorkingHours(ny)
+	holiday.AddHoliday(time.Date(2026, 3, 6, 0, 0, 0, 0, ny), time.Date(2026, 3, 7, 0, 0, 0, 0, ny))
+	monThu := DefaultWorkingHours(ny)
+	monThu.Days, _ = ParseWeekdays("mon-thu")
+
+	tests := []struct {
+		name  string
+		input string
+		now   time.Time
+		wh    WorkingHours
+		start string // RFC 3339
+		end   string
+	}{
+		{"today starts now", "today", thursday, weekdays, "2026-03-05T10:00:00-05:00", "2026-03-05T23:59:59-05:00"},
+		{"next week", "next week", thursday, weekdays, "2026-03-09T00:00:00-04:00", "2026-03-15T23:59:59-04:00"},
+		{"next days", "next 3 days", thursday, weekdays, "2026-03-05T10:00:00-05:00", "2026-03-07T23:59:59-05:00"},
+		{"working days skip the weekend", "next 5 working days", thursday, weekdays, "2026-03-05T10:00:00-05:00", "2026-03-11T23:59:59-04:00"},
+		{"working days skip holidays", "next 5 working days", thursday, holiday, "2026-03-05T10:00:00-05:00", "2026-03-12T23:59:59-04:00"},
+		{"working days follow the configured week", "next 5 working days", thursday, monThu, "2026-03-05T10:00:00-05:00", "2026-03-12T23:59:59-04:00"},
+		{"working days start on the next workday", "next 2 business days", saturday, weekdays, "2026-03-09T00:00:00-04:00", "2026-03-10T23:59:59-04:00"},
+		{"weekday range this week", "mon-fri", thursday, weekdays, "2026-03-05T10:00:00-05:00", "2026-03-06T23:59:59-05:00"},
+		{"weekday range rolls over once passed", "mon-wed", thursday, weekdays, "2026-03-09T00:00:00-04:00", "2026-03-11T23:59:59-04:00"},
+		{"bare end time is on the start day", "tomorrow 9am to 5pm", thursday, weekdays, "2026-03-06T09:00:00-05:00", "2026-03-06T17:00:00-05:00"},
+		{"explicit dates", "2026-03-09 - 2026-03-13", thursday, weekdays, "2026-03-09T00:00:00-04:00", "2026-03-13T23:59:59-04:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := ParseRange(tt.input, tt.now, tt.wh)
+			if err != nil {
+				t.Fatalf("ParseRange(%q): %v", tt.input, err)
+			}
+			if s := start.Format(time.RFC3339); s != tt.start {
+				t.Errorf("start = %s, want %s", s, tt.start)
+			}
+			if e := end.Format(time.RFC3339); e != tt.end {
+				t.Errorf("end = %s, want %s", e, tt.end)
+			}
+		})
+	}
+}
+
+func TestParseRangeNoWorkingDays(t *testing.T) {
+	now := time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC)
+	if _, _, err := ParseRange("next 5 working days", now, WorkingHours{Location: time.UTC}); err == nil {
+		t.Error("want an error when no working days are configured")
+	}
+}
@@ -0,0 +1,813 @@
+package timex
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the FREQ of a recurrence rule
+type Frequency int
+
+// Frequencies, from longest to shortest period
+const (
+	Yearly Frequency = iota
+	Monthly
+	Weekly
+	Daily
+	Hourly
+	Minutely
+	Secondly
+)
+
+var frequencyNames = []string{"YEARLY", "MONTHLY", "WEEKLY", "DAILY", "HOURLY", "MINUTELY", "SECONDLY"}
+
+func (f Frequency) String() string {
+	if f < 0 || int(f) >= len(frequencyNames) {
+		return "UNKNOWN"
+	}
+	return frequencyNames[f]
+}
+
+// WeekdayNum is a BYDAY entry such as MO, 2TU or -1FR. N is 0 for every
+// such weekday in the period.
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+var rruleDays = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+func (w WeekdayNum) String() string {
+	if w.N == 0 {
+		return rruleDays[w.Weekday]
+	}
+	return strconv.Itoa(w.N) + rruleDays[w.Weekday]
+}
+
+// RRule is a parsed RFC 5545 recurrence rule
+type RRule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int       // 0 when unset
+	Until      time.Time // Zero when unset
+	UntilDate  bool      // UNTIL was a date, so the whole day is included
+	BySecond   []int
+	ByMinute   []int
+	ByHour     []int
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByYearDay  []int
+	ByWeekNo   []int
+	ByMonth    []int
+	BySetPos   []int
+	WeekStart  time.Weekday
+}
+
+// ParseRRule parses an RRULE value such as "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10",
+// with or without the "RRULE:" prefix. A floating UNTIL (no Z) is read in loc.
+func ParseRRule(s string, loc *time.Location) (*RRule, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	s = strings.TrimSpace(s)
+	if len(s) > 6 && strings.EqualFold(s[:6], "RRULE:") {
+		s = s[6:]
+	}
+	if s == "" {
+		return nil, fmt.Errorf("empty RRULE")
+	}
+
+	r := &RRule{Freq: -1, Interval: 1, WeekStart: time.Monday}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return nil, fmt.Errorf("invalid RRULE part %q (use KEY=VALUE)", part)
+		}
+		key, value := strings.ToUpper(strings.TrimSpace(kv[0])), strings.ToUpper(strings.TrimSpace(kv[1]))
+		if seen[key] {
+			return nil, fmt.Errorf("RRULE has %s more than once", key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			r.Freq = -1
+			for i, name := range frequencyNames {
+				if value == name {
+					r.Freq = Frequency(i)
+				}
+			}
+			if r.Freq < 0 {
+				err = fmt.Errorf("unknown FREQ %s", value)
+			}
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+			if err == nil && r.Interval < 1 {
+				err = fmt.Errorf("INTERVAL must be positive")
+			}
+		case "COUNT":
+			r.Count, err = strconv.Atoi(value)
+			if err == nil && r.Count < 1 {
+				err = fmt.Errorf("COUNT must be positive")
+			}
+		case "UNTIL":
+			r.Until, r.UntilDate, err = ParseICalTime(value, loc)
+		case "BYSECOND":
+			r.BySecond, err = parseIntList(value, 0, 60, false)
+		case "BYMINUTE":
+			r.ByMinute, err = parseIntList(value, 0, 59, false)
+		case "BYHOUR":
+			r.ByHour, err = parseIntList(value, 0, 23, false)
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseIntList(value, 1, 31, true)
+		case "BYYEARDAY":
+			r.ByYearDay, err = parseIntList(value, 1, 366, true)
+		case "BYWEEKNO":
+			r.ByWeekNo, err = parseIntList(value, 1, 53, true)
+		case "BYMONTH":
+			r.ByMonth, err = parseIntList(value, 1, 12, false)
+		case "BYSETPOS":
+			r.BySetPos, err = parseIntList(value, 1, 366, true)
+		case "WKST":
+			var ok bool
+			if r.WeekStart, ok = parseRRuleDay(value); !ok {
+				err = fmt.Errorf("invalid WKST %s", value)
+			}
+		default:
+			err = fmt.Errorf("unsupported RRULE part %s", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid RRULE %s: %w", key, err)
+		}
+	}
+
+	if r.Freq < 0 {
+		return nil, fmt.Errorf("RRULE needs FREQ")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return nil, fmt.Errorf("RRULE can't have both COUNT and UNTIL")
+	}
+	if len(r.ByWeekNo) > 0 && r.Freq != Yearly {
+		return nil, fmt.Errorf("BYWEEKNO is only allowed with FREQ=YEARLY")
+	}
+	if len(r.ByYearDay) > 0 && (r.Freq == Monthly || r.Freq == Weekly || r.Freq == Daily) {
+		return nil, fmt.Errorf("BYYEARDAY is not allowed with FREQ=%s", r.Freq)
+	}
+	if len(r.ByMonthDay) > 0 && r.Freq == Weekly {
+		return nil, fmt.Errorf("BYMONTHDAY is not allowed with FREQ=WEEKLY")
+	}
+	for _, d := range r.ByDay {
+		if d.N != 0 && r.Freq != Monthly && r.Freq != Yearly {
+			return nil, fmt.Errorf("BYDAY %s: numbered weekdays need FREQ=MONTHLY or YEARLY", d)
+		}
+		if d.N != 0 && r.Freq == Yearly && len(r.ByWeekNo) > 0 {
+			return nil, fmt.Errorf("BYDAY %s: numbered weekdays can't be used with BYWEEKNO", d)
+		}
+	}
+	if len(r.BySetPos) > 0 && len(r.BySecond)+len(r.ByMinute)+len(r.ByHour)+len(r.ByDay)+
+		len(r.ByMonthDay)+len(r.ByYearDay)+len(r.ByWeekNo)+len(r.ByMonth) == 0 {
+		return nil, fmt.Errorf("BYSETPOS needs another BYxxx part")
+	}
+	return r, nil
+}
+
+// String formats the rule as an RRULE value, without the "RRULE:" prefix
+func (r *RRule) String() string {
+	parts := []string{"FREQ=" + r.Freq.String()}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		if r.UntilDate {
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+		} else {
+			parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+		}
+	}
+	ints := func(key string, values []int) {
+		if len(values) == 0 {
+			return
+		}
+		s := make([]string, len(values))
+		for i, v := range values {
+			s[i] = strconv.Itoa(v)
+		}
+		parts = append(parts, key+"="+strings.Join(s, ","))
+	}
+	ints("BYMONTH", r.ByMonth)
+	ints("BYWEEKNO", r.ByWeekNo)
+	ints("BYYEARDAY", r.ByYearDay)
+	ints("BYMONTHDAY", r.ByMonthDay)
+	if len(r.ByDay) > 0 {
+		s := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			s[i] = d.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(s, ","))
+	}
+	ints("BYHOUR", r.ByHour)
+	ints("BYMINUTE", r.ByMinute)
+	ints("BYSECOND", r.BySecond)
+	ints("BYSETPOS", r.BySetPos)
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+rruleDays[r.WeekStart])
+	}
+	return strings.Join(parts, ";")
+}
+
+// ParseICalTime parses an iCalendar DATE ("20260105") or DATE-TIME
+// ("20260105T090000" in loc, or "20260105T090000Z" in UTC). isDate reports a
+// DATE, returned as midnight in loc.
+func ParseICalTime(s string, loc *time.Location) (t time.Time, isDate bool, err error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	switch {
+	case len(s) == 8:
+		t, err = time.ParseInLocation("20060102", s, loc)
+		isDate = true
+	case strings.HasSuffix(s, "Z"):
+		t, err = time.Parse("20060102T150405Z", s)
+	default:
+		t, err = time.ParseInLocation("20060102T150405", s, loc)
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid date-time %q (use 20060102, 20060102T150405 or 20060102T150405Z)", s)
+	}
+	return t, isDate, nil
+}
+
+func parseIntList(s string, min, max int, signed bool) ([]int, error) {
+	var values []int
+	for _, field := range strings.Split(s, ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", field)
+		}
+		abs := v
+		if signed && v < 0 {
+			abs = -v
+		}
+		if abs < min || abs > max || (v < 0 && !signed) {
+			if signed {
+				return nil, fmt.Errorf("%d out of range (%d to %d, or negative)", v, min, max)
+			}
+			return nil, fmt.Errorf("%d out of range (%d to %d)", v, min, max)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+func parseByDay(s string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if len(field) < 2 {
+			return nil, fmt.Errorf("invalid weekday %q", field)
+		}
+		wd, ok := parseRRuleDay(field[len(field)-2:])
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", field)
+		}
+		n := 0
+		if prefix := field[:len(field)-2]; prefix != "" {
+			var err error
+			if n, err = strconv.Atoi(prefix); err != nil || n == 0 || n > 53 || n < -53 {
+				return nil, fmt.Errorf("invalid weekday %q", field)
+			}
+		}
+		days = append(days, WeekdayNum{Weekday: wd, N: n})
+	}
+	return days, nil
+}
+
+func parseRRuleDay(s string) (time.Weekday, bool) {
+	for i, d := range rruleDays {
+		if s == d {
+			return time.Weekday(i), true
+		}
+	}
+	return 0, false
+}
+
+// Recurrence is a recurrence set: the rules and extra dates of a recurring
+// event, anchored at its first start (DTSTART). Times are computed as wall
+// clock times in Start's location, so a 09:00 meeting stays at 09:00 across
+// DST changes.
+type Recurrence struct {
+	Start   time.Time
+	Rules   []*RRule
+	RDates  []time.Time
+	ExDates []time.Time
+	// ExDays are days excluded entirely (EXDATE;VALUE=DATE), as YYYY-MM-DD
+	// in Start's location
+	ExDays map[string]bool
+}
+
+// ParseRecurrence parses recurrence lines (RRULE, RDATE and EXDATE, one per
+// line) for a series starting at start. A line without a property name is
+// an RRULE. Floating times use TZID when given, else start's location.
+func ParseRecurrence(text string, start time.Time) (*Recurrence, error) {
+	rec := &Recurrence{Start: start}
+	loc := start.Location()
+	for _, line := range strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == '\r' }) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, params, value := splitICalLine(line)
+		switch name {
+		case "RRULE":
+			r, err := ParseRRule(value, loc)
+			if err != nil {
+				return nil, err
+			}
+			rec.Rules = append(rec.Rules, r)
+		case "RDATE", "EXDATE":
+			valueLoc := loc
+			if tzid := params["TZID"]; tzid != "" {
+				var err error
+				if valueLoc, err = LoadLocation(tzid); err != nil {
+					return nil, fmt.Errorf("%s: %w", name, err)
+				}
+			}
+			for _, v := range strings.Split(value, ",") {
+				t, isDate, err := ParseICalTime(strings.TrimSpace(v), valueLoc)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", name, err)
+				}
+				if err := rec.add(name, t, isDate); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unsupported recurrence property %s (use RRULE, RDATE or EXDATE)", name)
+		}
+	}
+	return rec, nil
+}
+
+// AddDate adds an RDATE or EXDATE given as an iCalendar value or any time
+// Parse accepts. A date without a time excludes (EXDATE) the whole day, or
+// adds (RDATE) an occurrence at the series' time of day.
+func (rec *Recurrence) AddDate(kind, value string) error {
+	loc := rec.Start.Location()
+	t, isDate, err := ParseICalTime(value, loc)
+	if err != nil {
+		var perr error
+		if t, perr = ParseAt(value, loc, rec.Start); perr != nil {
+			return fmt.Errorf("%s: %w", kind, perr)
+		}
+		isDate = !HasTime(value)
+	}
+	return rec.add(kind, t, isDate)
+}
+
+func (rec *Recurrence) add(kind string, t time.Time, isDate bool) error {
+	loc := rec.Start.Location()
+	switch kind {
+	case "RDATE":
+		if isDate {
+			s := rec.Start
+			t = time.Date(t.Year(), t.Month(), t.Day(), s.Hour(), s.Minute(), s.Second(), 0, loc)
+		}
+		rec.RDates = append(rec.RDates, t)
+	case "EXDATE":
+		if isDate {
+			if rec.ExDays == nil {
+				rec.ExDays = make(map[string]bool)
+			}
+			rec.ExDays[t.Format("2006-01-02")] = true
+		} else {
+			rec.ExDates = append(rec.ExDates, t)
+		}
+	default:
+		return fmt.Errorf("unknown recurrence date kind %s", kind)
+	}
+	return nil
+}
+
+// splitICalLine splits "NAME;PARAM=V:VALUE" into its parts. A line with no
+// recognised name is taken as an RRULE value.
+func splitICalLine(line string) (string, map[string]string, string) {
+	colon := strings.Index(line, ":")
+	if colon < 0 {
+		return "RRULE", nil, line
+	}
+	head := strings.Split(line[:colon], ";")
+	name := strings.ToUpper(head[0])
+	if strings.Contains(name, "=") {
+		return "RRULE", nil, line
+	}
+	params := make(map[string]string)
+	for _, p := range head[1:] {
+		if kv := strings.SplitN(p, "=", 2); len(kv) == 2 {
+			params[strings.ToUpper(kv[0])] = strings.Trim(kv[1], `"`)
+		}
+	}
+	return name, params, line[colon+1:]
+}
+
+// Between returns the occurrences starting from from to to (inclusive), in
+// order, stopping after limit occurrences when limit is positive. truncated
+// reports that more occurrences remain in the range.
+func (rec *Recurrence) Between(from, to time.Time, limit int) (occurrences []time.Time, truncated bool) {
+	seen := make(map[int64]bool)
+	var all []time.Time
+	add := func(t time.Time) {
+		if t.Before(from) || t.After(to) || seen[t.UnixNano()] || rec.excluded(t) {
+			return
+		}
+		seen[t.UnixNano()] = true
+		all = append(all, t)
+	}
+
+	// DTSTART is always the first occurrence
+	add(rec.Start)
+	for _, r := range rec.Rules {
+		// Each rule emits in order, so once it has passed the limit within
+		// the range its later occurrences can't be among the first
+		inRange := 0
+		r.expand(rec.Start, to, func(t time.Time) bool {
+			add(t)
+			if !t.Before(from) {
+				inRange++
+			}
+			return limit <= 0 || inRange <= limit
+		})
+	}
+	for _, t := range rec.RDates {
+		add(t.In(rec.Start.Location()))
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i].Before(all[j]) })
+	if limit > 0 && len(all) > limit {
+		return all[:limit], true
+	}
+	return all, false
+}
+
+// excluded reports whether an EXDATE removes t
+func (rec *Recurrence) excluded(t time.Time) bool {
+	if rec.ExDays[t.In(rec.Start.Location()).Format("2006-01-02")] {
+		return true
+	}
+	for _, ex := range rec.ExDates {
+		if ex.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// expand calls emit with each occurrence of the rule from start (exclusive)
+// up to end, honouring COUNT and UNTIL. COUNT includes start itself. emit
+// returns false to stop early. Periods are scanned until end, so a rule that
+// never matches (such as the 30th of February) still terminates.
+func (r *RRule) expand(start, end time.Time, emit func(time.Time) bool) {
+	loc := start.Location()
+	until := r.Until
+	if r.UntilDate {
+		// A date UNTIL includes the whole day
+		until = time.Date(until.Year(), until.Month(), until.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	count := 1 // start is the first occurrence
+
+	for period := 0; ; period++ {
+		periodStart, candidates := r.period(start, period)
+		if periodStart.After(end) || (!until.IsZero() && periodStart.After(until)) {
+			return
+		}
+		for _, t := range candidates {
+			if !t.After(start) {
+				continue
+			}
+			if !until.IsZero() && t.After(until) {
+				return
+			}
+			if r.Count > 0 && count >= r.Count {
+				return
+			}
+			count++
+			if t.After(end) || !emit(t) {
+				return
+			}
+		}
+	}
+}
+
+// period returns the start of the n-th period after start and the sorted
+// occurrences in it, after BYSETPOS
+func (r *RRule) period(start time.Time, n int) (time.Time, []time.Time) {
+	loc := start.Location()
+	step := n * r.Interval
+	var periodStart time.Time
+	var days []time.Time // Candidate days, for daily and longer periods
+	var times []time.Time
+
+	switch r.Freq {
+	case Yearly:
+		periodStart = time.Date(start.Year()+step, 1, 1, 0, 0, 0, 0, loc)
+		days = daysBetween(periodStart, periodStart.AddDate(1, 0, 0))
+	case Monthly:
+		periodStart = time.Date(start.Year(), start.Month()+time.Month(step), 1, 0, 0, 0, 0, loc)
+		days = daysBetween(periodStart, periodStart.AddDate(0, 1, 0))
+	case Weekly:
+		offset := (int(start.Weekday()) - int(r.WeekStart) + 7) % 7
+		periodStart = time.Date(start.Year(), start.Month(), start.Day()-offset+7*step, 0, 0, 0, 0, loc)
+		days = daysBetween(periodStart, periodStart.AddDate(0, 0, 7))
+	case Daily:
+		periodStart = time.Date(start.Year(), start.Month(), start.Day()+step, 0, 0, 0, 0, loc)
+		days = []time.Time{periodStart}
+	default:
+		// Sub-daily periods step in absolute time, so they keep their
+		// spacing across DST changes
+		unit := map[Frequency]time.Duration{Hourly: time.Hour, Minutely: time.Minute, Secondly: time.Second}[r.Freq]
+		periodStart = start.Add(time.Duration(step) * unit)
+		if !r.dayMatches(periodStart) {
+			return periodStart, nil
+		}
+		times = r.subDailyTimes(periodStart)
+	}
+
+	if days != nil {
+		for _, d := range r.filterDays(days, start) {
+			times = append(times, r.dayTimes(d, start)...)
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	// A time moved by a DST gap can land on another candidate
+	unique := times[:0]
+	for i, t := range times {
+		if i == 0 || !t.Equal(times[i-1]) {
+			unique = append(unique, t)
+		}
+	}
+	return periodStart, r.setPos(unique)
+}
+
+// filterDays applies BYMONTH, BYWEEKNO, BYYEARDAY, BYMONTHDAY and BYDAY to
+// the days of a period. Without any day rule, the day is taken from start.
+func (r *RRule) filterDays(days []time.Time, start time.Time) []time.Time {
+	noDayRule := len(r.ByWeekNo)+len(r.ByYearDay)+len(r.ByMonthDay)+len(r.ByDay) == 0
+	var out []time.Time
+	for _, d := range days {
+		if len(r.ByMonth) > 0 && !containsInt(r.ByMonth, int(d.Month())) {
+			continue
+		}
+		if noDayRule {
+			switch r.Freq {
+			case Yearly:
+				if d.Day() != start.Day() || (len(r.ByMonth) == 0 && d.Month() != start.Month()) {
+					continue
+				}
+			case Monthly:
+				if d.Day() != start.Day() {
+					continue
+				}
+			case Weekly:
+				if d.Weekday() != start.Weekday() {
+					continue
+				}
+			}
+		}
+		if len(r.ByWeekNo) > 0 {
+			if week, total := weekNumber(d, r.WeekStart); !matchesSigned(r.ByWeekNo, week, total) {
+				continue
+			}
+		}
+		if len(r.ByYearDay) > 0 && !matchesSigned(r.ByYearDay, d.YearDay(), daysIn(d.Year())) {
+			continue
+		}
+		if len(r.ByMonthDay) > 0 && !matchesSigned(r.ByMonthDay, d.Day(), daysInMonth(d)) {
+			continue
+		}
+		if len(r.ByDay) > 0 && !r.byDayMatches(d) {
+			continue
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// byDayMatches checks BYDAY for a day. Numbered weekdays count within the
+// month for MONTHLY rules and YEARLY rules with BYMONTH, else within the year.
+func (r *RRule) byDayMatches(d time.Time) bool {
+	for _, wd := range r.ByDay {
+		if d.Weekday() != wd.Weekday {
+			continue
+		}
+		if wd.N == 0 {
+			return true
+		}
+		var nth, total int
+		if r.Freq == Monthly || len(r.ByMonth) > 0 {
+			nth, total = (d.Day()-1)/7+1, (daysInMonth(d)-d.Day())/7+(d.Day()-1)/7+1
+		} else {
+			nth, total = (d.YearDay()-1)/7+1, (daysIn(d.Year())-d.YearDay())/7+(d.YearDay()-1)/7+1
+		}
+		if wd.N == nth || wd.N == nth-total-1 {
+			return true
+		}
+	}
+	return false
+}
+
+// dayMatches applies the day rules to a sub-daily occurrence
+func (r *RRule) dayMatches(t time.Time) bool {
+	if len(r.ByMonth) > 0 && !containsInt(r.ByMonth, int(t.Month())) {
+		return false
+	}
+	if len(r.ByYearDay) > 0 && !matchesSigned(r.ByYearDay, t.YearDay(), daysIn(t.Year())) {
+		return false
+	}
+	if len(r.ByMonthDay) > 0 && !matchesSigned(r.ByMonthDay, t.Day(), daysInMonth(t)) {
+		return false
+	}
+	if len(r.ByDay) > 0 && !r.byDayMatches(t) {
+		return false
+	}
+	return true
+}
+
+// dayTimes expands a day into occurrence times with BYHOUR, BYMINUTE and
+// BYSECOND, defaulting to the time of day of start
+func (r *RRule) dayTimes(d, start time.Time) []time.Time {
+	hours := orDefault(r.ByHour, start.Hour())
+	minutes := orDefault(r.ByMinute, start.Minute())
+	seconds := orDefault(r.BySecond, start.Second())
+	var times []time.Time
+	for _, h := range hours {
+		for _, m := range minutes {
+			for _, s := range seconds {
+				t := wallTime(d.Year(), d.Month(), d.Day(), h, m, s, d.Location())
+				// Drop a time moved onto another day by DST
+				if t.Day() == d.Day() {
+					times = append(times, t)
+				}
+			}
+		}
+	}
+	return times
+}
+
+// wallTime is time.Date with RFC 5545 handling of DST gaps: a wall time
+// that doesn't exist is read with the offset from before the gap, moving it
+// forward by the length of the gap (02:30 becomes 03:30). Ambiguous times
+// take the first occurrence.
+func wallTime(year int, month time.Month, day, hour, min, sec int, loc *time.Location) time.Time {
+	t := time.Date(year, month, day, hour, min, sec, 0, loc)
+	if t.Hour() == hour && t.Minute() == min {
+		return t
+	}
+	_, before := t.Add(-12 * time.Hour).Zone()
+	wall := time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+	return wall.Add(-time.Duration(before) * time.Second).In(loc)
+}
+
+// subDailyTimes expands or limits a sub-daily period with BYHOUR, BYMINUTE
+// and BYSECOND. Parts shorter than the period expand it; longer ones limit.
+func (r *RRule) subDailyTimes(p time.Time) []time.Time {
+	if len(r.ByHour) > 0 && !containsInt(r.ByHour, p.Hour()) {
+		return nil
+	}
+	minutes := []int{p.Minute()}
+	seconds := []int{p.Second()}
+	switch r.Freq {
+	case Hourly:
+		minutes = orDefault(r.ByMinute, p.Minute())
+		seconds = orDefault(r.BySecond, p.Second())
+	case Minutely:
+		if len(r.ByMinute) > 0 && !containsInt(r.ByMinute, p.Minute()) {
+			return nil
+		}
+		seconds = orDefault(r.BySecond, p.Second())
+	case Secondly:
+		if (len(r.ByMinute) > 0 && !containsInt(r.ByMinute, p.Minute())) ||
+			(len(r.BySecond) > 0 && !containsInt(r.BySecond, p.Second())) {
+			return nil
+		}
+	}
+	var times []time.Time
+	for _, m := range minutes {
+		for _, s := range seconds {
+			// Offsets within the period keep absolute spacing
+			times = append(times, p.Add(time.Duration(m-p.Minute())*time.Minute+time.Duration(s-p.Second())*time.Second))
+		}
+	}
+	return times
+}
+
+// setPos applies BYSETPOS to a period's sorted occurrences
+func (r *RRule) setPos(times []time.Time) []time.Time {
+	if len(r.BySetPos) == 0 {
+		return times
+	}
+	var out []time.Time
+	for i, t := range times {
+		if matchesSigned(r.BySetPos, i+1, len(times)) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// weekNumber returns the week of the year of d with weeks starting on wkst,
+// where week 1 is the first with at least four days in the year. Days
+// before week 1 belong to the last week of the previous year, and days in
+// next year's week 1 return 1. total is the number of weeks in that year.
+func weekNumber(d time.Time, wkst time.Weekday) (week, total int) {
+	year := d.Year()
+	first := firstWeekStart(year, wkst, d.Location())
+	if d.Before(first) {
+		year--
+		first = firstWeekStart(year, wkst, d.Location())
+	}
+	next := firstWeekStart(year+1, wkst, d.Location())
+	if !d.Before(next) {
+		return 1, weeksBetween(next, firstWeekStart(year+2, wkst, d.Location()))
+	}
+	return weeksBetween(first, d) + 1, weeksBetween(first, next)
+}
+
+// firstWeekStart returns the first day of week 1 of year
+func firstWeekStart(year int, wkst time.Weekday, loc *time.Location) time.Time {
+	jan1 := time.Date(year, 1, 1, 0, 0, 0, 0, loc)
+	offset := (int(jan1.Weekday()) - int(wkst) + 7) % 7
+	start := jan1.AddDate(0, 0, -offset)
+	if 7-offset < 4 {
+		start = start.AddDate(0, 0, 7)
+	}
+	return start
+}
+
+// weeksBetween counts whole weeks from a to b by calendar days, ignoring
+// DST
+func weeksBetween(a, b time.Time) int {
+	return civilDays(a, b) / 7
+}
+
+// civilDays counts calendar days from a to b
+func civilDays(a, b time.Time) int {
+	ua := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	ub := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(ub.Sub(ua).Hours() / 24)
+}
+
+// daysBetween lists the days from start up to (not including) end
+func daysBetween(start, end time.Time) []time.Time {
+	var days []time.Time
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		days = append(days, d)
+	}
+	return days
+}
+
+func daysIn(year int) int {
+	return time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
+
+func daysInMonth(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// matchesSigned reports whether value (1-based) is in list, where negative
+// entries count back from total
+func matchesSigned(list []int, value, total int) bool {
+	for _, v := range list {
+		if v == value || (v < 0 && total+v+1 == value) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(list []int, v int) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func orDefault(list []int, v int) []int {
+	if len(list) > 0 {
+		return list
+	}
+	return []int{v}
+}
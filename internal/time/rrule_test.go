@@ -0,0 +1,296 @@
+package timex
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const icalLocal = "20060102T150405"
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s not available: %v", name, err)
+	}
+	return loc
+}
+
+// Cases are the examples from RFC 5545 section 3.8.5.3 unless noted, with
+// DTSTART in America/New_York
+func TestRecurrenceBetween(t *testing.T) {
+	ny := mustLoad(t, "America/New_York")
+
+	tests := []struct {
+		name  string
+		start string
+		rules string
+		limit int
+		want  []string
+	}{
+		{
+			name:  "daily for 10 occurrences",
+			start: "19970902T090000",
+			rules: "RRULE:FREQ=DAILY;COUNT=10",
+			want: []string{
+				"19970902T090000", "19970903T090000", "19970904T090000", "19970905T090000", "19970906T090000",
+				"19970907T090000", "19970908T090000", "19970909T090000", "19970910T090000", "19970911T090000",
+			},
+		},
+		{
+			name:  "date UNTIL includes the whole day, same as COUNT=10",
+			start: "19970902T090000",
+			rules: "RRULE:FREQ=DAILY;UNTIL=19970911",
+			want: []string{
+				"19970902T090000", "19970903T090000", "19970904T090000", "19970905T090000", "19970906T090000",
+				"19970907T090000", "19970908T090000", "19970909T090000", "19970910T090000", "19970911T090000",
+			},
+		},
+		{
+			name:  "every other day",
+			start: "19970902T090000",
+			rules: "RRULE:FREQ=DAILY;INTERVAL=2",
+			limit: 4,
+			want:  []string{"19970902T090000", "19970904T090000", "19970906T090000", "19970908T090000"},
+		},
+		{
+			name:  "weekly on Tuesday and Thursday for five weeks, UNTIL",
+			start: "19970902T090000",
+			rules: "RRULE:FREQ=WEEKLY;UNTIL=19971007T000000Z;WKST=SU;BYDAY=TU,TH",
+			want: []string{
+				"19970902T090000", "19970904T090000", "19970909T090000", "19970911T090000", "19970916T090000",
+				"19970918T090000", "19970923T090000", "19970925T090000", "19970930T090000", "19971002T090000",
+			},
+		},
+		{
+			name:  "weekly on Tuesday and Thursday for five weeks, COUNT",
+			start: "19970902T090000",
+			rules: "RRULE:FREQ=WEEKLY;COUNT=10;WKST=SU;BYDAY=TU,TH",
+			want: []string{
+				"19970902T090000", "19970904T090000", "19970909T090000", "19970911T090000", "19970916T090000",
+				"19970918T090000", "19970923T090000", "19970925T090000", "19970930T090000", "19971002T090000",
+			},
+		},
+		{
+			name:  "every other week on Tuesday and Sunday, weeks from Monday",
+			start: "19970805T090000",
+			rules: "RRULE:FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=MO",
+			want:  []string{"19970805T090000", "19970810T090000", "19970819T090000", "19970824T090000"},
+		},
+		{
+			name:  "every other week on Tuesday and Sunday, weeks from Sunday",
+			start: "19970805T090000",
+			rules: "RRULE:FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=SU",
+			want:  []string{"19970805T090000", "19970817T090000", "19970819T090000", "19970831T090000"},
+		},
+		{
+			name:  "monthly on the first Friday",
+			start: "19970905T090000",
+			rules: "RRULE:FREQ=MONTHLY;COUNT=10;BYDAY=1FR",
+			want: []string{
+				"19970905T090000", "19971003T090000", "19971107T090000", "19971205T090000", "19980102T090000",
+				"19980206T090000", "19980306T090000", "19980403T090000", "19980501T090000", "19980605T090000",
+			},
+		},
+		{
+			name:  "every other month on the first and last Sunday",
+			start: "19970907T090000",
+			rules: "RRULE:FREQ=MONTHLY;INTERVAL=2;COUNT=10;BYDAY=1SU,-1SU",
+			want: []string{
+				"19970907T090000", "19970928T090000", "19971102T090000", "19971130T090000", "19980104T090000",
+				"19980125T090000", "19980301T090000", "19980329T090000", "19980503T090000", "19980531T090000",
+			},
+		},
+		{
+			name:  "monthly on the second-to-last Monday",
+			start: "19970922T090000",
+			rules: "RRULE:FREQ=MONTHLY;COUNT=6;BYDAY=-2MO",
+			want: []string{
+				"19970922T090000", "19971020T090000", "19971117T090000",
+				"19971222T090000", "19980119T090000", "19980216T090000",
+			},
+		},
+		{
+			name:  "yearly on the 20th Monday",
+			start: "19970519T090000",
+			rules: "RRULE:FREQ=YEARLY;BYDAY=20MO",
+			limit: 3,
+			want:  []string{"19970519T090000", "19980518T090000", "19990517T090000"},
+		},
+		{
+			name:  "Monday of ISO week 20",
+			start: "19970512T090000",
+			rules: "RRULE:FREQ=YEARLY;BYWEEKNO=20;BYDAY=MO",
+			limit: 3,
+			want:  []string{"19970512T090000", "19980511T090000", "19990517T090000"},
+		},
+		{
+			name:  "every Thursday in March",
+			start: "19970313T090000",
+			rules: "RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=TH",
+			limit: 7,
+			want: []string{
+				"19970313T090000", "19970320T090000", "19970327T090000",
+				"19980305T090000", "19980312T090000", "19980319T090000", "19980326T090000",
+			},
+		},
+		{
+			name:  "BYSETPOS: third Tuesday, Wednesday or Thursday of the month",
+			start: "19970904T090000",
+			rules: "RRULE:FREQ=MONTHLY;COUNT=3;BYDAY=TU,WE,TH;BYSETPOS=3",
+			want:  []string{"19970904T090000", "19971007T090000", "19971106T090000"},
+		},
+		{
+			name:  "BYSETPOS: second-to-last weekday of the month",
+			start: "19970929T090000",
+			rules: "RRULE:FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-2",
+			limit: 7,
+			want: []string{
+				"19970929T090000", "19971030T090000", "19971127T090000", "19971230T090000",
+				"19980129T090000", "19980226T090000", "19980330T090000",
+			},
+		},
+		{
+			name:  "EXDATE removes DTSTART: every Friday the 13th",
+			start: "19970902T090000",
+			rules: "EXDATE;TZID=America/New_York:19970902T090000\nRRULE:FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13",
+			limit: 5,
+			want:  []string{"19980213T090000", "19980313T090000", "19981113T090000", "19990813T090000", "20001013T090000"},
+		},
+		{
+			name:  "EXDATE removes an occurrence counted by COUNT",
+			start: "19970902T090000",
+			rules: "RRULE:FREQ=DAILY;COUNT=5\nEXDATE:19970904T090000",
+			want:  []string{"19970902T090000", "19970903T090000", "19970905T090000", "19970906T090000"},
+		},
+		{
+			name:  "EXDATE with a date removes the whole day",
+			start: "19970902T090000",
+			rules: "RRULE:FREQ=DAILY;COUNT=3\nEXDATE;VALUE=DATE:19970903",
+			want:  []string{"19970902T090000", "19970904T090000"},
+		},
+		{
+			name:  "RDATE adds an occurrence",
+			start: "19970902T090000",
+			rules: "RRULE:FREQ=WEEKLY;COUNT=2\nRDATE:19970905T140000",
+			want:  []string{"19970902T090000", "19970905T140000", "19970909T090000"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, err := time.ParseInLocation(icalLocal, tt.start, ny)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rec, err := ParseRecurrence(tt.rules, start)
+			if err != nil {
+				t.Fatalf("ParseRecurrence: %v", err)
+			}
+			got, _ := rec.Between(start, start.AddDate(5, 0, 0), tt.limit)
+			gotS := make([]string, len(got))
+			for i, g := range got {
+				gotS[i] = g.In(ny).Format(icalLocal)
+			}
+			if strings.Join(gotS, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("occurrences\n got %v\nwant %v", gotS, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecurrenceDST(t *testing.T) {
+	ny := mustLoad(t, "America/New_York")
+
+	tests := []struct {
+		name  string
+		start string
+		rule  string
+		want  []string // RFC 3339, with the offset in effect
+	}{
+		{
+			name:  "daily meeting keeps its wall time into EDT",
+			start: "20260307T090000",
+			rule:  "FREQ=DAILY;COUNT=3",
+			want:  []string{"2026-03-07T09:00:00-05:00", "2026-03-08T09:00:00-04:00", "2026-03-09T09:00:00-04:00"},
+		},
+		{
+			name:  "daily meeting keeps its wall time into EST",
+			start: "20261031T090000",
+			rule:  "FREQ=DAILY;COUNT=3",
+			want:  []string{"2026-10-31T09:00:00-04:00", "2026-11-01T09:00:00-05:00", "2026-11-02T09:00:00-05:00"},
+		},
+		{
+			name:  "a time in the spring gap moves forward by the gap",
+			start: "20260307T023000",
+			rule:  "FREQ=DAILY;COUNT=3",
+			want:  []string{"2026-03-07T02:30:00-05:00", "2026-03-08T03:30:00-04:00", "2026-03-09T02:30:00-04:00"},
+		},
+		{
+			name:  "weekly across the change stays on Monday 09:00",
+			start: "20260302T090000",
+			rule:  "FREQ=WEEKLY;BYDAY=MO;UNTIL=20260316T130000Z",
+			want:  []string{"2026-03-02T09:00:00-05:00", "2026-03-09T09:00:00-04:00", "2026-03-16T09:00:00-04:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, err := time.ParseInLocation(icalLocal, tt.start, ny)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rec, err := ParseRecurrence(tt.rule, start)
+			if err != nil {
+				t.Fatalf("ParseRecurrence: %v", err)
+			}
+			got, _ := rec.Between(start, start.AddDate(0, 1, 0), 0)
+			gotS := make([]string, len(got))
+			for i, g := range got {
+				gotS[i] = g.Format(time.RFC3339)
+			}
+			if strings.Join(gotS, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("occurrences\n got %v\nwant %v", gotS, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRRule(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string // Formatted back; empty when parsing should fail
+		wantErr string
+	}{
+		{in: "RRULE:FREQ=WEEKLY;INTERVAL=1;BYDAY=MO,WE", want: "FREQ=WEEKLY;BYDAY=MO,WE"},
+		{in: "freq=monthly;byday=-1fr;count=3", want: "FREQ=MONTHLY;COUNT=3;BYDAY=-1FR"},
+		{in: "FREQ=DAILY;UNTIL=20260105T090000Z", want: "FREQ=DAILY;UNTIL=20260105T090000Z"},
+		{in: "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1;WKST=SU", want: "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1;WKST=SU"},
+		{in: "FREQ=DAILY;COUNT=5;UNTIL=20260105", wantErr: "both COUNT and UNTIL"},
+		{in: "COUNT=5", wantErr: "needs FREQ"},
+		{in: "FREQ=WEEKLY;BYDAY=2MO", wantErr: "numbered weekdays"},
+		{in: "FREQ=DAILY;BYSETPOS=1", wantErr: "BYSETPOS needs"},
+		{in: "FREQ=MONTHLY;BYWEEKNO=1", wantErr: "BYWEEKNO"},
+		{in: "FREQ=FORTNIGHTLY", wantErr: "unknown FREQ"},
+		{in: "FREQ=DAILY;COUNT=0", wantErr: "COUNT must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			r, err := ParseRRule(tt.in, time.UTC)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseRRule(%q) error = %v, want %q", tt.in, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRRule(%q): %v", tt.in, err)
+			}
+			if got := r.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package timex
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dateKey is the layout of holiday dates
+const dateKey = "2006-01-02"
+
+// WorkingHours describes when someone works, in their own time zone
+type WorkingHours struct {
+	Start    time.Duration // Offset from midnight
+	End      time.Duration
+	Days     [7]bool         // Working weekdays, indexed by time.Weekday
+	Location *time.Location  // IANA zone the hours are in
+	Holidays map[string]bool // Days off as YYYY-MM-DD in Location
+}
+
+// DefaultWorkingHours is 09:00-18:00, Monday to Friday, in loc
+func DefaultWorkingHours(loc *time.Location) WorkingHours {
+	days, _ := ParseWeekdays("mon-fri")
+	return WorkingHours{
+		Start:    9 * time.Hour,
+		End:      18 * time.Hour,
+		Days:     days,
+		Location: loc,
+	}
+}
+
+// LoadLocation loads an IANA time zone such as "Europe/London". Unlike
+// time.LoadLocation it rejects the empty name and "Local", which would
+// silently use the machine's zone.
+func LoadLocation(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("time zone must be an IANA name like Asia/Singapore")
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q (use an IANA name like Asia/Singapore)", name)
+	}
+	return loc, nil
+}
+
+// ParseClockRange parses a range like "09:00-18:00" into offsets from midnight
+func ParseClockRange(s string) (time.Duration, time.Duration, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid working hours: %s (use HH:MM-HH:MM)", s)
+	}
+	var bounds [2]time.Duration
+	for i, part := range parts {
+		t, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid working hours: %s (use HH:MM-HH:MM)", s)
+		}
+		bounds[i] = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	if bounds[1] <= bounds[0] {
+		return 0, 0, fmt.Errorf("invalid working hours: %s (end must be after start)", s)
+	}
+	return bounds[0], bounds[1], nil
+}
+
+// FormatClockRange formats offsets from midnight as "09:00-18:00"
+func FormatClockRange(start, end time.Duration) string {
+	clock := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return clock(start) + "-" + clock(end)
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseWeekdays parses a day list like "mon-fri" or "sun-thu" or
+// "mon,tue,thu". Ranges may wrap around the end of the week.
+func ParseWeekdays(s string) ([7]bool, error) {
+	var days [7]bool
+	for _, part := range strings.Split(strings.ToLower(s), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		bounds := strings.SplitN(part, "-", 2)
+		from, ok := weekdayNames[abbrev(bounds[0])]
+		if !ok {
+			return days, fmt.Errorf("invalid weekday: %s (use mon, tue, ... or a range like mon-fri)", bounds[0])
+		}
+		to := from
+		if len(bounds) == 2 {
+			if to, ok = weekdayNames[abbrev(bounds[1])]; !ok {
+				return days, fmt.Errorf("invalid weekday: %s (use mon, tue, ... or a range like mon-fri)", bounds[1])
+			}
+		}
+		for d := from; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == to {
+				break
+			}
+		}
+	}
+	if days == ([7]bool{}) {
+		return days, fmt.Errorf("no working days given")
+	}
+	return days, nil
+}
+
+// abbrev shortens a weekday name to its first three letters
+func abbrev(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > 3 {
+		return s[:3]
+	}
+	return s
+}
+
+// IsWorkday reports whether the day of t, in the working hours' zone, is a
+// working weekday and not a holiday
+func (wh WorkingHours) IsWorkday(t time.Time) bool {
+	local := t.In(wh.location())
+	return wh.Days[local.Weekday()] && !wh.Holidays[local.Format(dateKey)]
+}
+
+// Window returns the working hours on the day of t. ok is false on days off.
+func (wh WorkingHours) Window(t time.Time) (start, end time.Time, ok bool) {
+	local := t.In(wh.location())
+	if !wh.IsWorkday(local) {
+		return time.Time{}, time.Time{}, false
+	}
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	return midnight.Add(wh.Start), midnight.Add(wh.End), true
+}
+
+// Contains reports whether start to end lies within working hours on a
+// working day
+func (wh WorkingHours) Contains(start, end time.Time) bool {
+	ws, we, ok := wh.Window(start)
+	return ok && !start.Before(ws) && !end.After(we)
+}
+
+// Clip returns the parts of start to end that fall within working hours,
+// in order
+func (wh WorkingHours) Clip(start, end time.Time) [][2]time.Time {
+	var parts [][2]time.Time
+	loc := wh.location()
+	s := start.In(loc)
+	for d := time.Date(s.Year(), s.Month(), s.Day(), 0, 0, 0, 0, loc); d.Before(end); d = d.AddDate(0, 0, 1) {
+		ws, we, ok := wh.Window(d)
+		if !ok {
+			continue
+		}
+		if ws.Before(start) {
+			ws = start
+		}
+		if we.After(end) {
+			we = end
+		}
+		if we.After(ws) {
+			parts = append(parts, [2]time.Time{ws, we})
+		}
+	}
+	return parts
+}
+
+// AddHoliday marks every day from start up to (not including) end as a day
+// off; at least the day of start is marked
+func (wh *WorkingHours) AddHoliday(start, end time.Time) {
+	if wh.Holidays == nil {
+		wh.Holidays = make(map[string]bool)
+	}
+	loc := wh.location()
+	s := start.In(loc)
+	for d := time.Date(s.Year(), s.Month(), s.Day(), 0, 0, 0, 0, loc); ; d = d.AddDate(0, 0, 1) {
+		wh.Holidays[d.Format(dateKey)] = true
+		if !d.AddDate(0, 0, 1).Before(end) {
+			break
+		}
+	}
+}
+
+func (wh WorkingHours) location() *time.Location {
+	if wh.Location == nil {
+		return time.Local
+	}
+	return wh.Location
+}